@@ -5,9 +5,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 
 	"github.com/sqlc-dev/sqlc/internal/codegen/json"
+	"github.com/sqlc-dev/sqlc/internal/ext"
+	"github.com/sqlc-dev/sqlc/internal/ext/process"
 	"github.com/sqlc-dev/sqlc/internal/plugin"
 )
 
@@ -19,6 +22,16 @@ func main() {
 }
 
 func run() error {
+	switch os.Getenv(process.EnvProtocol) {
+	case process.ProtocolStdio:
+		return process.ServeStdio(context.Background(), os.Stdin, os.Stdout, ext.HandleFunc(json.Generate))
+	case process.ProtocolGRPC:
+		lis, err := net.Listen("unix", os.Getenv(process.EnvSocket))
+		if err != nil {
+			return err
+		}
+		return process.ServeGRPC(lis, ext.HandleFunc(json.Generate))
+	}
 	var req plugin.CodeGenRequest
 	reqBlob, err := io.ReadAll(os.Stdin)
 	if err != nil {
@@ -0,0 +1,89 @@
+// Package apidiff compares the Go APIs generated for the queries of a package
+// at two revisions, and reports the changes that break their callers or the
+// payloads they share through the cache.
+package apidiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/golang"
+)
+
+// A Change is a breaking change to the generated method of a query.
+type Change struct {
+	Package string `json:"package"`
+	Query   string `json:"query"`
+	// Kind is "removed", "cmd", "params", "returns" or "cache key".
+	Kind string `json:"kind"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	if c.Kind == "removed" {
+		return fmt.Sprintf("%s.%s: removed", c.Package, c.Query)
+	}
+	return fmt.Sprintf("%s.%s: %s changed from %s to %s", c.Package, c.Query, c.Kind, c.Old, c.New)
+}
+
+// Compare returns the changes from the queries old of package pkg to the
+// queries new, in the order of old. Added queries are not changes. The cache
+// key of a query only matters if it was cached, since the keys of other
+// queries are never stored.
+func Compare(pkg string, old, new []golang.Signature) []Change {
+	current := map[string]golang.Signature{}
+	for _, sig := range new {
+		current[sig.Name] = sig
+	}
+	var changes []Change
+	add := func(query, kind, old, new string) {
+		if old != new {
+			changes = append(changes, Change{Package: pkg, Query: query, Kind: kind, Old: old, New: new})
+		}
+	}
+	for _, o := range old {
+		n, ok := current[o.Name]
+		if !ok {
+			changes = append(changes, Change{Package: pkg, Query: o.Name, Kind: "removed"})
+			continue
+		}
+		add(o.Name, "cmd", o.Cmd, n.Cmd)
+		add(o.Name, "params", params(o), params(n))
+		add(o.Name, "returns", returns(o), returns(n))
+		if o.Cache > 0 {
+			add(o.Name, "cache key", o.CacheKey, n.CacheKey)
+		}
+	}
+	return changes
+}
+
+// params describes the parameters of a query, with the fields of its params
+// struct.
+func params(sig golang.Signature) string {
+	args := make([]string, len(sig.Params))
+	for i, arg := range sig.Params {
+		args[i] = arg.Name + " " + arg.Type
+	}
+	return "(" + strings.Join(args, ", ") + ")" + fields(sig.ParamFields)
+}
+
+// returns describes the type returned per row, with its fields since the
+// cached payloads of a query are decoded into them.
+func returns(sig golang.Signature) string {
+	if sig.Ret == "" {
+		return "nothing"
+	}
+	return sig.Ret + fields(sig.RetFields)
+}
+
+func fields(fs []golang.Field) string {
+	if len(fs) == 0 {
+		return ""
+	}
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = f.Name + " " + f.Type
+	}
+	return "{" + strings.Join(out, "; ") + "}"
+}
@@ -0,0 +1,44 @@
+package apidiff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/golang"
+)
+
+func TestCompare(t *testing.T) {
+	old := []golang.Signature{
+		{Name: "GetBook", Cmd: ":one", Params: []golang.Argument{{Name: "id", Type: "int64"}}, Ret: "*Book",
+			RetFields: []golang.Field{{Name: "ID", Type: "int64"}, {Name: "Year", Type: "*int32"}},
+			Cache:     time.Minute, CacheKey: `"books:GetBook:" + hashIfLong(fmt.Sprintf("%+v",id))`},
+		{Name: "ListBooks", Cmd: ":many", Params: []golang.Argument{{Name: "title", Type: "string"}}, Ret: "string",
+			CacheKey: `"books:ListBooks:" + hashIfLong(fmt.Sprintf("%+v",title))`},
+		{Name: "DeleteBook", Cmd: ":exec"},
+	}
+	new := []golang.Signature{
+		{Name: "GetBook", Cmd: ":one", Params: []golang.Argument{{Name: "id", Type: "int32"}}, Ret: "*Book",
+			RetFields: []golang.Field{{Name: "ID", Type: "int64"}, {Name: "Year", Type: "int32"}},
+			Cache:     time.Minute, CacheKey: `"books:GetBook:" + hashIfLong(fmt.Sprintf("%+v",ptrStr(id)))`},
+		{Name: "ListBooks", Cmd: ":one", Params: []golang.Argument{{Name: "title", Type: "string"}}, Ret: "string",
+			CacheKey: `"books:ListBooks:" + hashIfLong(fmt.Sprintf("%+v",ptrStr(title)))`},
+		{Name: "CountBooks", Cmd: ":one", Ret: "int64"},
+	}
+	want := []Change{
+		{Package: "books", Query: "GetBook", Kind: "params", Old: "(id int64)", New: "(id int32)"},
+		{Package: "books", Query: "GetBook", Kind: "returns", Old: "*Book{ID int64; Year *int32}", New: "*Book{ID int64; Year int32}"},
+		{Package: "books", Query: "GetBook", Kind: "cache key",
+			Old: `"books:GetBook:" + hashIfLong(fmt.Sprintf("%+v",id))`,
+			New: `"books:GetBook:" + hashIfLong(fmt.Sprintf("%+v",ptrStr(id)))`},
+		{Package: "books", Query: "ListBooks", Kind: "cmd", Old: ":many", New: ":one"},
+		{Package: "books", Query: "DeleteBook", Kind: "removed"},
+	}
+	if diff := cmp.Diff(want, Compare("books", old, new)); diff != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", diff)
+	}
+	if changes := Compare("books", old, old); len(changes) != 0 {
+		t.Errorf("unchanged queries: got %v", changes)
+	}
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Root returns the directory sqlc caches data in. It defaults to
+// $XDG_CACHE_HOME/sqlc, or ~/.cache/sqlc, and can be set with SQLCCACHE.
+func Root() (string, error) {
+	cache := os.Getenv("SQLCCACHE")
+	if cache != "" {
+		return cache, nil
+	}
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(cacheHome, "sqlc"), nil
+}
+
+// Dir returns the named subdirectory of the cache root, creating it if
+// needed.
+func Dir(name string) (string, error) {
+	root, err := Root()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/apidiff"
+	"github.com/sqlc-dev/sqlc/internal/codegen/golang"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+)
+
+// Exit codes of sqlc api-diff, following the ones of sqlc diff.
+const (
+	apiDiffExitChanged = 1
+	apiDiffExitFailed  = 2
+)
+
+// errAPIChanged is returned by APIDiff when the generated API of a query
+// changed in a way that breaks its callers or its cache.
+var errAPIChanged = errors.New("breaking API changes found")
+
+var apiDiffCmd = &cobra.Command{
+	Use:   "api-diff",
+	Short: "Report breaking changes to the generated query APIs since a git revision",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defer trace.StartRegion(cmd.Context(), "api-diff").End()
+		stderr := cmd.ErrOrStderr()
+		dir, name := getConfigPath(stderr, cmd.Flag("file"))
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		o := APIDiffOptions{From: from, Format: format}
+		if err := APIDiff(cmd.Context(), dir, name, o, cmd.OutOrStdout(), stderr); err != nil {
+			if errors.Is(err, errAPIChanged) {
+				os.Exit(apiDiffExitChanged)
+			}
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			os.Exit(apiDiffExitFailed)
+		}
+		return nil
+	},
+}
+
+type APIDiffOptions struct {
+	// From is the git revision to compare with.
+	From string
+	// Format is either "text" (a line per change) or "json".
+	Format string
+}
+
+type apiDiffReport struct {
+	Changes []apidiff.Change `json:"changes"`
+}
+
+// APIDiff compiles the queries of each Go package at the git revision
+// o.From and in the working tree, and reports the queries that were removed
+// and the ones whose command, parameters, returned type or cache key
+// changed. Both revisions are compiled with the configuration of the working
+// tree; packages without queries at o.From are new and skipped.
+func APIDiff(ctx context.Context, dir, filename string, o APIDiffOptions, stdout, stderr io.Writer) error {
+	if o.From == "" {
+		return fmt.Errorf("--from is required")
+	}
+	if o.Format != "text" && o.Format != "json" {
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
+	_, conf, err := readConfig(stderr, dir, filename)
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(conf); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "sqlc-api-diff")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	report := apiDiffReport{Changes: []apidiff.Change{}}
+	for _, sql := range conf.SQL {
+		if sql.Gen.Go == nil {
+			continue
+		}
+		combo := config.Combine(*conf, sql)
+		queries, err := checkoutSchema(ctx, dir, o.From, sql.Queries, tmp)
+		if err != nil {
+			return err
+		}
+		if len(queries) == 0 {
+			continue
+		}
+		schema, err := checkoutSchema(ctx, dir, o.From, sql.Schema, tmp)
+		if err != nil {
+			return err
+		}
+		old, err := signatures(ctx, tmp, sql, combo, schema, queries, stderr)
+		if err != nil {
+			return fmt.Errorf("package %s at %s: %w", combo.Go.Package, o.From, err)
+		}
+
+		schema, queries = nil, nil
+		for _, s := range sql.Schema {
+			schema = append(schema, filepath.Join(dir, s))
+		}
+		for _, q := range sql.Queries {
+			queries = append(queries, filepath.Join(dir, q))
+		}
+		current, err := signatures(ctx, dir, sql, combo, schema, queries, stderr)
+		if err != nil {
+			return fmt.Errorf("package %s: %w", combo.Go.Package, err)
+		}
+		report.Changes = append(report.Changes, apidiff.Compare(combo.Go.Package, old, current)...)
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range report.Changes {
+			fmt.Fprintln(stdout, c)
+		}
+	}
+	if len(report.Changes) > 0 {
+		return errAPIChanged
+	}
+	return nil
+}
+
+// signatures compiles the schema and query files of a package and returns
+// the Go signatures of its queries.
+func signatures(ctx context.Context, dir string, sql config.SQL, combo config.CombinedSettings, schema, queries []string, stderr io.Writer) ([]golang.Signature, error) {
+	sql.Schema = schema
+	sql.Queries = queries
+	result, failed := parse(ctx, combo.Go.Package, dir, sql, combo, opts.Parser{}, nil, stderr)
+	if failed {
+		return nil, fmt.Errorf("compilation failed")
+	}
+	return golang.Signatures(codeGenRequest(result, combo))
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/apidiff"
+)
+
+const apiDiffTestQueries = `-- name: GetBook :one
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT * FROM books WHERE id = $1;
+
+-- name: ListBooks :many
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT * FROM books WHERE title = $1 AND year = $2;
+`
+
+func TestAPIDiffCacheKey(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFiles(t, dir, map[string]string{
+		"sqlc.yaml":        cacheTestConfig,
+		"books/schema.sql": "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL, year INT);\n",
+		"books/query.sql":  apiDiffTestQueries,
+	})
+	run("init", "-q")
+	run("add", "-A")
+	run("-c", "user.name=sqlc", "-c", "user.email=sqlc@example.com", "commit", "-q", "-m", "books")
+
+	// The year is no longer a pointer, which changes the encoding of the
+	// ListBooks key and the fingerprint of the results of both queries.
+	writeTestFiles(t, dir, map[string]string{
+		"books/schema.sql": "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL, year INT NOT NULL);\n",
+	})
+	var stdout, stderr bytes.Buffer
+	err := APIDiff(ctx, dir, "sqlc.yaml", APIDiffOptions{From: "HEAD", Format: "json"}, &stdout, &stderr)
+	if err != errAPIChanged {
+		t.Fatalf("api-diff: expected errAPIChanged, got %v\n%s", err, stderr.String())
+	}
+	var report apiDiffReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("%s\n%s", err, stdout.String())
+	}
+	keys := map[string]apidiff.Change{}
+	for _, c := range report.Changes {
+		if c.Kind == "cache key" {
+			keys[c.Query] = c
+		}
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected cache key changes of GetBook and ListBooks, got %+v", report.Changes)
+	}
+	if c := keys["ListBooks"]; !strings.Contains(c.Old, "ptrStr(arg.Year)") || strings.Contains(c.New, "ptrStr") {
+		t.Errorf("ListBooks: unexpected cache keys %q and %q", c.Old, c.New)
+	}
+
+	// The reported keys are the ones the generated code builds.
+	output, err := Generate(ctx, Env{}, dir, "sqlc.yaml", &stderr)
+	if err != nil {
+		t.Fatalf("generate: %s\n%s", err, stderr.String())
+	}
+	generated := output[filepath.Join(dir, "books", "query.sql.go")]
+	code := compactCode(generated)
+	if want := compactCode("GetWithTtl(qctx, " + keys["GetBook"].New + ","); !strings.Contains(code, want) {
+		t.Errorf("GetBook: generated code doesn't build the key %s:\n%s", keys["GetBook"].New, generated)
+	}
+	label, format, ok := strings.Cut(keys["ListBooks"].New, " + ")
+	if !ok {
+		t.Fatalf("ListBooks: unexpected cache key %q", keys["ListBooks"].New)
+	}
+	if want := compactCode("prefix := " + label + " return prefix + " + format); !strings.Contains(code, want) {
+		t.Errorf("ListBooks: generated code doesn't build the key %s:\n%s", keys["ListBooks"].New, generated)
+	}
+}
+
+// compactCode removes the white space of Go code, so that expressions are
+// compared regardless of gofmt.
+func compactCode(code string) string {
+	return strings.Join(strings.Fields(code), "")
+}
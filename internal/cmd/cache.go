@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+
+	"github.com/sqlc-dev/sqlc/internal/cache"
+	"github.com/sqlc-dev/sqlc/internal/info"
+	"github.com/sqlc-dev/sqlc/internal/sql/sqlpath"
+)
+
+// outputCache stores the files generated for each package on disk, keyed by
+// everything that can affect them, so unchanged packages are skipped.
+type outputCache struct {
+	dir    string
+	config []byte
+}
+
+type cachedOutput struct {
+	Out   string            `json:"out"`
+	Files map[string]string `json:"files"`
+}
+
+// newOutputCache opens the cache of the configuration at configPath, whose
+// fragments are part of the configuration in the keys.
+func newOutputCache(configPath string, fragments []string) (*outputCache, error) {
+	dir, err := cache.Dir("generate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	var config []byte
+	for _, path := range append([]string{configPath}, fragments...) {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		config = append(config, fmt.Sprintf("%d:", len(blob))...)
+		config = append(config, blob...)
+	}
+	return &outputCache{dir: dir, config: config}, nil
+}
+
+func writeHashed(h hash.Hash, s []byte) {
+	fmt.Fprintf(h, "%d:", len(s))
+	h.Write(s)
+}
+
+// key hashes the sqlc version, the configuration, the position of the
+// package in it and the contents of its schema and query files.
+func (c *outputCache) key(index int, pair outPair) (string, error) {
+	h := sha256.New()
+	writeHashed(h, []byte(info.Version))
+	writeHashed(h, c.config)
+	writeHashed(h, []byte(fmt.Sprint(index)))
+	for _, paths := range [][]string{pair.Schema, pair.Queries} {
+		files, err := sqlpath.Glob(paths)
+		if err != nil {
+			return "", err
+		}
+		for _, filename := range files {
+			blob, err := os.ReadFile(filename)
+			if err != nil {
+				return "", err
+			}
+			writeHashed(h, []byte(filename))
+			writeHashed(h, blob)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *outputCache) get(key string) (*cachedOutput, bool) {
+	blob, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var out cachedOutput
+	if err := json.Unmarshal(blob, &out); err != nil {
+		return nil, false
+	}
+	return &out, true
+}
+
+func (c *outputCache) put(key string, out *cachedOutput) error {
+	blob, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+}
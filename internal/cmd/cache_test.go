@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cacheTestConfig = `version: '2'
+sql:
+  - schema: books/schema.sql
+    queries: books/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: books
+        out: books
+`
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOutputCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("SQLCCACHE", cacheDir)
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"sqlc.yaml":        cacheTestConfig,
+		"books/schema.sql": "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL);\n",
+		"books/query.sql":  "-- name: GetBook :one\n-- -- timeout : 500ms\nSELECT * FROM books WHERE id = $1;\n",
+	})
+	queryFile := filepath.Join(dir, "books", "query.sql.go")
+
+	generate := func() map[string]string {
+		t.Helper()
+		var stderr bytes.Buffer
+		output, err := Generate(context.Background(), Env{Cache: true}, dir, "sqlc.yaml", &stderr)
+		if err != nil {
+			t.Fatalf("generate: %s\n%s", err, stderr.String())
+		}
+		return output
+	}
+	// mark replaces the query file of every cached output, so that a cache
+	// hit can be told apart from a new generation.
+	mark := func() {
+		t.Helper()
+		entries, err := filepath.Glob(filepath.Join(cacheDir, "generate", "*.json"))
+		if err != nil || len(entries) == 0 {
+			t.Fatalf("no cached output: %v", err)
+		}
+		for _, entry := range entries {
+			blob, err := os.ReadFile(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out cachedOutput
+			if err := json.Unmarshal(blob, &out); err != nil {
+				t.Fatal(err)
+			}
+			out.Files["query.sql.go"] = "cached"
+			blob, err = json.Marshal(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(entry, blob, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if out := generate()[queryFile]; !strings.Contains(out, "func (q *Queries) GetBook(") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	mark()
+	if out := generate()[queryFile]; out != "cached" {
+		t.Errorf("unchanged package: expected a cache hit, got:\n%s", out)
+	}
+
+	for name, change := range map[string]func(string) string{
+		"sqlc.yaml": func(s string) string {
+			return s + "        emit_json_tags: true\n"
+		},
+		"books/schema.sql": func(s string) string {
+			return strings.Replace(s, "title TEXT NOT NULL", "title TEXT NOT NULL, year INT", 1)
+		},
+		"books/query.sql": func(s string) string {
+			return s + "\n-- name: CountBooks :one\n-- -- timeout : 500ms\nSELECT count(*) FROM books;\n"
+		},
+	} {
+		path := filepath.Join(dir, name)
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFiles(t, dir, map[string]string{name: change(string(blob))})
+		if out := generate()[queryFile]; out == "cached" {
+			t.Errorf("%s changed: expected a cache miss", name)
+		}
+		mark()
+		if out := generate()[queryFile]; out != "cached" {
+			t.Errorf("%s changed: expected a cache hit on the next run, got:\n%s", name, out)
+		}
+	}
+}
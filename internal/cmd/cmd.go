@@ -30,6 +30,21 @@ func init() {
 	initCmd.Flags().BoolP("v1", "", false, "generate v1 config yaml file")
 	initCmd.Flags().BoolP("v2", "", true, "generate v2 config yaml file")
 	initCmd.MarkFlagsMutuallyExclusive("v1", "v2")
+	apiDiffCmd.Flags().String("from", "", "git revision of the queries to compare with")
+	apiDiffCmd.Flags().String("format", "text", "output format: text or json")
+	diffCmd.Flags().String("format", "text", "output format: text or json")
+	diffCmd.Flags().Bool("write", false, "write the stale generated files")
+	migrateDiffCmd.Flags().String("from", "", "git revision of the schema files to migrate from")
+	migrateDiffCmd.Flags().String("format", "goose", "migration format: goose, sql-migrate, tern or dbmate")
+	migrateCmd.AddCommand(migrateDiffCmd)
+	introspectCmd.Flags().String("uri", "", "database to read the schema of")
+	introspectCmd.Flags().String("out", ".", "directory to write the files to")
+	introspectCmd.Flags().Bool("force", false, "overwrite existing files")
+	reportCmd.Flags().String("format", "markdown", "output format: markdown or html")
+	reportCmd.Flags().Bool("explain", false, "add the EXPLAIN output of the queries of the packages with a database.uri")
+	reportCmd.Flags().StringP("output", "o", "", "file to write the report to instead of stdout")
+	verifySchemaCmd.Flags().String("uri", "", "database to compare with instead of database.uri, e.g. a local database")
+	verifySchemaCmd.Flags().String("format", "text", "output format: text or json")
 }
 
 // Do runs the command logic.
@@ -39,13 +54,20 @@ func Do(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int
 	rootCmd.PersistentFlags().BoolP("experimental", "x", false, "DEPRECATED: enable experimental features (default: false)")
 	rootCmd.PersistentFlags().Bool("no-remote", false, "disable remote execution (default: false)")
 	rootCmd.PersistentFlags().Bool("no-database", false, "disable database connections (default: false)")
+	rootCmd.PersistentFlags().Bool("cache", false, "share parsed schemas between packages, cache generated code on disk and skip unchanged packages (default: false)")
 
+	rootCmd.AddCommand(apiDiffCmd)
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(genCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(introspectCmd)
+	rootCmd.AddCommand(lspCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(uploadCmd)
+	rootCmd.AddCommand(verifySchemaCmd)
 	rootCmd.AddCommand(NewCmdVet())
 
 	rootCmd.SetArgs(args)
@@ -139,17 +161,20 @@ type Env struct {
 	Debug      opts.Debug
 	NoRemote   bool
 	NoDatabase bool
+	Cache      bool
 }
 
 func ParseEnv(c *cobra.Command) Env {
 	dr := c.Flag("dry-run")
 	nr := c.Flag("no-remote")
 	nodb := c.Flag("no-database")
+	cache := c.Flag("cache")
 	return Env{
 		DryRun:     dr != nil && dr.Changed,
 		Debug:      opts.DebugFromEnv(),
 		NoRemote:   nr != nil && nr.Value.String() == "true",
 		NoDatabase: nodb != nil && nodb.Value.String() == "true",
+		Cache:      cache != nil && cache.Value.String() == "true",
 	}
 }
 
@@ -279,8 +304,17 @@ var diffCmd = &cobra.Command{
 		defer trace.StartRegion(cmd.Context(), "diff").End()
 		stderr := cmd.ErrOrStderr()
 		dir, name := getConfigPath(stderr, cmd.Flag("file"))
-		if err := Diff(cmd.Context(), ParseEnv(cmd), dir, name, stderr); err != nil {
-			os.Exit(1)
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		write, err := cmd.Flags().GetBool("write")
+		if err != nil {
+			return err
+		}
+		o := DiffOptions{Format: format, Write: write}
+		if err := Diff(cmd.Context(), ParseEnv(cmd), dir, name, o, cmd.OutOrStdout(), stderr); err != nil {
+			os.Exit(diffExitCode(err))
 		}
 		return nil
 	},
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime/trace"
 	"sort"
 	"strings"
@@ -13,13 +15,83 @@ import (
 	"github.com/cubicdaiya/gonp"
 )
 
-func Diff(ctx context.Context, e Env, dir, name string, stderr io.Writer) error {
-	output, err := Generate(ctx, e, dir, name, stderr)
+// Exit codes of sqlc diff, so that hooks can tell stale files apart from
+// configurations that fail to generate.
+const (
+	diffExitStale  = 1
+	diffExitFailed = 2
+)
+
+// errStale is returned by Diff when generated files are missing or differ
+// from the files on disk.
+var errStale = errors.New("diff found")
+
+// diffExitCode returns the exit code of sqlc diff for an error of Diff.
+func diffExitCode(err error) int {
+	if errors.Is(err, errStale) {
+		return diffExitStale
+	}
+	return diffExitFailed
+}
+
+type DiffOptions struct {
+	// Format is either "text" (unified hunks on stderr) or "json" (a report
+	// on stdout).
+	Format string
+	// Write updates the stale files on disk.
+	Write bool
+}
+
+type diffReport struct {
+	Files []diffFile `json:"files"`
+}
+
+type diffFile struct {
+	Path    string     `json:"path"`
+	Status  string     `json:"status"`
+	Added   int        `json:"added"`
+	Removed int        `json:"removed"`
+	SQL     *diffBlock `json:"sql,omitempty"`
+}
+
+// diffBlock identifies the sql block of the configuration that produced a
+// file.
+type diffBlock struct {
+	Index   int      `json:"index"`
+	Schema  []string `json:"schema"`
+	Queries []string `json:"queries"`
+	Out     string   `json:"out"`
+}
+
+func newDiffBlock(pair outPair) *diffBlock {
+	block := &diffBlock{
+		Index:   pair.Index,
+		Schema:  pair.Schema,
+		Queries: pair.Queries,
+	}
+	switch {
+	case pair.Gen.Go != nil:
+		block.Out = pair.Gen.Go.Out
+	case pair.Gen.JSON != nil:
+		block.Out = pair.Gen.JSON.Out
+	case pair.Plugin != nil:
+		block.Out = pair.Plugin.Out
+	}
+	return block
+}
+
+func Diff(ctx context.Context, e Env, dir, name string, o DiffOptions, stdout, stderr io.Writer) error {
+	if o.Format != "text" && o.Format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q\n", o.Format)
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
+	output, origins, err := generate(ctx, e, dir, name, stderr)
 	if err != nil {
 		return err
 	}
 	defer trace.StartRegion(ctx, "checkfiles").End()
 	var errored bool
+	report := diffReport{Files: []diffFile{}}
 
 	keys := make([]string, 0, len(output))
 	for k, _ := range output {
@@ -30,30 +102,70 @@ func Diff(ctx context.Context, e Env, dir, name string, stderr io.Writer) error
 
 	for _, filename := range keys {
 		source := output[filename]
-		if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
-			errored = true
-			// stdout message
-			continue
-		}
-		existing, err := os.ReadFile(filename)
+		rel, err := filepath.Rel(dir, filename)
 		if err != nil {
-			errored = true
-			fmt.Fprintf(stderr, "%s: %s\n", filename, err)
-			continue
+			rel = filename
+		}
+		file := diffFile{Path: rel, Status: "modified"}
+		if pair, ok := origins[filename]; ok {
+			file.SQL = newDiffBlock(pair)
+		}
+		var existing []byte
+		if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+			file.Status = "missing"
+		} else {
+			existing, err = os.ReadFile(filename)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", filename, err)
+				return err
+			}
 		}
 		diff := gonp.New(getLines(existing), getLines([]byte(source)))
 		diff.Compose()
 		uniHunks := filterHunks(diff.UnifiedHunks())
+		if file.Status != "missing" && len(uniHunks) == 0 {
+			continue
+		}
+		for _, e := range diff.Ses() {
+			switch e.GetType() {
+			case gonp.SesAdd:
+				file.Added++
+			case gonp.SesDelete:
+				file.Removed++
+			}
+		}
+		errored = true
+		report.Files = append(report.Files, file)
 
-		if len(uniHunks) > 0 {
-			errored = true
-			fmt.Fprintf(stderr, "--- a%s\n", strings.TrimPrefix(filename, dir))
-			fmt.Fprintf(stderr, "+++ b%s\n", strings.TrimPrefix(filename, dir))
-			diff.FprintUniHunks(stderr, uniHunks)
+		if o.Format == "text" {
+			if file.Status == "missing" {
+				fmt.Fprintf(stderr, "%s: missing\n", rel)
+			} else {
+				fmt.Fprintf(stderr, "--- a%s\n", strings.TrimPrefix(filename, dir))
+				fmt.Fprintf(stderr, "+++ b%s\n", strings.TrimPrefix(filename, dir))
+				diff.FprintUniHunks(stderr, uniHunks)
+			}
+		}
+		if o.Write {
+			if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", filename, err)
+				return err
+			}
+			if err := os.WriteFile(filename, []byte(source), 0644); err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", filename, err)
+				return err
+			}
+		}
+	}
+	if o.Format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
 		}
 	}
 	if errored {
-		return errors.New("diff found")
+		return errStale
 	}
 	return nil
 }
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDiffTestFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"sqlc.yaml":        cacheTestConfig,
+		"books/schema.sql": "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL);\n",
+		"books/query.sql":  "-- name: GetBook :one\n-- -- timeout : 500ms\nSELECT * FROM books WHERE id = $1;\n",
+	})
+	return dir
+}
+
+func TestDiff(t *testing.T) {
+	dir := writeDiffTestFiles(t)
+	diff := func(o DiffOptions) (diffReport, string, error) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		err := Diff(context.Background(), Env{}, dir, "sqlc.yaml", o, &stdout, &stderr)
+		var report diffReport
+		if o.Format == "json" && (err == nil || errors.Is(err, errStale)) {
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("json report: %s\n%s", err, stdout.String())
+			}
+		}
+		return report, stderr.String(), err
+	}
+
+	// Nothing was generated yet: every file is missing.
+	report, _, err := diff(DiffOptions{Format: "json"})
+	if code := diffExitCode(err); !errors.Is(err, errStale) || code != diffExitStale {
+		t.Fatalf("missing files: got %v (exit %d), want %v (exit %d)", err, code, errStale, diffExitStale)
+	}
+	var paths []string
+	for _, f := range report.Files {
+		if f.Status != "missing" {
+			t.Errorf("%s: status %q, want missing", f.Path, f.Status)
+		}
+		if f.SQL == nil || f.SQL.Out != "books" || f.SQL.Queries[0] != "books/query.sql" {
+			t.Errorf("%s: sql block %+v", f.Path, f.SQL)
+		}
+		paths = append(paths, f.Path)
+	}
+	if got, want := strings.Join(paths, " "), "books/db.go books/models.go books/query.sql.go"; got != want {
+		t.Errorf("files: got %q, want %q", got, want)
+	}
+
+	// --write creates the files, after which nothing is reported.
+	if _, _, err := diff(DiffOptions{Format: "text", Write: true}); !errors.Is(err, errStale) {
+		t.Fatalf("write: got %v, want %v", err, errStale)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("write: %s", err)
+		}
+	}
+	report, _, err = diff(DiffOptions{Format: "json"})
+	if err != nil {
+		t.Fatalf("after write: %s", err)
+	}
+	if len(report.Files) != 0 {
+		t.Errorf("after write: got %+v, want no files", report.Files)
+	}
+
+	// A changed query modifies a single file.
+	writeTestFiles(t, dir, map[string]string{
+		"books/query.sql": "-- name: GetBook :one\n-- -- timeout : 500ms\nSELECT * FROM books WHERE id = $1;\n\n-- name: ListBooks :many\n-- -- timeout : 500ms\nSELECT * FROM books;\n",
+	})
+	report, _, err = diff(DiffOptions{Format: "json"})
+	if !errors.Is(err, errStale) {
+		t.Fatalf("modified: got %v, want %v", err, errStale)
+	}
+	if len(report.Files) != 1 {
+		t.Fatalf("modified: got %+v, want one file", report.Files)
+	}
+	if f := report.Files[0]; f.Path != "books/query.sql.go" || f.Status != "modified" || f.Added == 0 {
+		t.Errorf("modified: got %+v", f)
+	}
+	_, stderr, _ := diff(DiffOptions{Format: "text"})
+	if !strings.Contains(stderr, "+++ b/books/query.sql.go") || !strings.Contains(stderr, "+func (q *Queries) ListBooks(") {
+		t.Errorf("text diff:\n%s", stderr)
+	}
+
+	// A configuration that fails to generate exits with a different code.
+	writeTestFiles(t, dir, map[string]string{
+		"books/query.sql": "-- name: GetBook :one\n-- -- timeout : 500ms\nSELECT * FROM missing WHERE id = $1;\n",
+	})
+	_, _, err = diff(DiffOptions{Format: "json"})
+	if err == nil || errors.Is(err, errStale) || diffExitCode(err) != diffExitFailed {
+		t.Errorf("failure: got %v (exit %d), want exit %d", err, diffExitCode(err), diffExitFailed)
+	}
+	if _, _, err := diff(DiffOptions{Format: "yaml"}); diffExitCode(err) != diffExitFailed {
+		t.Errorf("unknown format: got %v", err)
+	}
+}
@@ -59,6 +59,9 @@ type outPair struct {
 	Gen    config.SQLGen
 	Plugin *config.Codegen
 
+	// Index is the position of the sql block in the configuration.
+	Index int
+
 	config.SQL
 }
 
@@ -104,14 +107,12 @@ func readConfig(stderr io.Writer, dir, filename string) (string, *config.Config,
 	}
 
 	base := filepath.Base(configPath)
-	file, err := os.Open(configPath)
-	if err != nil {
+	if _, err := os.Stat(configPath); err != nil {
 		fmt.Fprintf(stderr, "error parsing %s: file does not exist\n", base)
 		return "", nil, err
 	}
-	defer file.Close()
 
-	conf, err := config.ParseConfig(file)
+	conf, err := config.ParseConfigFile(configPath)
 	if err != nil {
 		switch err {
 		case config.ErrMissingVersion:
@@ -129,51 +130,80 @@ func readConfig(stderr io.Writer, dir, filename string) (string, *config.Config,
 }
 
 func Generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer) (map[string]string, error) {
+	output, _, err := generate(ctx, e, dir, filename, stderr)
+	return output, err
+}
+
+// generate is like Generate, but also returns the package that produced each
+// file. Files generated remotely have no package.
+func generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer) (map[string]string, map[string]outPair, error) {
 	configPath, conf, err := readConfig(stderr, dir, filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	base := filepath.Base(configPath)
 	if err := config.Validate(conf); err != nil {
 		fmt.Fprintf(stderr, "error validating %s: %s\n", base, err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := e.Validate(conf); err != nil {
 		fmt.Fprintf(stderr, "error validating %s: %s\n", base, err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if conf.Cloud.Project != "" && !e.NoRemote {
-		return remoteGenerate(ctx, configPath, conf, dir, stderr)
+		output, err := remoteGenerate(ctx, configPath, conf, dir, stderr)
+		return output, nil, err
 	}
 
 	output := map[string]string{}
+	origins := map[string]outPair{}
 	errored := false
 
 	var pairs []outPair
-	for _, sql := range conf.SQL {
+	for index, sql := range conf.SQL {
 		if sql.Gen.Go != nil {
 			pairs = append(pairs, outPair{
-				SQL: sql,
-				Gen: config.SQLGen{Go: sql.Gen.Go},
+				SQL:   sql,
+				Gen:   config.SQLGen{Go: sql.Gen.Go},
+				Index: index,
 			})
 		}
 		if sql.Gen.JSON != nil {
 			pairs = append(pairs, outPair{
-				SQL: sql,
-				Gen: config.SQLGen{JSON: sql.Gen.JSON},
+				SQL:   sql,
+				Gen:   config.SQLGen{JSON: sql.Gen.JSON},
+				Index: index,
 			})
 		}
 		for i, _ := range sql.Codegen {
 			pairs = append(pairs, outPair{
 				SQL:    sql,
 				Plugin: &sql.Codegen[i],
+				Index:  index,
 			})
 		}
 	}
 
+	var outputs *outputCache
+	if e.Cache {
+		outputs, err = newOutputCache(configPath, conf.Fragments)
+		if err != nil {
+			fmt.Fprintf(stderr, "error opening cache: %s\n", err)
+			return nil, nil, err
+		}
+	}
+	// Parsed schema files and catalogs are only shared between packages
+	// with --cache.
+	var cache *compiler.Cache
+	if e.Cache {
+		cache = compiler.NewCache()
+	}
+	plugins := process.NewPool(stderr)
+	defer plugins.Close()
+
 	var m sync.Mutex
 	grp, gctx := errgroup.WithContext(ctx)
 	grp.SetLimit(runtime.GOMAXPROCS(0))
@@ -182,6 +212,7 @@ func Generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer
 
 	for i, pair := range pairs {
 		sql := pair
+		index := i
 		errout := &stderrs[i]
 
 		grp.Go(func() error {
@@ -221,14 +252,40 @@ func Generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer
 			packageRegion := trace.StartRegion(gctx, "package")
 			trace.Logf(gctx, "", "name=%s dir=%s plugin=%s", name, dir, lang)
 
-			result, failed := parse(gctx, name, dir, sql.SQL, combo, parseOpts, errout)
+			// The output of process plugins depends on a binary outside
+			// of the cache key.
+			var key string
+			if outputs != nil && !usesProcessPlugin(*conf, sql) {
+				k, err := outputs.key(index, sql)
+				if err != nil {
+					fmt.Fprintf(errout, "# package %s\n", name)
+					fmt.Fprintf(errout, "error reading cache: %s\n", err)
+					errored = true
+					packageRegion.End()
+					return nil
+				}
+				key = k
+				if cached, ok := outputs.get(key); ok {
+					m.Lock()
+					for n, source := range cached.Files {
+						filename := filepath.Join(dir, cached.Out, n)
+						output[filename] = source
+						origins[filename] = pairs[index]
+					}
+					m.Unlock()
+					packageRegion.End()
+					return nil
+				}
+			}
+
+			result, failed := parse(gctx, name, dir, sql.SQL, combo, parseOpts, cache, errout)
 			if failed {
 				packageRegion.End()
 				errored = true
 				return nil
 			}
 
-			out, resp, err := codegen(gctx, combo, sql, result)
+			out, resp, err := codegen(gctx, combo, sql, result, plugins, errout)
 			if err != nil {
 				fmt.Fprintf(errout, "# package %s\n", name)
 				fmt.Fprintf(errout, "error generating code: %s\n", err)
@@ -241,11 +298,18 @@ func Generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer
 			for _, file := range resp.Files {
 				files[file.Name] = string(file.Contents)
 			}
+			if key != "" {
+				if err := outputs.put(key, &cachedOutput{Out: out, Files: files}); err != nil {
+					fmt.Fprintf(errout, "# package %s\n", name)
+					fmt.Fprintf(errout, "error writing cache: %s\n", err)
+				}
+			}
 
 			m.Lock()
 			for n, source := range files {
 				filename := filepath.Join(dir, out, n)
 				output[filename] = source
+				origins[filename] = pairs[index]
 			}
 			m.Unlock()
 
@@ -254,17 +318,17 @@ func Generate(ctx context.Context, e Env, dir, filename string, stderr io.Writer
 		})
 	}
 	if err := grp.Wait(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if errored {
 		for i, _ := range stderrs {
 			if _, err := io.Copy(stderr, &stderrs[i]); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
-		return nil, fmt.Errorf("errored")
+		return nil, nil, fmt.Errorf("errored")
 	}
-	return output, nil
+	return output, origins, nil
 }
 
 func remoteGenerate(ctx context.Context, configPath string, conf *config.Config, dir string, stderr io.Writer) (map[string]string, error) {
@@ -284,6 +348,15 @@ func remoteGenerate(ctx context.Context, configPath string, conf *config.Config,
 		Version: info.Version,
 		Inputs:  []*remote.File{{Path: filepath.Base(configPath), Bytes: configBytes}},
 	}
+	for _, path := range conf.Fragments {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading config file %s: %s\n", path, err)
+			return nil, err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rpcReq.Inputs = append(rpcReq.Inputs, &remote.File{Path: rel, Bytes: blob})
+	}
 
 	for _, pkg := range conf.SQL {
 		for _, paths := range []config.Paths{pkg.Schema, pkg.Queries} {
@@ -330,9 +403,20 @@ func remoteGenerate(ctx context.Context, configPath string, conf *config.Config,
 	return output, nil
 }
 
-func parse(ctx context.Context, name, dir string, sql config.SQL, combo config.CombinedSettings, parserOpts opts.Parser, stderr io.Writer) (*compiler.Result, bool) {
+func usesProcessPlugin(conf config.Config, pair outPair) bool {
+	if pair.Plugin == nil {
+		return false
+	}
+	plug, err := findPlugin(conf, pair.Plugin.Plugin)
+	return err != nil || plug.Process != nil
+}
+
+func parse(ctx context.Context, name, dir string, sql config.SQL, combo config.CombinedSettings, parserOpts opts.Parser, cache *compiler.Cache, stderr io.Writer) (*compiler.Result, bool) {
 	defer trace.StartRegion(ctx, "parse").End()
 	c := compiler.NewCompiler(sql, combo)
+	if cache != nil {
+		c.UseCache(cache)
+	}
 	if err := c.ParseCatalog(sql.Schema); err != nil {
 		fmt.Fprintf(stderr, "# package %s\n", name)
 		if parserErr, ok := err.(*multierr.Error); ok {
@@ -361,7 +445,9 @@ func parse(ctx context.Context, name, dir string, sql config.SQL, combo config.C
 	return c.Result(), false
 }
 
-func codegen(ctx context.Context, combo config.CombinedSettings, sql outPair, result *compiler.Result) (string, *plugin.CodeGenResponse, error) {
+// codegen runs the code generator of a package. Persistent process plugins
+// are taken from plugins, and their standard error is written to stderr.
+func codegen(ctx context.Context, combo config.CombinedSettings, sql outPair, result *compiler.Result, plugins *process.Pool, stderr io.Writer) (string, *plugin.CodeGenResponse, error) {
 	defer trace.StartRegion(ctx, "codegen").End()
 	req := codeGenRequest(result, combo)
 	var handler ext.Handler
@@ -385,8 +471,11 @@ func codegen(ctx context.Context, combo config.CombinedSettings, sql outPair, re
 		switch {
 		case plug.Process != nil:
 			handler = &process.Runner{
-				Cmd: plug.Process.Cmd,
-				Env: plug.Env,
+				Cmd:      plug.Process.Cmd,
+				Env:      plug.Env,
+				Protocol: plug.Process.Protocol,
+				Pool:     plugins,
+				Stderr:   stderr,
 			}
 		case plug.WASM != nil:
 			handler = &wasm.Runner{
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/introspect"
+)
+
+var introspectCmd = &cobra.Command{
+	Use:   "introspect",
+	Short: "Write schema files and a sqlc.yaml for an existing PostgreSQL database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defer trace.StartRegion(cmd.Context(), "introspect").End()
+		stderr := cmd.ErrOrStderr()
+		uri, err := cmd.Flags().GetString("uri")
+		if err != nil {
+			return err
+		}
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+		o := IntrospectOptions{URI: uri, Out: out, Force: force}
+		if err := Introspect(cmd.Context(), ParseEnv(cmd), o, stderr); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+type IntrospectOptions struct {
+	URI string
+	// Out is the directory the files are written to.
+	Out string
+	// Force overwrites existing files.
+	Force bool
+}
+
+// Introspect writes a schema file per table and view of the database at
+// o.URI, and a sqlc.yaml with a wpgx package for each of them.
+func Introspect(ctx context.Context, e Env, o IntrospectOptions, stderr io.Writer) error {
+	if o.URI == "" {
+		return fmt.Errorf("--uri is required")
+	}
+	if e.NoDatabase {
+		return fmt.Errorf("database: connections disabled via command line flag")
+	}
+	conn, err := pgx.Connect(ctx, expandURI(o.URI))
+	if err != nil {
+		return fmt.Errorf("database: connection error: %s", err)
+	}
+	db, err := introspect.Load(ctx, conn)
+	conn.Close(ctx)
+	if err != nil {
+		return fmt.Errorf("database: %s", err)
+	}
+
+	files := db.Files()
+	if !o.Force {
+		for _, f := range files {
+			path := filepath.Join(o.Out, f.Path)
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s already exists, pass --force to overwrite it", path)
+			}
+		}
+	}
+	for _, f := range files {
+		path := filepath.Join(o.Out, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(f.Contents), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "wrote %s\n", path)
+	}
+	return nil
+}
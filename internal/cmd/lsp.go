@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/lsp"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server for query files over stdio",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stderr := cmd.ErrOrStderr()
+		dir, name := getConfigPath(stderr, cmd.Flag("file"))
+		configPath, conf, err := readConfig(stderr, dir, name)
+		if err != nil {
+			return err
+		}
+		if err := config.Validate(conf); err != nil {
+			fmt.Fprintf(stderr, "error validating %s: %s\n", configPath, err)
+			return err
+		}
+
+		srv := lsp.NewServer(dir, conf, codeGenRequest)
+		return srv.Serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+	},
+}
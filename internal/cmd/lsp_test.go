@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/lsp"
+)
+
+func TestLSPHoverGoTypes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "books"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"books/schema.sql": "CREATE TABLE books (\n  id BIGINT PRIMARY KEY,\n  title TEXT NOT NULL,\n  year INT\n);\n",
+		"books/query.sql":  "-- name: ListBooks :many\n-- -- timeout : 500ms\nSELECT * FROM books WHERE title = @title AND year > @year;\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conf := &config.Config{
+		Version: "2",
+		SQL: []config.SQL{{
+			Engine:  config.EnginePostgreSQL,
+			Schema:  config.Paths{"books/schema.sql"},
+			Queries: config.Paths{"books/query.sql"},
+			Gen:     config.SQLGen{Go: &config.SQLGo{Package: "books", Out: "books", SQLPackage: "wpgx"}},
+		}},
+	}
+
+	var in bytes.Buffer
+	for _, msg := range []map[string]interface{}{
+		{"jsonrpc": "2.0", "id": 1, "method": "textDocument/hover", "params": map[string]interface{}{
+			"textDocument": map[string]string{"uri": "file://" + filepath.Join(dir, "books", "query.sql")},
+			"position":     map[string]int{"line": 2, "character": 3},
+		}},
+		{"jsonrpc": "2.0", "method": "exit"},
+	} {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	var out bytes.Buffer
+	if err := lsp.NewServer(dir, conf, codeGenRequest).Serve(context.Background(), &in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	r := textproto.NewReader(bufio.NewReader(&out))
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r.R, body); err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Result lsp.Hover `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	hover := resp.Result.Contents.Value
+	for _, want := range []string{
+		"(arg ListBooksParams)",
+		"// returns\nBook\n",
+		"\tID int64\n",
+		"\tTitle string\n",
+		"\tYear *int32\n",
+	} {
+		if !strings.Contains(hover, want) {
+			t.Errorf("hover does not contain %q:\n%s", want, hover)
+		}
+	}
+}
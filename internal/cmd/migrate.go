@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/compiler"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+	"github.com/sqlc-dev/sqlc/internal/sql/sqlpath"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Work with database migrations",
+}
+
+var migrateDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Print the migration from the schema files at a git revision to the current ones",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defer trace.StartRegion(cmd.Context(), "migrate diff").End()
+		stderr := cmd.ErrOrStderr()
+		dir, name := getConfigPath(stderr, cmd.Flag("file"))
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if err := MigrateDiff(cmd.Context(), dir, name, from, format, cmd.OutOrStdout(), stderr); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// MigrateDiff prints the migration, with its rollback, that moves a database
+// from the PostgreSQL schema files of the git revision ref to the schema
+// files in the working tree.
+func MigrateDiff(ctx context.Context, dir, filename, ref, format string, stdout, stderr io.Writer) error {
+	if ref == "" {
+		return fmt.Errorf("--from is required")
+	}
+	_, conf, err := readConfig(stderr, dir, filename)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "sqlc-migrate")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	from, to := migrations.NewSchema(), migrations.NewSchema()
+	for _, sql := range conf.SQL {
+		if sql.Engine != config.EnginePostgreSQL {
+			continue
+		}
+		var current []string
+		for _, s := range sql.Schema {
+			current = append(current, filepath.Join(dir, s))
+		}
+		if err := loadSchema(to, *conf, sql, current, dir, stderr); err != nil {
+			return err
+		}
+		old, err := checkoutSchema(ctx, dir, ref, sql.Schema, tmp)
+		if err != nil {
+			return err
+		}
+		if len(old) == 0 {
+			continue
+		}
+		if err := loadSchema(from, *conf, sql, old, tmp, stderr); err != nil {
+			return fmt.Errorf("schema at %s: %w", ref, err)
+		}
+	}
+
+	up := migrations.Diff(from, to)
+	if len(up) == 0 {
+		fmt.Fprintf(stderr, "no schema changes since %s\n", ref)
+		return nil
+	}
+	down := migrations.Diff(to, from)
+	out, err := migrations.Format(format, up, down)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(stdout, out); err != nil {
+		return err
+	}
+	if n := unsupportedChanges(down); n > 0 {
+		fmt.Fprintf(stderr, "warning: the rollback has %d unsupported changes\n", n)
+	}
+	if format == "tern" && (migrations.AddsEnumValue(up) || migrations.AddsEnumValue(down)) {
+		fmt.Fprintf(stderr, "warning: the migration adds enum values, which can't be used before its transaction commits; run it outside of a transaction\n")
+	}
+	if n := unsupportedChanges(up); n > 0 {
+		return fmt.Errorf("the migration has %d unsupported changes, edit the %q lines", n, strings.TrimSpace(migrations.UnsupportedChange))
+	}
+	return nil
+}
+
+func unsupportedChanges(stmts []string) int {
+	n := 0
+	for _, stmt := range stmts {
+		if strings.HasPrefix(stmt, migrations.UnsupportedChange) {
+			n++
+		}
+	}
+	return n
+}
+
+// loadSchema adds the catalog built from the schema files, and the
+// statements of the files, to s.
+func loadSchema(s *migrations.Schema, conf config.Config, sql config.SQL, files []string, dir string, stderr io.Writer) error {
+	sql.Schema = files
+	c := compiler.NewCompiler(sql, config.Combine(conf, sql))
+	if err := c.ParseCatalog(files); err != nil {
+		if parserErr, ok := err.(*multierr.Error); ok {
+			for _, fileErr := range parserErr.Errs() {
+				printFileErr(stderr, dir, fileErr)
+			}
+		}
+		return fmt.Errorf("error parsing schema: %w", err)
+	}
+	s.AddCatalog(c.Catalog())
+
+	paths, err := sqlpath.Glob(files)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		contents := migrations.RemoveRollbackStatements(string(blob))
+		stmts, err := c.Parser().Parse(strings.NewReader(contents))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		s.AddStatements(contents, stmts)
+	}
+	return nil
+}
+
+// checkoutSchema writes the schema files of the git revision ref into tmp,
+// and returns the paths of the schema entries that exist at ref.
+func checkoutSchema(ctx context.Context, dir, ref string, schema []string, tmp string) ([]string, error) {
+	var paths []string
+	for _, s := range schema {
+		listing, err := git(ctx, dir, "ls-tree", "-r", "--name-only", ref, "--", s)
+		if err != nil {
+			return nil, err
+		}
+		files := strings.Fields(listing)
+		if len(files) == 0 {
+			continue
+		}
+		for _, file := range files {
+			blob, err := git(ctx, dir, "show", ref+":./"+file)
+			if err != nil {
+				return nil, err
+			}
+			path := filepath.Join(tmp, file)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, []byte(blob), 0644); err != nil {
+				return nil, err
+			}
+		}
+		paths = append(paths, filepath.Join(tmp, s))
+	}
+	return paths, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
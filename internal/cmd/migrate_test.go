@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+const migrateTestConfig = `version: '2'
+sql:
+  - schema: [books/schema.sql, authors/schema.sql]
+    queries: books/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: books
+        out: books
+`
+
+func TestMigrateDiff(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFiles(t, dir, map[string]string{
+		"sqlc.yaml":        migrateTestConfig,
+		"books/schema.sql": "CREATE TABLE books (\n  id    BIGINT PRIMARY KEY,\n  title TEXT NOT NULL,\n  price INT NOT NULL CHECK (price > 0)\n);\n",
+		"books/query.sql":  "-- name: GetBook :one\n-- -- timeout : 500ms\nSELECT * FROM books WHERE id = $1;\n",
+	})
+	run("init", "-q")
+	run("add", "-A")
+	run("-c", "user.name=sqlc", "-c", "user.email=sqlc@example.com", "commit", "-q", "-m", "books")
+
+	// The authors schema file doesn't exist at HEAD.
+	writeTestFiles(t, dir, map[string]string{
+		"authors/schema.sql": "CREATE TABLE authors (\n  id BIGINT PRIMARY KEY\n);\n",
+		"books/schema.sql":   "CREATE TABLE books (\n  id        BIGINT PRIMARY KEY,\n  title     TEXT NOT NULL DEFAULT '',\n  price     INT NOT NULL CHECK (price >= 0),\n  author_id BIGINT REFERENCES authors (id)\n);\n",
+	})
+	var stdout, stderr bytes.Buffer
+	if err := MigrateDiff(ctx, dir, "sqlc.yaml", "HEAD", "goose", &stdout, &stderr); err != nil {
+		t.Fatalf("migrate diff: %s\n%s", err, stderr.String())
+	}
+	want := `-- +goose Up
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_price_check;
+CREATE TABLE authors (
+  id BIGINT PRIMARY KEY
+);
+ALTER TABLE books ALTER COLUMN title SET DEFAULT '';
+ALTER TABLE books ADD COLUMN author_id BIGINT REFERENCES authors (id);
+ALTER TABLE books ADD CHECK (price >= 0);
+
+-- +goose Down
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_author_id_fkey;
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_price_check;
+ALTER TABLE books ALTER COLUMN title DROP DEFAULT;
+ALTER TABLE books DROP COLUMN author_id;
+ALTER TABLE books ADD CHECK (price > 0);
+DROP TABLE IF EXISTS authors;
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Unchanged schema files.
+	run("add", "-A")
+	run("-c", "user.name=sqlc", "-c", "user.email=sqlc@example.com", "commit", "-q", "-m", "authors")
+	stdout.Reset()
+	stderr.Reset()
+	if err := MigrateDiff(ctx, dir, "sqlc.yaml", "HEAD", "tern", &stdout, &stderr); err != nil || stdout.Len() != 0 {
+		t.Fatalf("unchanged: got %v\n%s", err, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "no schema changes since HEAD") {
+		t.Errorf("unchanged: %s", stderr.String())
+	}
+
+	// An unnamed table constraint can't be dropped.
+	writeTestFiles(t, dir, map[string]string{
+		"authors/schema.sql": "CREATE TABLE authors (\n  id BIGINT PRIMARY KEY,\n  CHECK (id > 0)\n);\n",
+	})
+	run("add", "-A")
+	run("-c", "user.name=sqlc", "-c", "user.email=sqlc@example.com", "commit", "-q", "-m", "check")
+	writeTestFiles(t, dir, map[string]string{
+		"authors/schema.sql": "CREATE TABLE authors (\n  id BIGINT PRIMARY KEY\n);\n",
+	})
+	stdout.Reset()
+	stderr.Reset()
+	err := MigrateDiff(ctx, dir, "sqlc.yaml", "HEAD", "tern", &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "1 unsupported changes") {
+		t.Errorf("unsupported: got %v", err)
+	}
+	want = `-- unsupported change: drop the unnamed constraint of authors: CHECK (id > 0)
+
+---- create above / drop below ----
+ALTER TABLE authors ADD CHECK (id > 0);
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("unsupported: got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// tern has no way to run a migration outside of a transaction.
+	writeTestFiles(t, dir, map[string]string{
+		"authors/schema.sql": "CREATE TYPE status AS ENUM ('open');\nCREATE TABLE authors (\n  id BIGINT PRIMARY KEY\n);\n",
+	})
+	run("add", "-A")
+	run("-c", "user.name=sqlc", "-c", "user.email=sqlc@example.com", "commit", "-q", "-m", "status")
+	writeTestFiles(t, dir, map[string]string{
+		"authors/schema.sql": "CREATE TYPE status AS ENUM ('open', 'closed');\nCREATE TABLE authors (\n  id BIGINT PRIMARY KEY\n);\n",
+	})
+	stdout.Reset()
+	stderr.Reset()
+	if err := MigrateDiff(ctx, dir, "sqlc.yaml", "HEAD", "tern", &stdout, &stderr); err != nil {
+		t.Fatalf("enum: %s\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ALTER TYPE status ADD VALUE 'closed';") || !strings.Contains(stderr.String(), "run it outside of a transaction") {
+		t.Errorf("enum: got:\n%s\n%s", stdout.String(), stderr.String())
+	}
+
+	if err := MigrateDiff(ctx, dir, "sqlc.yaml", "no-such-ref", "goose", &stdout, &stderr); err == nil {
+		t.Errorf("unknown ref: no error")
+	}
+	if err := MigrateDiff(ctx, dir, "sqlc.yaml", "HEAD", "flyway", &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "unknown migration format") {
+		t.Errorf("unknown format: got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/report"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render the queries of every package as a document for review",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defer trace.StartRegion(cmd.Context(), "report").End()
+		stderr := cmd.ErrOrStderr()
+		dir, name := getConfigPath(stderr, cmd.Flag("file"))
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		explain, err := cmd.Flags().GetBool("explain")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		stdout := cmd.OutOrStdout()
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				fmt.Fprintf(stderr, "error: %s\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			stdout = f
+		}
+		o := ReportOptions{Format: format, Explain: explain}
+		if err := Report(cmd.Context(), ParseEnv(cmd), dir, name, o, stdout, stderr); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+type ReportOptions struct {
+	// Format is either "markdown" or "html".
+	Format string
+	// Explain adds the EXPLAIN output of the queries of the PostgreSQL
+	// packages with a database.uri, as vet gets it.
+	Explain bool
+}
+
+// Report compiles the queries of every package, and writes the SQL, tables,
+// parameters and options of each query as a Markdown or HTML document.
+func Report(ctx context.Context, e Env, dir, filename string, o ReportOptions, stdout, stderr io.Writer) error {
+	if o.Format != "markdown" && o.Format != "html" {
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
+	if o.Explain && e.NoDatabase {
+		return fmt.Errorf("database: connections disabled via command line flag")
+	}
+	_, conf, err := readConfig(stderr, dir, filename)
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(conf); err != nil {
+		return err
+	}
+
+	var r report.Report
+	for _, sql := range conf.SQL {
+		combo := config.Combine(*conf, sql)
+		name := combo.Go.Package
+		if name == "" {
+			name = strings.Join(sql.Queries, ", ")
+		}
+		var schema, queries []string
+		for _, s := range sql.Schema {
+			schema = append(schema, filepath.Join(dir, s))
+		}
+		for _, q := range sql.Queries {
+			queries = append(queries, filepath.Join(dir, q))
+		}
+		sql.Schema, sql.Queries = schema, queries
+		result, failed := parse(ctx, name, dir, sql, combo, opts.Parser{}, nil, stderr)
+		if failed {
+			return fmt.Errorf("package %s: compilation failed", name)
+		}
+		req := codeGenRequest(result, combo)
+		stmts := make([]ast.Node, len(result.Queries))
+		for i, q := range result.Queries {
+			if q.RawStmt != nil {
+				stmts[i] = q.RawStmt.Stmt
+			}
+		}
+		pkg := report.NewPackage(name, req, stmts)
+		if o.Explain && sql.Engine == config.EnginePostgreSQL && sql.Database != nil {
+			if err := explainPackage(ctx, sql.Database.URI, req.Queries, pkg.Queries); err != nil {
+				return fmt.Errorf("package %s: %w", name, err)
+			}
+		}
+		r.Packages = append(r.Packages, pkg)
+	}
+
+	if o.Format == "html" {
+		return r.HTML(stdout)
+	}
+	return r.Markdown(stdout)
+}
+
+// explainPackage sets the plans of the queries of a package from the
+// database at uri.
+func explainPackage(ctx context.Context, uri string, reqs []*plugin.Query, queries []report.Query) error {
+	conn, err := pgx.Connect(ctx, expandURI(uri))
+	if err != nil {
+		return fmt.Errorf("database: connection error: %s", err)
+	}
+	defer conn.Close(ctx)
+	expl := &pgxConn{conn}
+	for i, q := range reqs {
+		out, err := expl.Explain(ctx, q.Text, q.Params...)
+		if err != nil {
+			return fmt.Errorf("%s: error explaining query: %s", q.Name, err)
+		}
+		queries[i].Plan = report.FormatPlan(out.PostgreSQL.Explain)
+	}
+	return nil
+}
@@ -110,6 +110,7 @@ func pluginGoCode(s config.SQLGo) *plugin.GoCode {
 		InflectionExcludeTableNames: s.InflectionExcludeTableNames,
 		QueryParameterLimit:         s.QueryParameterLimit,
 		OmitUnusedStructs:           s.OmitUnusedStructs,
+		NumericType:                 s.NumericType,
 	}
 }
 
@@ -149,9 +150,23 @@ func pluginCatalog(c *catalog.Catalog) *plugin.Catalog {
 					Vals:    typ.Vals,
 				})
 			case *catalog.CompositeType:
+				var columns []*plugin.Column
+				for _, c := range typ.Columns {
+					columns = append(columns, &plugin.Column{
+						Name: c.Name,
+						Type: &plugin.Identifier{
+							Catalog: c.Type.Catalog,
+							Schema:  c.Type.Schema,
+							Name:    c.Type.Name,
+						},
+						IsArray:   c.IsArray,
+						ArrayDims: int32(c.ArrayDims),
+					})
+				}
 				cts = append(cts, &plugin.CompositeType{
 					Name:    typ.Name,
 					Comment: typ.Comment,
+					Columns: columns,
 				})
 			}
 		}
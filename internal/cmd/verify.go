@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+	"github.com/sqlc-dev/sqlc/internal/shfmt"
+)
+
+// Exit codes of sqlc verify-schema, following the ones of sqlc diff.
+const (
+	verifyExitDrift  = 1
+	verifyExitFailed = 2
+)
+
+// errDrift is returned by VerifySchema when the database differs from the
+// schema files.
+var errDrift = errors.New("schema drift found")
+
+var verifySchemaCmd = &cobra.Command{
+	Use:   "verify-schema",
+	Short: "Compare the schema files to the schema of a database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defer trace.StartRegion(cmd.Context(), "verify-schema").End()
+		stderr := cmd.ErrOrStderr()
+		dir, name := getConfigPath(stderr, cmd.Flag("file"))
+		uri, err := cmd.Flags().GetString("uri")
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		o := VerifySchemaOptions{URI: uri, Format: format}
+		if err := VerifySchema(cmd.Context(), ParseEnv(cmd), dir, name, o, cmd.OutOrStdout(), stderr); err != nil {
+			if errors.Is(err, errDrift) {
+				os.Exit(verifyExitDrift)
+			}
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			os.Exit(verifyExitFailed)
+		}
+		return nil
+	},
+}
+
+type VerifySchemaOptions struct {
+	// URI of the database to compare every package with, instead of the
+	// database.uri of the packages; e.g. a local database.
+	URI string
+	// Format is either "text" (a line per drift) or "json".
+	Format string
+}
+
+type verifyReport struct {
+	Drifts []migrations.Drift `json:"drifts"`
+}
+
+// VerifySchema compares the catalog built from the PostgreSQL schema files of
+// each package with the database of the package, and reports the tables,
+// columns, enums and indexes that differ.
+func VerifySchema(ctx context.Context, e Env, dir, filename string, o VerifySchemaOptions, stdout, stderr io.Writer) error {
+	if o.Format != "text" && o.Format != "json" {
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
+	if e.NoDatabase {
+		return fmt.Errorf("database: connections disabled via command line flag")
+	}
+	_, conf, err := readConfig(stderr, dir, filename)
+	if err != nil {
+		return err
+	}
+
+	// Packages sharing a database are compared with it at once, so that the
+	// tables of one package are not reported as missing by another.
+	var uris []string
+	schemas := map[string]*migrations.Schema{}
+	for _, sql := range conf.SQL {
+		if sql.Engine != config.EnginePostgreSQL {
+			continue
+		}
+		uri := o.URI
+		if uri == "" && sql.Database != nil {
+			uri = sql.Database.URI
+		}
+		if uri == "" {
+			continue
+		}
+		s, ok := schemas[uri]
+		if !ok {
+			s = migrations.NewSchema()
+			schemas[uri] = s
+			uris = append(uris, uri)
+		}
+		var files []string
+		for _, f := range sql.Schema {
+			files = append(files, filepath.Join(dir, f))
+		}
+		if err := loadSchema(s, *conf, sql, files, dir, stderr); err != nil {
+			return err
+		}
+	}
+	if len(uris) == 0 {
+		return fmt.Errorf("no database to compare with: set database.uri of a postgresql package or pass --uri")
+	}
+
+	report := verifyReport{Drifts: []migrations.Drift{}}
+	for _, uri := range uris {
+		conn, err := pgx.Connect(ctx, expandURI(uri))
+		if err != nil {
+			return fmt.Errorf("database: connection error: %s", err)
+		}
+		db, err := migrations.LoadDatabase(ctx, conn)
+		conn.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("database: %s", err)
+		}
+		report.Drifts = append(report.Drifts, migrations.Verify(schemas[uri], db)...)
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, d := range report.Drifts {
+			fmt.Fprintln(stdout, d)
+		}
+	}
+	if len(report.Drifts) > 0 {
+		return errDrift
+	}
+	return nil
+}
+
+// expandURI replaces the environment variables in a database URI, as vet
+// does for database.uri.
+func expandURI(uri string) string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	return shfmt.Replace(uri, env)
+}
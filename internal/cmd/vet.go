@@ -12,21 +12,30 @@ import (
 	"path/filepath"
 	"runtime/trace"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/cel-go/cel"
-	"github.com/google/cel-go/ext"
+	celext "github.com/google/cel-go/ext"
 	"github.com/jackc/pgx/v5"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/sqlc-dev/sqlc/internal/compiler"
 	"github.com/sqlc-dev/sqlc/internal/config"
 	"github.com/sqlc-dev/sqlc/internal/debug"
+	"github.com/sqlc-dev/sqlc/internal/engine/postgresql"
+	"github.com/sqlc-dev/sqlc/internal/ext"
+	"github.com/sqlc-dev/sqlc/internal/ext/process"
+	"github.com/sqlc-dev/sqlc/internal/ext/wasm"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
 	"github.com/sqlc-dev/sqlc/internal/opts"
 	"github.com/sqlc-dev/sqlc/internal/plugin"
 	"github.com/sqlc-dev/sqlc/internal/shfmt"
+	"github.com/sqlc-dev/sqlc/internal/sql/sqlpath"
 	"github.com/sqlc-dev/sqlc/internal/vet"
 )
 
@@ -35,6 +44,7 @@ var ErrFailedChecks = errors.New("failed checks")
 var pjson = protojson.UnmarshalOptions{AllowPartial: true, DiscardUnknown: true}
 
 const RuleDbPrepare = "sqlc/db-prepare"
+const RuleMigrationSafety = "sqlc/migration-safety"
 const QueryFlagSqlcVetDisable = "@sqlc-vet-disable"
 
 func NewCmdVet() *cobra.Command {
@@ -75,7 +85,7 @@ func Vet(ctx context.Context, e Env, dir, filename string, stderr io.Writer) err
 
 	env, err := cel.NewEnv(
 		cel.StdLib(),
-		ext.Strings(ext.StringsVersion(1)),
+		celext.Strings(celext.StringsVersion(1)),
 		cel.Types(
 			&vet.Config{},
 			&vet.Query{},
@@ -100,7 +110,8 @@ func Vet(ctx context.Context, e Env, dir, filename string, stderr io.Writer) err
 	}
 
 	rules := map[string]rule{
-		RuleDbPrepare: {NeedsPrepare: true},
+		RuleDbPrepare:       {NeedsPrepare: true},
+		RuleMigrationSafety: {NeedsSchema: true},
 	}
 
 	for _, c := range conf.Rules {
@@ -110,6 +121,17 @@ func Vet(ctx context.Context, e Env, dir, filename string, stderr io.Writer) err
 		if _, found := rules[c.Name]; found {
 			return fmt.Errorf("type-check error: a rule with the name '%s' already exists", c.Name)
 		}
+		if c.Plugin != "" {
+			if c.Rule != "" {
+				return fmt.Errorf("type-check error: %s cannot have both a rule and a plugin", c.Name)
+			}
+			plug, err := findPlugin(*conf, c.Plugin)
+			if err != nil {
+				return fmt.Errorf("type-check error: %s: plugin '%s' does not exist", c.Name, c.Plugin)
+			}
+			rules[c.Name] = rule{Plugin: pluginChecker(plug), Message: c.Msg}
+			continue
+		}
 		if c.Rule == "" {
 			return fmt.Errorf("type-check error: %s is empty", c.Name)
 		}
@@ -133,6 +155,10 @@ func Vet(ctx context.Context, e Env, dir, filename string, stderr io.Writer) err
 		rules[c.Name] = rule
 	}
 
+	var cache *compiler.Cache
+	if e.Cache {
+		cache = compiler.NewCache()
+	}
 	c := checker{
 		Rules:      rules,
 		Conf:       conf,
@@ -141,6 +167,8 @@ func Vet(ctx context.Context, e Env, dir, filename string, stderr io.Writer) err
 		Envmap:     map[string]string{},
 		Stderr:     stderr,
 		NoDatabase: e.NoDatabase,
+		Cache:      cache,
+		Linted:     map[string]bool{},
 	}
 	errored := false
 	for _, sql := range conf.SQL {
@@ -363,9 +391,25 @@ func (me *mysqlExplainer) Explain(ctx context.Context, query string, args ...*pl
 
 type rule struct {
 	Program      *cel.Program
+	Plugin       ext.Checker
 	Message      string
 	NeedsPrepare bool
 	NeedsExplain bool
+	NeedsSchema  bool
+}
+
+func pluginChecker(plug *config.Plugin) ext.Checker {
+	if plug.Process != nil {
+		return &process.Runner{
+			Cmd: plug.Process.Cmd,
+			Env: plug.Env,
+		}
+	}
+	return &wasm.Runner{
+		URL:    plug.WASM.URL,
+		SHA256: plug.WASM.SHA256,
+		Env:    plug.Env,
+	}
 }
 
 type checker struct {
@@ -376,6 +420,9 @@ type checker struct {
 	Envmap     map[string]string
 	Stderr     io.Writer
 	NoDatabase bool
+	Cache      *compiler.Cache
+	Linter     *migrations.Linter
+	Linted     map[string]bool
 }
 
 func (c *checker) DSN(dsn string) (string, error) {
@@ -411,7 +458,7 @@ func (c *checker) checkSQL(ctx context.Context, s config.SQL) error {
 		Debug: debug.Debug,
 	}
 
-	result, failed := parse(ctx, name, c.Dir, s, combo, parseOpts, c.Stderr)
+	result, failed := parse(ctx, name, c.Dir, s, combo, parseOpts, c.Cache, c.Stderr)
 	if failed {
 		return ErrFailedChecks
 	}
@@ -469,8 +516,56 @@ func (c *checker) checkSQL(ctx context.Context, s config.SQL) error {
 	}
 
 	errored := false
+	for _, name := range s.Rules {
+		if rule, ok := c.Rules[name]; !ok || !rule.NeedsSchema {
+			continue
+		}
+		if err := c.checkSchema(s); err != nil {
+			if !errors.Is(err, ErrFailedChecks) {
+				return err
+			}
+			errored = true
+		}
+	}
+
 	req := codeGenRequest(result, combo)
 	cfg := vetConfig(req)
+
+	// Rules implemented by plugins are sent all the queries at once and run
+	// while the CEL rules are evaluated. On every return, the plugins are
+	// canceled then waited for.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	pctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var queries []*vet.Query
+	for i, query := range req.Queries {
+		if !result.Queries[i].Flags[QueryFlagSqlcVetDisable] {
+			queries = append(queries, vetQuery(query))
+		}
+	}
+	var pluginRules []string
+	for _, name := range s.Rules {
+		if rule, ok := c.Rules[name]; ok && rule.Plugin != nil {
+			pluginRules = append(pluginRules, name)
+		}
+	}
+	pluginResps := make([]*vet.CheckResponse, len(pluginRules))
+	pluginErrs := make([]error, len(pluginRules))
+	for i, name := range pluginRules {
+		i, name := i, name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pluginResps[i], pluginErrs[i] = c.Rules[name].Plugin.Check(pctx, &vet.CheckRequest{
+				Rule:    name,
+				Config:  cfg,
+				Queries: queries,
+				Catalog: req.Catalog,
+			})
+		}()
+	}
+
 	for i, query := range req.Queries {
 		if result.Queries[i].Flags[QueryFlagSqlcVetDisable] {
 			if debug.Active {
@@ -489,6 +584,9 @@ func (c *checker) checkSQL(ctx context.Context, s config.SQL) error {
 			if !ok {
 				return fmt.Errorf("type-check error: a rule with the name '%s' does not exist", name)
 			}
+			if rule.Plugin != nil {
+				continue
+			}
 
 			if rule.NeedsPrepare {
 				if prep == nil {
@@ -553,12 +651,88 @@ func (c *checker) checkSQL(ctx context.Context, s config.SQL) error {
 			}
 		}
 	}
+
+	wg.Wait()
+	filenames := map[string]string{}
+	for _, query := range req.Queries {
+		filenames[query.Name] = query.Filename
+	}
+	for i, name := range pluginRules {
+		if err := pluginErrs[i]; err != nil {
+			fmt.Fprintf(c.Stderr, "%s: error running plugin: %s\n", name, err)
+			errored = true
+			continue
+		}
+		for _, finding := range pluginResps[i].Findings {
+			msg := finding.Message
+			if msg == "" {
+				msg = c.Rules[name].Message
+			}
+			prefix := name
+			if filename, ok := filenames[finding.Query]; ok {
+				prefix = fmt.Sprintf("%s: %s: %s", filename, finding.Query, name)
+			}
+			if msg == "" {
+				fmt.Fprintf(c.Stderr, "%s\n", prefix)
+			} else {
+				fmt.Fprintf(c.Stderr, "%s: %s\n", prefix, msg)
+			}
+			errored = true
+		}
+	}
 	if errored {
 		return ErrFailedChecks
 	}
 	return nil
 }
 
+// checkSchema runs the sqlc/migration-safety rule on the schema files of s
+// that no other package has listed yet.
+func (c *checker) checkSchema(s config.SQL) error {
+	if s.Engine != config.EnginePostgreSQL {
+		return fmt.Errorf("%s: unsupported engine: %s", RuleMigrationSafety, s.Engine)
+	}
+	files, err := sqlpath.Glob(s.Schema)
+	if err != nil {
+		return err
+	}
+	if c.Linter == nil {
+		c.Linter = migrations.NewLinter()
+	}
+	parser := postgresql.NewParser()
+	merr := multierr.New()
+	// The files are applied from the last one to the first, see
+	// compiler.ParseCatalog.
+	for i := len(files) - 1; i >= 0; i-- {
+		filename := files[i]
+		if c.Linted[filename] {
+			continue
+		}
+		c.Linted[filename] = true
+		blob, err := os.ReadFile(filename)
+		if err != nil {
+			merr.Add(filename, "", 0, err)
+			continue
+		}
+		contents := migrations.RemoveRollbackStatements(string(blob))
+		stmts, err := parser.Parse(strings.NewReader(contents))
+		if err != nil {
+			merr.Add(filename, contents, 0, err)
+			continue
+		}
+		for _, finding := range c.Linter.Lint(contents, stmts) {
+			merr.Add(filename, contents, finding.Location, fmt.Errorf("%s: %s", RuleMigrationSafety, finding.Message))
+		}
+	}
+	for _, fileErr := range merr.Errs() {
+		printFileErr(c.Stderr, c.Dir, fileErr)
+	}
+	if len(merr.Errs()) > 0 {
+		return ErrFailedChecks
+	}
+	return nil
+}
+
 func vetConfig(req *plugin.CodeGenRequest) *vet.Config {
 	return &vet.Config{
 		Version: req.Settings.Version,
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+	"github.com/sqlc-dev/sqlc/internal/vet"
+)
+
+// envVetPlugin makes the test binary run as the process plugin of a vet rule,
+// in the mode it is set to.
+const envVetPlugin = "SQLC_TEST_VET_PLUGIN"
+
+func TestMain(m *testing.M) {
+	mode := os.Getenv(envVetPlugin)
+	if mode == "" {
+		os.Exit(m.Run())
+	}
+	if err := runVetPlugin(mode, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// runVetPlugin reports the queries without a WHERE clause, and a finding
+// that describes the request.
+func runVetPlugin(mode string, r io.Reader, w io.Writer) error {
+	if mode == "fail" {
+		return fmt.Errorf("no rules today")
+	}
+	blob, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var req vet.CheckRequest
+	if err := proto.Unmarshal(blob, &req); err != nil {
+		return err
+	}
+	var resp vet.CheckResponse
+	var names, tables []string
+	for _, q := range req.Queries {
+		names = append(names, q.Name)
+		if !strings.Contains(q.Sql, "WHERE") {
+			resp.Findings = append(resp.Findings, &vet.Finding{Query: q.Name})
+		}
+	}
+	for _, s := range req.Catalog.GetSchemas() {
+		if s.Name != req.Catalog.DefaultSchema {
+			continue
+		}
+		for _, t := range s.Tables {
+			tables = append(tables, t.Rel.Name)
+		}
+	}
+	sort.Strings(tables)
+	resp.Findings = append(resp.Findings, &vet.Finding{
+		Message: fmt.Sprintf("rule %s, engine %s, queries %s, tables %s",
+			req.Rule, req.Config.GetEngine(), strings.Join(names, " "), strings.Join(tables, " ")),
+	})
+	blob, err = proto.Marshal(&resp)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(blob)
+	return err
+}
+
+func vetTestConfig(t *testing.T, rules string) string {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(`version: '2'
+plugins:
+  - name: checker
+    env: [%s]
+    process:
+      cmd: %s
+rules:
+  - name: no-where
+    plugin: checker
+    message: query without a WHERE clause
+  - name: paginate
+    rule: query.cmd == "many"
+    message: paginate the rows
+sql:
+  - schema: schema.sql
+    queries: query.sql
+    engine: postgresql
+    rules: [%s]
+`, envVetPlugin, exe, rules)
+}
+
+const (
+	vetTestSchema = "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL);\n"
+	vetTestQuery  = `-- name: GetBook :one
+-- -- timeout : 500ms
+SELECT id, title FROM books WHERE id = $1;
+
+-- name: ListBooks :many
+-- -- timeout : 500ms
+SELECT * FROM books;
+
+-- name: DeleteBooks :exec
+-- @sqlc-vet-disable
+-- -- timeout : 500ms
+DELETE FROM books;
+`
+)
+
+func TestVetPluginRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"sqlc.yaml":  vetTestConfig(t, "no-where, paginate"),
+		"schema.sql": vetTestSchema,
+		"query.sql":  vetTestQuery,
+	})
+	t.Setenv(envVetPlugin, "check")
+	var stderr bytes.Buffer
+	err := Vet(context.Background(), Env{Debug: opts.DebugFromEnv()}, dir, "sqlc.yaml", &stderr)
+	if !errors.Is(err, ErrFailedChecks) {
+		t.Fatalf("got %v, want %v\n%s", err, ErrFailedChecks, stderr.String())
+	}
+	got := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	sort.Strings(got)
+	want := []string{
+		"no-where: rule no-where, engine postgresql, queries GetBook ListBooks, tables books",
+		"query.sql: ListBooks: no-where: query without a WHERE clause",
+		"query.sql: ListBooks: paginate: paginate the rows",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	t.Setenv(envVetPlugin, "fail")
+	stderr.Reset()
+	err = Vet(context.Background(), Env{Debug: opts.DebugFromEnv()}, dir, "sqlc.yaml", &stderr)
+	if !errors.Is(err, ErrFailedChecks) {
+		t.Fatalf("failing plugin: got %v, want %v", err, ErrFailedChecks)
+	}
+	if !strings.Contains(stderr.String(), "no-where: error running plugin: process: error running command no rules today") {
+		t.Errorf("failing plugin:\n%s", stderr.String())
+	}
+}
+
+// blockingChecker is a plugin rule that runs until it is canceled.
+type blockingChecker struct {
+	done atomic.Bool
+}
+
+func (b *blockingChecker) Check(ctx context.Context, req *vet.CheckRequest) (*vet.CheckResponse, error) {
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	b.done.Store(true)
+	return nil, ctx.Err()
+}
+
+// TestVetPluginCanceled checks that the plugin rules of a package are
+// canceled and waited for when the package fails to be checked.
+func TestVetPluginCanceled(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"schema.sql": vetTestSchema,
+		"query.sql":  vetTestQuery,
+	})
+	plug := &blockingChecker{}
+	s := config.SQL{
+		Engine:  config.EnginePostgreSQL,
+		Schema:  []string{"schema.sql"},
+		Queries: []string{"query.sql"},
+		Rules:   []string{"blocking", "missing"},
+	}
+	var stderr bytes.Buffer
+	c := checker{
+		Rules:  map[string]rule{"blocking": {Plugin: plug}},
+		Conf:   &config.Config{SQL: []config.SQL{s}},
+		Dir:    dir,
+		Envmap: map[string]string{},
+		Stderr: &stderr,
+		Linted: map[string]bool{},
+	}
+	err := c.checkSQL(context.Background(), s)
+	if err == nil || !strings.Contains(err.Error(), "a rule with the name 'missing' does not exist") {
+		t.Fatalf("got %v, want an error for the missing rule\n%s", err, stderr.String())
+	}
+	if !plug.done.Load() {
+		t.Errorf("the plugin rule was still running after checkSQL returned")
+	}
+}
@@ -0,0 +1,231 @@
+package golang_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/cmd"
+)
+
+const codegenConfig = `version: '2'
+sql:
+  - schema: schema.sql
+    queries: query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: db
+        out: db
+`
+
+// generate runs sqlc generate on a package made of schema and queries, and
+// returns the generated files by name.
+func generate(t *testing.T, schema, queries string) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"sqlc.yaml":  codegenConfig,
+		"schema.sql": schema,
+		"query.sql":  queries,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stderr bytes.Buffer
+	output, err := cmd.Generate(context.Background(), cmd.Env{}, dir, "sqlc.yaml", &stderr)
+	if err != nil {
+		t.Fatalf("generate: %s\n%s", err, stderr.String())
+	}
+	files := map[string]string{}
+	for path, contents := range output {
+		files[filepath.Base(path)] = contents
+	}
+	return files
+}
+
+func assertContains(t *testing.T, name, contents string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(contents, w) {
+			t.Errorf("%s: missing %q in\n%s", name, w, contents)
+		}
+	}
+}
+
+func TestColumnHints(t *testing.T) {
+	schema := `CREATE TABLE books (
+  id       BIGINT PRIMARY KEY,
+  price    INT NOT NULL,
+  subtitle TEXT,
+  metadata JSONB
+);
+`
+	const query = `-- name: GetBookStats :one
+-- -- timeout : 500ms
+%s
+SELECT sum(price) AS total, subtitle, metadata AS meta FROM books WHERE id = @id GROUP BY subtitle, metadata;
+
+-- name: GetTotal :one
+-- -- timeout : 500ms
+%s
+SELECT sum(price) AS total FROM books WHERE id = @id;
+`
+	plain := generate(t, schema, strings.ReplaceAll(query, "%s\n", ""))["query.sql.go"]
+	assertContains(t, "without hints", plain,
+		"\tTotal    int64\n\tSubtitle *string\n\tMeta     []byte\n",
+		") GetTotal(ctx context.Context, id int64) (**int64, error) {",
+	)
+
+	hinted := generate(t, schema, strings.Replace(strings.Replace(query,
+		"%s", "-- -- column: total nullable\n-- -- column: subtitle notnull\n-- -- column: meta nullable type:*github.com/acme/types.Meta", 1),
+		"%s", "-- -- column: total notnull type:int", 1))["query.sql.go"]
+	assertContains(t, "with hints", hinted,
+		"\tTotal    *int64\n\tSubtitle string\n\tMeta     *types.Meta\n",
+		"\t\"github.com/acme/types\"\n",
+		") GetTotal(ctx context.Context, id int64) (*int, error) {",
+	)
+}
+
+func TestCompositeModels(t *testing.T) {
+	files := generate(t, `CREATE TYPE mood AS ENUM ('happy', 'sad');
+
+CREATE TYPE visit AS (
+  place TEXT,
+  moods mood[],
+  at    TIMESTAMPTZ
+);
+
+CREATE TABLE people (
+  id     BIGINT PRIMARY KEY,
+  visits visit[] NOT NULL,
+  last   visit
+);
+`, `-- name: GetPerson :one
+-- -- timeout : 500ms
+SELECT * FROM people WHERE id = $1;
+`)
+	models := files["models.go"]
+	assertContains(t, "models.go", models,
+		"import (\n\t\"context\"\n\t\"database/sql/driver\"\n\t\"fmt\"\n\t\"time\"\n\n\t\"github.com/jackc/pgx/v5\"\n)\n",
+		// Composite columns map to the struct of the type.
+		"type Person struct {\n\tID     int64   `json:\"id\"`\n\tVisits []Visit `json:\"visits\"`\n\tLast   *Visit  `json:\"last\"`\n}\n",
+		"type Visit struct {\n\tPlace *string    `json:\"place\"`\n\tMoods []Mood     `json:\"moods\"`\n\tAt    *time.Time `json:\"at\"`\n}\n",
+		"func (c Visit) Index(i int) any {\n\tswitch i {\n\tcase 0:\n\t\treturn c.Place\n\tcase 1:\n\t\treturn c.Moods\n\tcase 2:\n\t\treturn c.At\n\t}\n\treturn nil\n}\n",
+		"func (c *Visit) ScanIndex(i int) any {\n\tswitch i {\n\tcase 0:\n\t\treturn &c.Place\n\tcase 1:\n\t\treturn &c.Moods\n\tcase 2:\n\t\treturn &c.At\n\t}\n\treturn nil\n}\n",
+		"func (c Visit) IsNull() bool {",
+		"func (c *Visit) ScanNull() error {",
+		// The enum is loaded before the composite type that uses it.
+		"var compositeTypeNames = []string{\n\t\"mood\",\n\t\"mood[]\",\n\t\"visit\",\n\t\"visit[]\",\n}\n",
+		"func RegisterCompositeTypes(ctx context.Context, conn *pgx.Conn) error {",
+	)
+	assertContains(t, "query.sql.go", files["query.sql.go"],
+		"err := row.Scan(&i.ID, &i.Visits, &i.Last)",
+	)
+}
+
+func TestCacheKeyHelpers(t *testing.T) {
+	schema := "CREATE TABLE books (id BIGINT PRIMARY KEY, title TEXT NOT NULL, tags TEXT[] NOT NULL);\n"
+	plain := generate(t, schema, `-- name: GetBook :one
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT * FROM books WHERE id = $1;
+
+-- name: ListByTags :many
+-- -- timeout : 500ms
+SELECT * FROM books WHERE tags = $1;
+`)["query.sql.go"]
+	for _, unwanted := range []string{"func keySlice", "func keyJSON", "\t\"sort\"\n", "\t\"strconv\"\n"} {
+		if strings.Contains(plain, unwanted) {
+			t.Errorf("without slices in cache keys: unexpected %q in\n%s", unwanted, plain)
+		}
+	}
+	assertContains(t, "without slices in cache keys", plain, "func hashIfLong(")
+
+	slices := generate(t, schema, `-- name: GetBook :one
+-- -- timeout : 500ms
+SELECT * FROM books WHERE id = $1;
+
+-- name: ListByIDs :many
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT * FROM books WHERE id = ANY(sqlc.slice(ids)::bigint[]) AND title = @title;
+`)["query.sql.go"]
+	assertContains(t, "with a slice in a cache key", slices,
+		"keySlice(arg.Ids, false)",
+		"func keySlice[T any](v []T, sorted bool) string {",
+		"func keyJSON(v any) string {",
+		"\t\"sort\"\n",
+		"\t\"strconv\"\n",
+		"\t\"strings\"\n",
+	)
+}
+
+func TestCacheFingerprint(t *testing.T) {
+	const schema = `CREATE TYPE visit AS (
+  place TEXT,
+  at    TIMESTAMPTZ
+);
+
+CREATE TABLE people (
+  id   BIGINT PRIMARY KEY,
+  last visit,
+  meta JSONB
+);
+
+COMMENT ON COLUMN people.meta IS '@go-type: github.com/acme/types.Meta';
+`
+	const queries = `-- name: GetPerson :one
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT * FROM people WHERE id = $1;
+
+-- name: GetMeta :one
+-- -- timeout : 500ms
+-- -- cache : 1m
+SELECT meta FROM people WHERE id = $1;
+`
+	label := regexp.MustCompile(`"db:(GetPerson|GetMeta):[0-9a-f]{8}:"`)
+	labels := func(schema string) map[string]string {
+		t.Helper()
+		code := generate(t, schema, queries)["query.sql.go"]
+		found := map[string]string{}
+		for _, m := range label.FindAllStringSubmatch(code, -1) {
+			found[m[1]] = m[0]
+		}
+		if len(found) != 2 {
+			t.Fatalf("expected the labels of GetPerson and GetMeta, got %q in\n%s", found, code)
+		}
+		return found
+	}
+
+	base := labels(schema)
+	if diff := cmp.Diff(base, labels(schema)); diff != "" {
+		t.Errorf("labels are not stable (-first +second):\n%s", diff)
+	}
+	for _, tc := range []struct {
+		name    string
+		schema  string
+		queries []string
+	}{
+		// The Person struct stays the same, only its Visit field changes.
+		{"composite field", strings.Replace(schema, "place TEXT", "place INT", 1), []string{"GetPerson"}},
+		// The Go type is still types.Meta, from another package.
+		{"json type", strings.Replace(schema, "github.com/acme/types.Meta", "github.com/acme/v2/types.Meta", 1), []string{"GetPerson", "GetMeta"}},
+	} {
+		got := labels(tc.schema)
+		for _, q := range tc.queries {
+			if got[q] == base[q] {
+				t.Errorf("%s: label of %s did not change: %s", tc.name, q, got[q])
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package golang
+
+import (
+	"sort"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/sdk"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// CompositeType is the Go struct generated for a composite type. pgx encodes
+// and decodes it field by field, through the pgtype.CompositeIndexGetter and
+// pgtype.CompositeIndexScanner methods generated along with it.
+type CompositeType struct {
+	Name    string
+	Comment string
+	Fields  []Field
+}
+
+// compositeTypeName returns the Go name of a composite type, prefixed with
+// its schema outside of the default schema, as enums are.
+func compositeTypeName(req *plugin.CodeGenRequest, schema, name string) string {
+	if schema == req.Catalog.DefaultSchema {
+		return StructName(name, req.Settings)
+	}
+	return StructName(schema+"_"+name, req.Settings)
+}
+
+// sqlTypeName returns the name pgx loads a user-defined type by.
+func sqlTypeName(req *plugin.CodeGenRequest, schema, name string) string {
+	if schema == req.Catalog.DefaultSchema {
+		return name
+	}
+	return schema + "." + name
+}
+
+// isCompositeColumn reports whether col is of a composite type.
+func isCompositeColumn(req *plugin.CodeGenRequest, col *plugin.Column) bool {
+	if col == nil || col.Type == nil {
+		return false
+	}
+	rel, err := parseIdentifierString(sdk.DataType(col.Type))
+	if err != nil {
+		return false
+	}
+	if rel.Schema == "" {
+		rel.Schema = req.Catalog.DefaultSchema
+	}
+	for _, schema := range req.Catalog.Schemas {
+		if schema.Name != rel.Schema {
+			continue
+		}
+		for _, ct := range schema.CompositeTypes {
+			if ct.Name == rel.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func buildCompositeTypes(req *plugin.CodeGenRequest) []CompositeType {
+	var cts []CompositeType
+	for _, schema := range req.Catalog.Schemas {
+		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
+			continue
+		}
+		for _, ct := range schema.CompositeTypes {
+			c := CompositeType{
+				Name:    compositeTypeName(req, schema.Name, ct.Name),
+				Comment: ct.Comment,
+			}
+			for _, column := range ct.Columns {
+				tags := map[string]string{}
+				if req.Settings.Go.EmitDbTags {
+					tags["db"] = column.Name
+				}
+				tags["json"] = JSONTagName(column.Name, req.Settings)
+				c.Fields = append(c.Fields, Field{
+					Name:   StructName(column.Name, req.Settings),
+					DBName: column.Name,
+					Type:   goType(req, column),
+					Tags:   tags,
+					Column: column,
+				})
+			}
+			cts = append(cts, c)
+		}
+	}
+	if len(cts) > 0 {
+		sort.Slice(cts, func(i, j int) bool { return cts[i].Name < cts[j].Name })
+	}
+	return cts
+}
+
+// compositeTypeNames returns the types to load into the pgx type map for the
+// composite types to be scanned and bound: the enums their fields use, then
+// every composite type after the composite types of its fields, each
+// followed by its array type.
+func compositeTypeNames(req *plugin.CodeGenRequest) []string {
+	enums := map[string]bool{}
+	composites := map[string]*plugin.CompositeType{}
+	var order []string
+	for _, schema := range req.Catalog.Schemas {
+		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
+			continue
+		}
+		for _, enum := range schema.Enums {
+			enums[sqlTypeName(req, schema.Name, enum.Name)] = true
+		}
+		for _, ct := range schema.CompositeTypes {
+			name := sqlTypeName(req, schema.Name, ct.Name)
+			composites[name] = ct
+			order = append(order, name)
+		}
+	}
+	sort.Strings(order)
+
+	fieldType := func(col *plugin.Column) string {
+		schema := col.Type.Schema
+		if schema == "" {
+			schema = req.Catalog.DefaultSchema
+		}
+		return sqlTypeName(req, schema, col.Type.Name)
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range order {
+		for _, col := range composites[name].Columns {
+			if typ := fieldType(col); enums[typ] {
+				add(typ)
+				if col.IsArray {
+					add(typ + "[]")
+				}
+			}
+		}
+	}
+	var visit func(name string)
+	visit = func(name string) {
+		if seen[name] {
+			return
+		}
+		// Mark the type first, so that a cycle can't recurse forever.
+		seen[name] = true
+		for _, col := range composites[name].Columns {
+			if typ := fieldType(col); composites[typ] != nil {
+				visit(typ)
+			}
+		}
+		names = append(names, name, name+"[]")
+	}
+	for _, name := range order {
+		visit(name)
+	}
+	return names
+}
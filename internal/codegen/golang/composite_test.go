@@ -0,0 +1,50 @@
+package golang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+func TestCompositeTypeNames(t *testing.T) {
+	req := &plugin.CodeGenRequest{
+		Settings: &plugin.Settings{Go: &plugin.GoCode{}},
+		Catalog: &plugin.Catalog{
+			DefaultSchema: "public",
+			Schemas: []*plugin.Schema{
+				{
+					Name:  "public",
+					Enums: []*plugin.Enum{{Name: "mood"}},
+					CompositeTypes: []*plugin.CompositeType{
+						{
+							Name: "a_visit",
+							Columns: []*plugin.Column{
+								{Name: "home", Type: &plugin.Identifier{Schema: "geo", Name: "address"}},
+								{Name: "moods", Type: &plugin.Identifier{Name: "mood"}, IsArray: true},
+							},
+						},
+					},
+				},
+				{
+					Name: "geo",
+					CompositeTypes: []*plugin.CompositeType{
+						{
+							Name: "address",
+							Columns: []*plugin.Column{
+								{Name: "street", Type: &plugin.Identifier{Name: "text"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	want := "mood,mood[],geo.address,geo.address[],a_visit,a_visit[]"
+	if got := strings.Join(compositeTypeNames(req), ","); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got := compositeTypeName(req, "geo", "address"); got != "GeoAddress" {
+		t.Errorf("want GeoAddress, got %s", got)
+	}
+}
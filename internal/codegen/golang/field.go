@@ -18,6 +18,8 @@ type Field struct {
 	Column  *plugin.Column
 	// EmbedFields contains the embedded fields that require scanning.
 	EmbedFields []Field
+	// Composite is true if the field holds a composite type value.
+	Composite bool
 }
 
 func (gf Field) Tag() string {
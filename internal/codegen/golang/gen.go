@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"go/format"
+	"os"
 	"strings"
 	"text/template"
 
@@ -26,6 +27,11 @@ type tmplCtx struct {
 	DumpLoader   *DumpLoader
 	RawSchemaSQL string
 
+	// CompositeTypes are registered on a connection by loading
+	// CompositeTypeNames, in order.
+	CompositeTypes     []CompositeType
+	CompositeTypeNames []string
+
 	// TODO: Race conditions
 	SourceName string
 
@@ -40,12 +46,26 @@ type tmplCtx struct {
 	EmitAllEnumValues         bool
 	UsesCopyFrom              bool
 	UsesBatch                 bool
+	UsesTypedJSON             bool
+	UsesDecimal               bool
 }
 
 func (t *tmplCtx) OutputQuery(sourceName string) bool {
 	return t.SourceName == sourceName
 }
 
+// UsesKeySlice is used by WPgx only.
+func (t *tmplCtx) UsesKeySlice() bool {
+	slice, _ := cacheKeyHelpers(t.GoQueries, t.SourceName)
+	return slice
+}
+
+// UsesKeyJSON is used by WPgx only.
+func (t *tmplCtx) UsesKeyJSON() bool {
+	_, json := cacheKeyHelpers(t.GoQueries, t.SourceName)
+	return json
+}
+
 func (t *tmplCtx) codegenDbarg() string {
 	if t.EmitMethodsWithDBArgument {
 		return "db DBTX, "
@@ -136,10 +156,14 @@ func Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenR
 
 func generate(req *plugin.CodeGenRequest, enums []Enum, structs []Struct, queries []Query) (*plugin.CodeGenResponse, error) {
 	i := &importer{
-		Settings: req.Settings,
-		Queries:  queries,
-		Enums:    enums,
-		Structs:  structs,
+		Settings:  req.Settings,
+		Queries:   queries,
+		Enums:     enums,
+		Structs:   structs,
+		JSONTypes: jsonGoTypes(req),
+		Decimal:   usesDecimal(req.Settings, structs, queries),
+
+		CompositeTypes: buildCompositeTypes(req),
 	}
 
 	golang := req.Settings.Go
@@ -159,11 +183,15 @@ func generate(req *plugin.CodeGenRequest, enums []Enum, structs []Struct, querie
 		EmitAllEnumValues:         true,
 		UsesCopyFrom:              usesCopyFrom(queries),
 		UsesBatch:                 usesBatch(queries),
+		UsesTypedJSON:             usesTypedJSON(structs, queries),
+		UsesDecimal:               i.Decimal,
 		SQLDriver:                 parseDriver(golang.SqlPackage),
 		Q:                         "`",
 		Package:                   golang.Package,
 		Enums:                     enums,
 		Structs:                   structs,
+		CompositeTypes:            i.CompositeTypes,
+		CompositeTypeNames:        compositeTypeNames(req),
 		SqlcVersion:               req.SqlcVersion,
 		DumpLoader:                dumploader,
 		RawSchemaSQL:              strings.Join(req.Catalog.GetRawSqls(), "\n"),
@@ -226,7 +254,7 @@ func generate(req *plugin.CodeGenRequest, enums []Enum, structs []Struct, querie
 		}
 		code, err := format.Source(b.Bytes())
 		if err != nil {
-			fmt.Println(b.String())
+			fmt.Fprintln(os.Stderr, b.String())
 			return fmt.Errorf("source error: %w", err)
 		}
 
@@ -285,6 +313,12 @@ func generate(req *plugin.CodeGenRequest, enums []Enum, structs []Struct, querie
 		}
 	}
 
+	if tctx.UsesTypedJSON {
+		if err := execute(jsonFileName, "jsonFile"); err != nil {
+			return nil, err
+		}
+	}
+
 	files := map[string]struct{}{}
 	for _, gq := range queries {
 		files[gq.SourceName] = struct{}{}
@@ -316,6 +350,58 @@ func usesCopyFrom(queries []Query) bool {
 	return false
 }
 
+// usesGoType reports whether a model, parameter or result field has a type
+// that matches, ignoring slice and pointer prefixes.
+func usesGoType(structs []Struct, queries []Query, match func(string) bool) bool {
+	matches := func(typ string) bool {
+		return match(strings.TrimLeft(typ, "[]*"))
+	}
+	for _, s := range structs {
+		for _, f := range s.Fields {
+			if matches(f.Type) {
+				return true
+			}
+		}
+	}
+	for _, q := range queries {
+		if q.hasRetType() {
+			if matches(q.Ret.Type()) {
+				return true
+			}
+			if q.Ret.IsStruct() {
+				for _, f := range q.Ret.Struct.Fields {
+					if matches(f.Type) {
+						return true
+					}
+				}
+			}
+		}
+		for _, f := range q.Arg.Pairs() {
+			if matches(f.Type) {
+				return true
+			}
+		}
+		if q.Arg.EmitStruct() {
+			for _, f := range q.Arg.Struct.Fields {
+				if matches(f.Type) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// usesDecimal reports whether the package needs the generated Decimal type.
+func usesDecimal(settings *plugin.Settings, structs []Struct, queries []Query) bool {
+	if settings.Go.NumericType != NumericTypeString {
+		return false
+	}
+	return usesGoType(structs, queries, func(typ string) bool {
+		return typ == "Decimal"
+	})
+}
+
 func usesBatch(queries []Query) bool {
 	for _, q := range queries {
 		for _, cmd := range []string{metadata.CmdBatchExec, metadata.CmdBatchMany, metadata.CmdBatchOne} {
@@ -62,6 +62,12 @@ type importer struct {
 	Queries  []Query
 	Enums    []Enum
 	Structs  []Struct
+	// CompositeTypes are the Go structs of the composite types.
+	CompositeTypes []CompositeType
+	// JSONTypes are the Go types of annotated json columns.
+	JSONTypes []string
+	// Decimal is set when the models file holds the generated Decimal type.
+	Decimal bool
 }
 
 func (i *importer) usesType(typ string) bool {
@@ -72,6 +78,13 @@ func (i *importer) usesType(typ string) bool {
 			}
 		}
 	}
+	for _, ct := range i.CompositeTypes {
+		for _, f := range ct.Fields {
+			if hasPrefixIgnoringSliceAndPointerPrefix(f.Type, typ) {
+				return true
+			}
+		}
+	}
 	return false
 }
 
@@ -108,6 +121,12 @@ func (i *importer) Imports(filename string) [][]ImportSpec {
 		return mergeImports(i.copyfromImports())
 	case batchFileName:
 		return mergeImports(i.batchImports())
+	case jsonFileName:
+		return mergeImports(fileImports{Std: []ImportSpec{
+			{Path: "database/sql/driver"},
+			{Path: "encoding/json"},
+			{Path: "fmt"},
+		}})
 	default:
 		return mergeImports(i.queryImports(filename))
 	}
@@ -160,7 +179,8 @@ var pqtypeTypes = map[string]struct{}{
 	"pqtype.NullRawMessage": {},
 }
 
-func buildImports(settings *plugin.Settings, queries []Query, uses func(string) bool) (map[string]struct{}, map[ImportSpec]struct{}) {
+func (i *importer) buildImports(queries []Query, uses func(string) bool) (map[string]struct{}, map[ImportSpec]struct{}) {
+	settings := i.Settings
 	pkg := make(map[ImportSpec]struct{})
 	std := make(map[string]struct{})
 
@@ -202,6 +222,10 @@ func buildImports(settings *plugin.Settings, queries []Query, uses func(string)
 		}
 	}
 
+	if uses("decimal.Decimal") {
+		pkg[ImportSpec{Path: "github.com/shopspring/decimal"}] = struct{}{}
+	}
+
 	for typeName := range pqtypeTypes {
 		if uses(typeName) {
 			pkg[ImportSpec{Path: "github.com/sqlc-dev/pqtype"}] = struct{}{}
@@ -230,6 +254,27 @@ func buildImports(settings *plugin.Settings, queries []Query, uses func(string)
 		pkg[ImportSpec{Path: "github.com/google/uuid"}] = struct{}{}
 	}
 
+	// Types of column hints
+	for _, q := range queries {
+		for _, hint := range q.Option.Columns {
+			if hint.GoType == "" {
+				continue
+			}
+			typeName, imp := parseGoTypeSpec(hint.GoType)
+			if imp.Path != "" && uses(strings.TrimPrefix(typeName, "*")) {
+				pkg[imp] = struct{}{}
+			}
+		}
+	}
+
+	// Types of annotated json columns
+	for _, spec := range i.JSONTypes {
+		typeName, imp := parseGoTypeSpec(spec)
+		if imp.Path != "" && uses("JSON["+strings.TrimPrefix(typeName, "*")) {
+			pkg[imp] = struct{}{}
+		}
+	}
+
 	// Custom imports
 	for _, o := range settings.Overrides {
 		if o.GoType.BasicType || o.GoType.TypeName == "" {
@@ -246,7 +291,7 @@ func buildImports(settings *plugin.Settings, queries []Query, uses func(string)
 }
 
 func (i *importer) interfaceImports() fileImports {
-	std, pkg := buildImports(i.Settings, i.Queries, func(name string) bool {
+	std, pkg := i.buildImports(i.Queries, func(name string) bool {
 		for _, q := range i.Queries {
 			if q.hasRetType() {
 				if usesBatch([]Query{q}) {
@@ -271,12 +316,22 @@ func (i *importer) interfaceImports() fileImports {
 }
 
 func (i *importer) modelImports() fileImports {
-	std, pkg := buildImports(i.Settings, nil, i.usesType)
+	std, pkg := i.buildImports(nil, i.usesType)
 
 	if len(i.Enums) > 0 {
 		std["fmt"] = struct{}{}
 		std["database/sql/driver"] = struct{}{}
 	}
+	if i.Decimal {
+		std["fmt"] = struct{}{}
+		std["database/sql/driver"] = struct{}{}
+		std["strconv"] = struct{}{}
+	}
+	if len(i.CompositeTypes) > 0 {
+		std["context"] = struct{}{}
+		std["fmt"] = struct{}{}
+		pkg[ImportSpec{Path: "github.com/jackc/pgx/v5"}] = struct{}{}
+	}
 
 	return sortedImports(std, pkg)
 }
@@ -310,7 +365,7 @@ func (i *importer) queryImports(filename string) fileImports {
 		}
 	}
 
-	std, pkg := buildImports(i.Settings, gq, func(name string) bool {
+	std, pkg := i.buildImports(gq, func(name string) bool {
 		for _, q := range gq {
 			if q.hasRetType() {
 				if q.Ret.EmitStruct() {
@@ -409,6 +464,12 @@ func (i *importer) queryImports(filename string) fileImports {
 
 	if sqlpkg == SQLDriverWPGX {
 		pkg[ImportSpec{Path: "github.com/rs/zerolog/log"}] = struct{}{}
+		if slice, _ := cacheKeyHelpers(gq, filename); slice {
+			// used by keySlice.
+			std["sort"] = struct{}{}
+			std["strconv"] = struct{}{}
+			std["strings"] = struct{}{}
+		}
 	}
 
 	return sortedImports(std, pkg)
@@ -421,7 +482,7 @@ func (i *importer) copyfromImports() fileImports {
 			copyFromQueries = append(copyFromQueries, q)
 		}
 	}
-	std, pkg := buildImports(i.Settings, copyFromQueries, func(name string) bool {
+	std, pkg := i.buildImports(copyFromQueries, func(name string) bool {
 		for _, q := range copyFromQueries {
 			if q.hasRetType() {
 				if strings.HasPrefix(q.Ret.Type(), name) {
@@ -457,7 +518,7 @@ func (i *importer) batchImports() fileImports {
 			batchQueries = append(batchQueries, q)
 		}
 	}
-	std, pkg := buildImports(i.Settings, batchQueries, func(name string) bool {
+	std, pkg := i.buildImports(batchQueries, func(name string) bool {
 		for _, q := range batchQueries {
 			if q.hasRetType() {
 				if q.Ret.EmitStruct() {
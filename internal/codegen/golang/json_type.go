@@ -0,0 +1,112 @@
+package golang
+
+import (
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/sdk"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// jsonFileName is the file that holds the JSON[T] wrapper.
+const jsonFileName = "json.go"
+
+// jsonGoTypeAnnotation marks a json or jsonb column comment line that names
+// the Go type stored in the column, e.g.
+//
+//	COMMENT ON COLUMN books.metadata IS '@go-type: github.com/acme/types.BookMeta';
+const jsonGoTypeAnnotation = "@go-type:"
+
+// parseJSONGoType returns the Go type named by the annotation in comment.
+func parseJSONGoType(comment string) string {
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, jsonGoTypeAnnotation) {
+			return strings.TrimSpace(strings.TrimPrefix(line, jsonGoTypeAnnotation))
+		}
+	}
+	return ""
+}
+
+// stripJSONGoType removes the annotation from comment, so that it does not
+// show up in the doc comment of the generated field.
+func stripJSONGoType(comment string) string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), jsonGoTypeAnnotation) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// jsonGoType returns the annotated Go type of a json or jsonb column, found
+// through the comment of the table column it comes from.
+func jsonGoType(req *plugin.CodeGenRequest, col *plugin.Column) string {
+	switch sdk.DataType(col.Type) {
+	case "json", "jsonb", "pg_catalog.json", "pg_catalog.jsonb":
+	default:
+		return ""
+	}
+	if col.IsArray || col.IsSqlcSlice || col.Table == nil {
+		return ""
+	}
+	schemaName := col.Table.Schema
+	if schemaName == "" {
+		schemaName = req.Catalog.DefaultSchema
+	}
+	cname := col.Name
+	if col.OriginalName != "" {
+		cname = col.OriginalName
+	}
+	for _, schema := range req.Catalog.Schemas {
+		if schema.Name != schemaName {
+			continue
+		}
+		for _, table := range schema.Tables {
+			if table.Rel.Name != col.Table.Name {
+				continue
+			}
+			for _, c := range table.Columns {
+				if c.Name == cname {
+					return parseJSONGoType(c.Comment)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// typedJSONType returns the JSON[T] wrapper type of an annotated json or
+// jsonb column, or an empty string if the column is not annotated.
+func typedJSONType(req *plugin.CodeGenRequest, col *plugin.Column) string {
+	spec := jsonGoType(req, col)
+	if spec == "" {
+		return ""
+	}
+	typeName, _ := parseGoTypeSpec(spec)
+	if col.NotNull {
+		return "JSON[" + typeName + "]"
+	}
+	return "*JSON[" + typeName + "]"
+}
+
+// jsonGoTypes returns the Go types of all annotated columns in the catalog.
+func jsonGoTypes(req *plugin.CodeGenRequest) []string {
+	var specs []string
+	for _, schema := range req.Catalog.Schemas {
+		for _, table := range schema.Tables {
+			for _, c := range table.Columns {
+				if spec := parseJSONGoType(c.Comment); spec != "" {
+					specs = append(specs, spec)
+				}
+			}
+		}
+	}
+	return specs
+}
+
+func usesTypedJSON(structs []Struct, queries []Query) bool {
+	return usesGoType(structs, queries, func(typ string) bool {
+		return strings.HasPrefix(typ, "JSON[")
+	})
+}
@@ -0,0 +1,61 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+func TestTypedJSONType(t *testing.T) {
+	req := &plugin.CodeGenRequest{
+		Catalog: &plugin.Catalog{
+			DefaultSchema: "public",
+			Schemas: []*plugin.Schema{
+				{
+					Name: "public",
+					Tables: []*plugin.Table{
+						{
+							Rel: &plugin.Identifier{Name: "books"},
+							Columns: []*plugin.Column{
+								{Name: "metadata", Comment: "Details.\n@go-type: github.com/acme/types.BookMeta"},
+								{Name: "raw", Comment: "Raw payload."},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	books := &plugin.Identifier{Name: "books"}
+	jsonb := &plugin.Identifier{Name: "jsonb"}
+
+	tests := []struct {
+		col  *plugin.Column
+		want string
+	}{
+		{
+			col:  &plugin.Column{Name: "metadata", Table: books, Type: jsonb, NotNull: true},
+			want: "JSON[types.BookMeta]",
+		},
+		{
+			col:  &plugin.Column{Name: "meta", OriginalName: "metadata", Table: books, Type: jsonb},
+			want: "*JSON[types.BookMeta]",
+		},
+		{
+			col:  &plugin.Column{Name: "metadata", Table: books, Type: jsonb, IsArray: true},
+			want: "",
+		},
+		{
+			col:  &plugin.Column{Name: "raw", Table: books, Type: jsonb},
+			want: "",
+		},
+	}
+	for _, tc := range tests {
+		if got := typedJSONType(req, tc.col); got != tc.want {
+			t.Errorf("typedJSONType(%s): want %q, got %q", tc.col.Name, tc.want, got)
+		}
+	}
+	if got := stripJSONGoType(req.Catalog.Schemas[0].Tables[0].Columns[0].Comment); got != "Details." {
+		t.Errorf("stripJSONGoType: want %q, got %q", "Details.", got)
+	}
+}
@@ -2,12 +2,17 @@ package golang
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/sqlc-dev/sqlc/internal/metadata"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
 )
 
 const (
 	WPgxOptionKeyCache        = "cache"
+	WPgxOptionKeyCacheVersion = "cache_version"
 	WPgxOptionKeyInvalidate   = "invalidate"
 	WpgxOptionKeyCountIntent  = "count_intent"
 	WpgxOptionKeyTimeout      = "timeout"
@@ -15,11 +20,16 @@ const (
 )
 
 type WPgxOption struct {
-	Cache        time.Duration
+	Cache time.Duration
+	// CacheVersion replaces the fingerprint of the result struct in the
+	// cache keys of the query when set.
+	CacheVersion int
 	Invalidates  []string
 	CountIntent  bool
 	Timeout      time.Duration
 	AllowReplica bool
+	// Columns are the hints for result columns, keyed by column name.
+	Columns map[string]*metadata.ColumnHint
 }
 
 func parseOption(options map[string]string, queryNames map[string]bool) (rv WPgxOption, err error) {
@@ -33,6 +43,11 @@ func parseOption(options map[string]string, queryNames map[string]bool) (rv WPgx
 			if rv.Cache < 1*time.Millisecond {
 				return rv, fmt.Errorf("cache duration too short: %s", v)
 			}
+		case WPgxOptionKeyCacheVersion:
+			rv.CacheVersion, err = strconv.Atoi(v)
+			if err != nil || rv.CacheVersion < 1 {
+				return rv, fmt.Errorf("invalid cache_version, want a positive integer: %s", v)
+			}
 		case WPgxOptionKeyInvalidate:
 			trimed := strings.Trim(v, " []")
 			fnNames := strings.Split(trimed, ",")
@@ -68,8 +83,57 @@ func parseOption(options map[string]string, queryNames map[string]bool) (rv WPgx
 				return rv, fmt.Errorf("Unknown allow_replica value: %s", v)
 			}
 		default:
-			return rv, fmt.Errorf("Unknown option: %s", k)
+			if !strings.HasPrefix(k, metadata.ColumnHintKey("")) {
+				return rv, fmt.Errorf("Unknown option: %s", k)
+			}
+			var hint *metadata.ColumnHint
+			hint, err = metadata.ParseColumnHint(v)
+			if err != nil {
+				return
+			}
+			if rv.Columns == nil {
+				rv.Columns = make(map[string]*metadata.ColumnHint)
+			}
+			rv.Columns[hint.Column] = hint
 		}
 	}
 	return
 }
+
+// hintedColumns returns the result columns of a query with the nullability
+// set by its column hints.
+func hintedColumns(columns []*plugin.Column, hints map[string]*metadata.ColumnHint) []*plugin.Column {
+	if len(hints) == 0 {
+		return columns
+	}
+	out := make([]*plugin.Column, 0, len(columns))
+	for _, c := range columns {
+		hint, ok := hints[c.Name]
+		if !ok || !(hint.NotNull || hint.Nullable) {
+			out = append(out, c)
+			continue
+		}
+		c = c.CloneVT()
+		c.NotNull = hint.NotNull
+		out = append(out, c)
+	}
+	return out
+}
+
+// parseGoTypeSpec splits a Go type hint such as "github.com/acme/types.Meta"
+// into the type name used in the generated code and its import, which is
+// empty for basic types.
+func parseGoTypeSpec(spec string) (string, ImportSpec) {
+	pointer := strings.HasPrefix(spec, "*")
+	spec = strings.TrimPrefix(spec, "*")
+	typeName := spec
+	var imp ImportSpec
+	if lastDot := strings.LastIndex(spec, "."); lastDot != -1 {
+		typeName = strings.TrimPrefix(spec[strings.LastIndex(spec, "/")+1:], "go-")
+		imp.Path = spec[:lastDot]
+	}
+	if pointer {
+		typeName = "*" + typeName
+	}
+	return typeName, imp
+}
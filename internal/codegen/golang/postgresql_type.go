@@ -33,6 +33,34 @@ func parseIdentifierString(name string) (*plugin.Identifier, error) {
 	}
 }
 
+const (
+	NumericTypePgtype     = "pgtype"
+	NumericTypeString     = "string"
+	NumericTypeShopspring = "shopspring"
+)
+
+// numericType returns the Go type of a numeric column under the numeric_type
+// setting, or an empty string to keep pgtype.Numeric. Money is left alone, as
+// its text form carries a currency symbol.
+func numericType(req *plugin.CodeGenRequest, columnType string, notNull bool) string {
+	if columnType == "money" {
+		return ""
+	}
+	var typ string
+	switch req.Settings.Go.NumericType {
+	case NumericTypeString:
+		typ = "Decimal"
+	case NumericTypeShopspring:
+		typ = "decimal.Decimal"
+	default:
+		return ""
+	}
+	if notNull {
+		return typ
+	}
+	return "*" + typ
+}
+
 func postgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 	if parseDriver(req.Settings.Go.SqlPackage) == SQLDriverWPGX {
 		return wpgxPostgresType(req, col)
@@ -140,6 +168,9 @@ func postgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 		return "sql.NullFloat64" // TODO: Change to sql.NullFloat32 after updating the go.mod file
 
 	case "numeric", "pg_catalog.numeric", "money":
+		if typ := numericType(req, columnType, notNull); typ != "" && driver.IsPGX() {
+			return typ
+		}
 		if driver.IsPGX() {
 			return "pgtype.Numeric"
 		}
@@ -170,6 +201,9 @@ func postgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 	case "json":
 		switch driver {
 		case SQLDriverPGXV5:
+			if typ := typedJSONType(req, col); typ != "" {
+				return typ
+			}
 			return "[]byte"
 		case SQLDriverPGXV4:
 			return "pgtype.JSON"
@@ -186,6 +220,9 @@ func postgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 	case "jsonb":
 		switch driver {
 		case SQLDriverPGXV5:
+			if typ := typedJSONType(req, col); typ != "" {
+				return typ
+			}
 			return "[]byte"
 		case SQLDriverPGXV4:
 			return "pgtype.JSONB"
@@ -651,6 +688,9 @@ func wpgxPostgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 			return "*float32"
 		}
 	case "numeric", "pg_catalog.numeric", "money":
+		if typ := numericType(req, columnType, notNull); typ != "" {
+			return typ
+		}
 		return "pgtype.Numeric"
 	case "boolean", "bool", "pg_catalog.bool":
 		if notNull {
@@ -658,9 +698,10 @@ func wpgxPostgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 		} else {
 			return "*bool"
 		}
-	case "json":
-		return "[]byte"
-	case "jsonb":
+	case "json", "jsonb":
+		if typ := typedJSONType(req, col); typ != "" {
+			return typ
+		}
 		return "[]byte"
 	case "bytea", "blob", "pg_catalog.bytea":
 		return "[]byte"
@@ -819,10 +860,9 @@ func wpgxPostgresType(req *plugin.CodeGenRequest, col *plugin.Column) string {
 			for _, ct := range schema.CompositeTypes {
 				if rel.Name == ct.Name && rel.Schema == schema.Name {
 					if notNull {
-						return "string"
-					} else {
-						return "*string"
+						return compositeTypeName(req, schema.Name, ct.Name)
 					}
+					return "*" + compositeTypeName(req, schema.Name, ct.Name)
 				}
 			}
 		}
@@ -1,6 +1,8 @@
 package golang
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -20,6 +22,8 @@ type QueryValue struct {
 	// Column is kept so late in the generation process around to differentiate
 	// between mysql slices and pg arrays
 	Column *plugin.Column
+	// Composite is true if the value is of a composite type. Only set if Struct==nil.
+	Composite bool
 }
 
 func (v QueryValue) EmitStruct() bool {
@@ -262,11 +266,7 @@ func (v QueryValue) CacheKeySprintf() string {
 	args := make([]string, 0)
 	for _, f := range v.Struct.Fields {
 		format = append(format, "%+v")
-		if strings.HasPrefix(f.Type, "*") {
-			args = append(args, wrapPtrStr(v.Name+"."+f.Name))
-		} else {
-			args = append(args, v.Name+"."+f.Name)
-		}
+		args = append(args, cacheKeyArg(v.Name+"."+f.Name, f.Type, f.Column, f.Composite))
 	}
 	formatStr := `"` + strings.Join(format, ",") + `"`
 	if len(args) <= 3 {
@@ -292,6 +292,8 @@ type Query struct {
 	Invalidates  []InvalidateParam
 	// Used for :copyfrom
 	Table *plugin.Identifier
+	// shape lists the types the result is decoded into, see resultShape.
+	shape string
 }
 
 func (q Query) hasRetType() bool {
@@ -374,8 +376,71 @@ func (q Query) UniqueLabel() string {
 }
 
 // CacheUniqueLabel is used by WPgx only.
+// The label ends with the version of the cached payload, so that entries
+// written for a different result struct are never decoded.
 func (q Query) CacheUniqueLabel() string {
-	return fmt.Sprintf("%s:%s:", q.Pkg, q.MethodName)
+	return fmt.Sprintf("%s:%s:%s:", q.Pkg, q.MethodName, q.cacheVersion())
+}
+
+// cacheVersion returns the cache_version option of q as "v<n>", or else the
+// fingerprint of its result: the first 8 hex digits of the SHA-256 of its
+// shape.
+func (q Query) cacheVersion() string {
+	if q.Option.CacheVersion > 0 {
+		return fmt.Sprintf("v%d", q.Option.CacheVersion)
+	}
+	sum := sha256.Sum256([]byte(q.shape))
+	return hex.EncodeToString(sum[:4])
+}
+
+// resultShape lists the names and types of the fields ret is decoded into,
+// down to the fields of composite types, with the annotated Go types of json
+// columns, as cached payloads of the previous shape don't decode into it.
+// col is the column of a single-column result. req is nil when the types are
+// not resolved.
+func resultShape(req *plugin.CodeGenRequest, composites map[string]CompositeType, ret QueryValue, col *plugin.Column) string {
+	if ret.isEmpty() {
+		return ""
+	}
+	w := shapeWriter{req: req, composites: composites}
+	w.b.WriteString(ret.Type() + "\n")
+	if ret.Struct != nil {
+		w.fields(ret.Struct.Fields, "")
+	} else {
+		w.value(ret.Type(), col, "")
+	}
+	return w.b.String()
+}
+
+type shapeWriter struct {
+	req        *plugin.CodeGenRequest
+	composites map[string]CompositeType
+	b          strings.Builder
+}
+
+func (w *shapeWriter) fields(fields []Field, prefix string) {
+	for _, f := range fields {
+		fmt.Fprintf(&w.b, "%s%s %s\n", prefix, f.Name, f.Type)
+		w.fields(f.EmbedFields, prefix+f.Name+".")
+		w.value(f.Type, f.Column, prefix+f.Name+".")
+	}
+}
+
+// value writes the annotated Go type of a json column and the fields of a
+// composite type. PostgreSQL doesn't allow a composite type to contain
+// itself, so the recursion ends.
+func (w *shapeWriter) value(typ string, col *plugin.Column, prefix string) {
+	if w.req == nil {
+		return
+	}
+	if col != nil && strings.Contains(typ, "JSON[") {
+		if spec := jsonGoType(w.req, col); spec != "" {
+			fmt.Fprintf(&w.b, "%sjson %s\n", prefix, spec)
+		}
+	}
+	if ct, ok := w.composites[strings.TrimLeft(typ, "*[]")]; ok {
+		w.fields(ct.Fields, prefix)
+	}
 }
 
 // ConnType is used by WPgx only.
@@ -407,9 +472,7 @@ func genCacheKeyWithArgName(q Query, argName string) string {
 	}
 	// when it's non-struct parameter, generate inline fmt.Sprintf.
 	if q.Arg.Struct == nil {
-		if q.Arg.IsTypePointer() {
-			argName = wrapPtrStr(argName)
-		}
+		argName = cacheKeyArg(argName, q.Arg.Type(), q.Arg.Column, q.Arg.Composite)
 		fmtStr := `hashIfLong(fmt.Sprintf("%+v",` + argName + `))`
 		return fmt.Sprintf("\"%s\" + %s", prefix, fmtStr)
 	} else {
@@ -417,6 +480,48 @@ func genCacheKeyWithArgName(q Query, argName string) string {
 	}
 }
 
+// cacheKeyArg returns the expression that encodes the argument expr of type
+// typ in a cache key. Slices are length-prefixed, with the elements of a
+// sqlc.slice() sorted as their order does not matter, and composite values
+// are encoded by their field values rather than their pointers.
+func cacheKeyArg(expr, typ string, col *plugin.Column, composite bool) string {
+	switch {
+	case strings.HasPrefix(typ, "[]") && typ != "[]byte":
+		return fmt.Sprintf("keySlice(%s, %t)", expr, col != nil && col.IsSqlcSlice)
+	case composite:
+		return fmt.Sprintf("keyJSON(%s)", expr)
+	case strings.HasPrefix(typ, "*"):
+		return wrapPtrStr(expr)
+	}
+	return expr
+}
+
+// cacheKeyHelpers reports whether the cache keys built in the query file
+// filename call keySlice and keyJSON, which are only emitted when they are.
+// keySlice encodes its elements with keyJSON.
+func cacheKeyHelpers(queries []Query, filename string) (slice, json bool) {
+	for _, q := range queries {
+		if q.SourceName != filename || q.Cmd == metadata.CmdCopyFrom {
+			continue
+		}
+		var keys []string
+		if q.Option.Cache > 0 {
+			keys = append(keys, q.CacheKey())
+			if q.Arg.EmitStruct() {
+				keys = append(keys, q.Arg.CacheKeySprintf())
+			}
+		}
+		for _, inv := range q.Invalidates {
+			keys = append(keys, inv.CacheKey)
+		}
+		for _, key := range keys {
+			slice = slice || strings.Contains(key, "keySlice(")
+			json = json || strings.Contains(key, "keyJSON(")
+		}
+	}
+	return slice, slice || json
+}
+
 func wrapPtrStr(v string) string {
 	return fmt.Sprintf("ptrStr(%s)", v)
 }
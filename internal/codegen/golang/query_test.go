@@ -0,0 +1,46 @@
+package golang
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCacheUniqueLabel(t *testing.T) {
+	book := func(fields ...Field) Query {
+		q := Query{
+			Pkg:        "books",
+			MethodName: "GetBook",
+			Ret:        QueryValue{Name: "i", Struct: &Struct{Name: "Book", Fields: fields}},
+		}
+		q.shape = resultShape(nil, nil, q.Ret, nil)
+		return q
+	}
+	id := Field{Name: "ID", Type: "int64"}
+	title := Field{Name: "Title", Type: "string"}
+
+	label := book(id, title).CacheUniqueLabel()
+	if !regexp.MustCompile(`^books:GetBook:[0-9a-f]{8}:$`).MatchString(label) {
+		t.Fatalf("unexpected label %q", label)
+	}
+	if got := book(id, title).CacheUniqueLabel(); got != label {
+		t.Errorf("label is not stable: %q and %q", label, got)
+	}
+	for name, q := range map[string]Query{
+		"added field":   book(id, title, Field{Name: "Year", Type: "*int32"}),
+		"changed type":  book(id, Field{Name: "Title", Type: "*string"}),
+		"renamed field": book(id, Field{Name: "Name", Type: "string"}),
+		"embedded field": book(id, Field{Name: "Author", Type: "Author", EmbedFields: []Field{
+			{Name: "ID", Type: "int64"},
+		}}),
+	} {
+		if got := q.CacheUniqueLabel(); got == label {
+			t.Errorf("%s: label did not change: %q", name, got)
+		}
+	}
+
+	q := book(id, title)
+	q.Option.CacheVersion = 2
+	if got, want := q.CacheUniqueLabel(), "books:GetBook:v2:"; got != want {
+		t.Errorf("cache_version: got %q, want %q", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package golang
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
@@ -70,16 +71,37 @@ func buildEnums(req *plugin.CodeGenRequest) []Enum {
 	return enums
 }
 
+// embeddedTables returns the tables that queries embed with sqlc.embed().
+func embeddedTables(req *plugin.CodeGenRequest) map[string]bool {
+	tables := map[string]bool{}
+	for _, query := range req.Queries {
+		for _, c := range query.Columns {
+			if c.EmbedTable == nil {
+				continue
+			}
+			schema := c.EmbedTable.Schema
+			if schema == "" {
+				schema = req.Catalog.DefaultSchema
+			}
+			tables[schema+"."+c.EmbedTable.Name] = true
+		}
+	}
+	return tables
+}
+
 func buildStructs(req *plugin.CodeGenRequest) []Struct {
-	var structs []Struct
+	var structs, embeds []Struct
+	embedded := embeddedTables(req)
 	for _, schema := range req.Catalog.Schemas {
 		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
 			continue
 		}
 		for _, table := range schema.Tables {
 			// only the last table schema, which is the first table creation SQL in sqlc.yaml file
-			// in the `schema: []` array, will be generated.
-			if !table.GenerateModel {
+			// in the `schema: []` array, will be generated, along with the referenced tables
+			// that queries embed, so that sqlc.embed() has a struct to scan into.
+			isEmbed := !table.GenerateModel
+			if isEmbed && !embedded[schema.Name+"."+table.Rel.Name] {
 				continue
 			}
 			var tableName string
@@ -113,19 +135,27 @@ func buildStructs(req *plugin.CodeGenRequest) []Struct {
 					DBName:  column.Name,
 					Type:    goType(req, column),
 					Tags:    tags,
-					Comment: column.Comment,
+					Comment: stripJSONGoType(column.Comment),
+					Column:  column,
 				})
 			}
-			structs = append(structs, s)
+			if isEmbed {
+				embeds = append(embeds, s)
+			} else {
+				structs = append(structs, s)
+			}
 		}
 	}
-	return structs
+	// The struct of the table itself comes first, see buildDumpLoader.
+	return append(structs, embeds...)
 }
 
 type goColumn struct {
 	id int
 	*plugin.Column
 	embed *goEmbed
+	// typ overrides the Go type of the column.
+	typ string
 }
 
 type goEmbed struct {
@@ -200,6 +230,10 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 	for _, query := range req.Queries {
 		queryNames[query.Name] = true
 	}
+	composites := map[string]CompositeType{}
+	for _, ct := range buildCompositeTypes(req) {
+		composites[ct.Name] = ct
+	}
 	qs := make([]Query, 0, len(req.Queries))
 	for _, query := range req.Queries {
 		if query.Name == "" {
@@ -244,6 +278,7 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 				Typ:       goType(req, p.Column),
 				SQLDriver: sqlpkg,
 				Column:    p.Column,
+				Composite: isCompositeColumn(req, p.Column),
 			}
 		} else if len(query.Params) >= 1 {
 			var cols []goColumn
@@ -270,8 +305,22 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 			}
 		}
 
-		if len(query.Columns) == 1 && query.Columns[0].EmbedTable == nil {
-			c := query.Columns[0]
+		var err error
+		gq.Option, err = parseOption(query.Options, queryNames)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse options for %s because %w", query.Name, err)
+		}
+		columns := hintedColumns(query.Columns, gq.Option.Columns)
+		columnType := func(c *plugin.Column) string {
+			if hint, ok := gq.Option.Columns[c.Name]; ok && hint.GoType != "" {
+				typ, _ := parseGoTypeSpec(hint.GoType)
+				return typ
+			}
+			return goType(req, c)
+		}
+
+		if len(columns) == 1 && columns[0].EmbedTable == nil {
+			c := columns[0]
 			name := columnName(c, 0)
 			if c.IsFuncCall {
 				name = strings.Replace(name, "$", "_", -1)
@@ -279,7 +328,7 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 			gq.Ret = QueryValue{
 				Name:      name,
 				DBName:    name,
-				Typ:       goType(req, c),
+				Typ:       columnType(c),
 				SQLDriver: sqlpkg,
 			}
 		} else if putOutColumns(query) {
@@ -287,14 +336,14 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 			var emit bool
 
 			for _, s := range structs {
-				if len(s.Fields) != len(query.Columns) {
+				if len(s.Fields) != len(columns) {
 					continue
 				}
 				same := true
 				for i, f := range s.Fields {
-					c := query.Columns[i]
+					c := columns[i]
 					sameName := f.Name == StructName(columnName(c, i), req.Settings)
-					sameType := f.Type == goType(req, c)
+					sameType := f.Type == columnType(c)
 					sameTable := sdk.SameTableName(c.Table, s.Table, req.Catalog.DefaultSchema)
 					if !sameName || !sameType || !sameTable {
 						same = false
@@ -307,16 +356,20 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 			}
 
 			if gs == nil {
-				var columns []goColumn
-				for i, c := range query.Columns {
-					columns = append(columns, goColumn{
+				var gcols []goColumn
+				for i, c := range columns {
+					gc := goColumn{
 						id:     i,
 						Column: c,
 						embed:  newGoEmbed(c.EmbedTable, structs, req.Catalog.DefaultSchema),
-					})
+					}
+					if hint, ok := gq.Option.Columns[c.Name]; ok && hint.GoType != "" {
+						gc.typ = columnType(c)
+					}
+					gcols = append(gcols, gc)
 				}
 				var err error
-				gs, err = columnsToStruct(req, gq.MethodName+"Row", columns, true)
+				gs, err = columnsToStruct(req, gq.MethodName+"Row", gcols, true)
 				if err != nil {
 					return nil, err
 				}
@@ -330,11 +383,11 @@ func buildQueries(req *plugin.CodeGenRequest, structs []Struct) ([]Query, error)
 				EmitPointer: req.Settings.Go.EmitResultStructPointers,
 			}
 		}
-		var err error
-		gq.Option, err = parseOption(query.Options, queryNames)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to parse options for %s because %w", query.Name, err)
+		var col *plugin.Column
+		if len(columns) == 1 {
+			col = columns[0]
 		}
+		gq.shape = resultShape(req, composites, gq.Ret, col)
 		qs = append(qs, gq)
 	}
 	sort.Slice(qs, func(i, j int) bool { return qs[i].MethodName < qs[j].MethodName })
@@ -368,7 +421,7 @@ func buildQueryInvalidates(queries []Query) error {
 					err := fmt.Errorf(
 						"Although invalidate pointer-typed argument is supported (%s tries to invalidate %s) , the generated type will be **T",
 						mutation.MethodName, query.MethodName)
-					fmt.Printf("WARNING: %s\n", err)
+					fmt.Fprintf(os.Stderr, "WARNING: %s\n", err)
 				}
 				argName := unamer.UniqueName(methodName)
 				// additional pointer will be added to invalidate query key,
@@ -453,12 +506,15 @@ func columnsToStruct(req *plugin.CodeGenRequest, name string, columns []goColumn
 		}
 		addExtraGoStructTags(tags, req, c.Column)
 		f := Field{
-			Name:   fieldName,
-			DBName: colName,
-			Tags:   tags,
-			Column: c.Column,
+			Name:      fieldName,
+			DBName:    colName,
+			Tags:      tags,
+			Column:    c.Column,
+			Composite: isCompositeColumn(req, c.Column),
 		}
-		if c.embed == nil {
+		if c.typ != "" {
+			f.Type = c.typ
+		} else if c.embed == nil {
 			f.Type = goType(req, c.Column)
 		} else {
 			f.Type = c.embed.modelType
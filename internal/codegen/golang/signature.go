@@ -0,0 +1,70 @@
+package golang
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// Signature describes the Go method that would be generated for a query. It
+// lets tooling such as the language server show inferred Go types without
+// rendering any templates.
+type Signature struct {
+	Name   string
+	Cmd    string
+	Params []Argument
+	// ParamFields are the fields of the params struct, if the query takes
+	// one.
+	ParamFields []Field
+	// Ret is the Go type returned per row, empty for exec-style queries.
+	Ret       string
+	RetFields []Field
+	// Cache is the cache duration of a WPgx query, zero if it is not cached.
+	Cache time.Duration
+	// CacheKey is the expression the cache key of a WPgx query is built
+	// from, with the arguments in their order in the key.
+	CacheKey string
+}
+
+// Signatures returns the Go signature of every named query in req.
+func Signatures(req *plugin.CodeGenRequest) ([]Signature, error) {
+	structs := buildStructs(req)
+	queries, err := buildQueries(req, structs)
+	if err != nil {
+		return nil, err
+	}
+	sigs := make([]Signature, 0, len(queries))
+	for _, q := range queries {
+		sig := Signature{
+			Name:   q.MethodName,
+			Cmd:    q.Cmd,
+			Params: q.Arg.Pairs(),
+			Cache:  q.Option.Cache,
+		}
+		if req.Settings.Go.SqlPackage == SQLPackageWPGX && q.Pkg != "" {
+			sig.CacheKey = q.cacheKeyExpr()
+		}
+		if q.Arg.EmitStruct() && q.Arg.IsStruct() {
+			sig.ParamFields = q.Arg.Struct.Fields
+		}
+		if q.hasRetType() {
+			sig.Ret = q.Ret.DefineType()
+			if q.Ret.IsStruct() {
+				sig.RetFields = q.Ret.Struct.Fields
+			}
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// cacheKeyExpr returns the cache key of q as CacheKey() builds it, with the
+// format and the arguments of a struct argument inlined.
+func (q Query) cacheKeyExpr() string {
+	if q.Arg.Struct == nil {
+		return q.CacheKey()
+	}
+	format := strings.Join(strings.Fields(q.Arg.CacheKeySprintf()), " ")
+	return `"` + q.CacheUniqueLabel() + `" + hashIfLong(fmt.Sprintf(` + strings.TrimSuffix(format, ",") + `))`
+}
@@ -0,0 +1,135 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+// Cache memoizes work shared by the compilers of a single run: the statements
+// parsed from each schema file, keyed by content hash, and the catalog built
+// from each chain of dependency schema files. It is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	stmts    map[string]*cacheEntry[[]ast.Statement]
+	catalogs map[string]*cacheEntry[*catalog.Catalog]
+}
+
+type cacheEntry[T any] struct {
+	once sync.Once
+	val  T
+	err  error
+}
+
+func NewCache() *Cache {
+	return &Cache{
+		stmts:    map[string]*cacheEntry[[]ast.Statement]{},
+		catalogs: map[string]*cacheEntry[*catalog.Catalog]{},
+	}
+}
+
+func lookup[T any](mu *sync.Mutex, m map[string]*cacheEntry[T], key string) *cacheEntry[T] {
+	mu.Lock()
+	defer mu.Unlock()
+	e, ok := m[key]
+	if !ok {
+		e = &cacheEntry[T]{}
+		m[key] = e
+	}
+	return e
+}
+
+func hashString(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// UseCache makes the compiler share parsed schema files and catalog states
+// through cache.
+func (c *Compiler) UseCache(cache *Cache) {
+	c.cache = cache
+}
+
+// parseSchema parses the contents of a schema file. The returned statements
+// may be shared with other compilers and must not be modified.
+func (c *Compiler) parseSchema(contents string) ([]ast.Statement, error) {
+	if c.cache == nil {
+		return c.parser.Parse(strings.NewReader(contents))
+	}
+	e := lookup(&c.cache.mu, c.cache.stmts, hashString(string(c.conf.Engine), contents))
+	e.once.Do(func() {
+		e.val, e.err = c.parser.Parse(strings.NewReader(contents))
+	})
+	return e.val, e.err
+}
+
+// catalogKey identifies the catalog state produced by applying the schema
+// files in order. View definitions are compiled as queries, so settings that
+// affect query compilation are part of the key.
+func (c *Compiler) catalogKey(prev, filename, contents string) string {
+	strictOrderBy := "default"
+	if c.conf.StrictOrderBy != nil {
+		strictOrderBy = fmt.Sprint(*c.conf.StrictOrderBy)
+	}
+	return hashString(prev, string(c.conf.Engine), strictOrderBy,
+		fmt.Sprint(c.conf.StrictFunctionChecks), filename, contents)
+}
+
+// cachedCatalog returns a copy of the catalog built from the given
+// dependency files, or false if the files could not be applied without
+// errors. Errors are left for the uncached path to report with positions.
+func (c *Compiler) cachedCatalog(files []string) (*catalog.Catalog, bool) {
+	base := c.catalog
+	key := ""
+	for _, filename := range files {
+		blob, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, false
+		}
+		contents := migrations.RemoveRollbackStatements(string(blob))
+		key = c.catalogKey(key, filename, contents)
+		e := lookup(&c.cache.mu, c.cache.catalogs, key)
+		prev := base
+		e.once.Do(func() {
+			e.val, e.err = c.applySchema(prev.Clone(), contents)
+		})
+		if e.err != nil {
+			return nil, false
+		}
+		base = e.val
+	}
+	return base.Clone(), true
+}
+
+// applySchema applies a dependency schema file to cat, which must not be
+// shared.
+func (c *Compiler) applySchema(cat *catalog.Catalog, contents string) (*catalog.Catalog, error) {
+	stmts, err := c.parseSchema(contents)
+	if err != nil {
+		return nil, err
+	}
+	// Views are resolved against the catalog being built.
+	gen := &Compiler{conf: c.conf, combo: c.combo, parser: c.parser, catalog: cat}
+	tableDefined := false
+	for _, stmt := range stmts {
+		if err := cat.Update(stmt, gen, false); err != nil {
+			return nil, err
+		}
+		definingTable := cat.IsCreatingNewTableLayout(stmt)
+		if tableDefined && definingTable {
+			return nil, fmt.Errorf("only one table creation is allowed per schema.sql file")
+		}
+		tableDefined = tableDefined || definingTable
+	}
+	return cat, nil
+}
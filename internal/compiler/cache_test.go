@@ -0,0 +1,158 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+const (
+	cacheAuthorsSchema = `CREATE TYPE author_state AS ENUM ('active', 'retired');
+
+CREATE TABLE authors (
+   id    BIGINT PRIMARY KEY,
+   name  TEXT NOT NULL,
+   state author_state NOT NULL
+);
+
+CREATE VIEW active_authors AS SELECT id, name FROM authors WHERE state = 'active';
+`
+	cacheBooksSchema = `CREATE TABLE books (
+   id        BIGINT PRIMARY KEY,
+   author_id BIGINT NOT NULL REFERENCES authors (id),
+   title     TEXT NOT NULL
+);
+
+CREATE VIEW book_authors AS SELECT b.id, b.title, a.name FROM books b JOIN authors a ON a.id = b.author_id;
+`
+	cacheReviewsSchema = `CREATE TABLE reviews (
+   id      BIGINT PRIMARY KEY,
+   book_id BIGINT NOT NULL,
+   stars   INT
+);
+
+ALTER TABLE books ADD COLUMN rating INT;
+ALTER TYPE author_state ADD VALUE 'banned';
+`
+)
+
+func writeCacheSchemas(t *testing.T) (authors, books, reviews string) {
+	t.Helper()
+	dir := t.TempDir()
+	paths := []string{"authors.sql", "books.sql", "reviews.sql"}
+	for i, contents := range []string{cacheAuthorsSchema, cacheBooksSchema, cacheReviewsSchema} {
+		paths[i] = filepath.Join(dir, paths[i])
+		if err := os.WriteFile(paths[i], []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths[0], paths[1], paths[2]
+}
+
+func userSchema(t *testing.T, c *catalog.Catalog) *catalog.Schema {
+	t.Helper()
+	for _, s := range c.Schemas {
+		if s.Name == c.DefaultSchema {
+			return s
+		}
+	}
+	t.Fatalf("no schema %s", c.DefaultSchema)
+	return nil
+}
+
+func compileSchema(t *testing.T, cache *Cache, files ...string) *catalog.Catalog {
+	t.Helper()
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if cache != nil {
+		c.UseCache(cache)
+	}
+	if err := c.ParseCatalog(files); err != nil {
+		t.Fatal(err)
+	}
+	return c.Catalog()
+}
+
+func TestCloneIsolation(t *testing.T) {
+	authors, books, _ := writeCacheSchemas(t)
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if err := c.ParseCatalog([]string{books, authors}); err != nil {
+		t.Fatal(err)
+	}
+	orig := c.Catalog()
+	want := compileSchema(t, nil, books, authors)
+
+	clone := orig.Clone()
+	stmts, err := c.parser.Parse(strings.NewReader(`
+ALTER TABLE books ADD COLUMN year INT;
+ALTER TABLE books RENAME COLUMN title TO name;
+ALTER TABLE books ALTER COLUMN author_id DROP NOT NULL;
+ALTER TABLE authors DROP COLUMN state;
+ALTER TYPE author_state ADD VALUE 'banned';
+ALTER TYPE author_state RENAME TO writer_state;
+ALTER TABLE books RENAME TO novels;
+CREATE TABLE reviews (id BIGINT PRIMARY KEY);
+DROP VIEW active_authors;
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range stmts {
+		if err := clone.Update(stmt, c, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff(userSchema(t, want), userSchema(t, orig)); diff != "" {
+		t.Errorf("updating the clone changed the original (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(userSchema(t, want), userSchema(t, clone)); diff == "" {
+		t.Errorf("the clone was not updated")
+	}
+}
+
+func TestCachedCatalog(t *testing.T) {
+	authors, books, reviews := writeCacheSchemas(t)
+	// The schema arrays of three packages sharing dependency files, in the
+	// order the packages are compiled.
+	packages := [][]string{
+		{books, authors},
+		{authors},
+		{reviews, books, authors},
+		{books, authors},
+		{authors},
+	}
+	cache := NewCache()
+	for _, files := range packages {
+		want := userSchema(t, compileSchema(t, nil, files...))
+		got := userSchema(t, compileSchema(t, cache, files...))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%v: cached catalog differs (-want +got):\n%s", files, diff)
+		}
+	}
+
+	// The statements handed out by the cache are still the ones parsed from
+	// the files.
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	c.UseCache(cache)
+	for _, contents := range []string{cacheAuthorsSchema, cacheBooksSchema, cacheReviewsSchema} {
+		want, err := c.parser.Parse(strings.NewReader(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.parseSchema(contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b *ast.RawStmt) bool {
+			return cmp.Equal(*a, *b)
+		})); diff != "" {
+			t.Errorf("cached statements were modified (-want +got):\n%s", diff)
+		}
+	}
+}
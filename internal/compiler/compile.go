@@ -6,13 +6,16 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/sqlc-dev/sqlc/internal/config"
 	"github.com/sqlc-dev/sqlc/internal/metadata"
 	"github.com/sqlc-dev/sqlc/internal/migrations"
 	"github.com/sqlc-dev/sqlc/internal/multierr"
 	"github.com/sqlc-dev/sqlc/internal/opts"
 	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/rewrite"
 	"github.com/sqlc-dev/sqlc/internal/sql/sqlerr"
 	"github.com/sqlc-dev/sqlc/internal/sql/sqlpath"
 )
@@ -33,14 +36,27 @@ func (c *Compiler) parseCatalog(schemas []string) error {
 	merr := multierr.New()
 	// XXX(yumin): reverse the order of files to process dependencies first.
 	orderReversedFiles := reversed(files)
+	start := 0
+	if c.cache != nil && len(orderReversedFiles) > 1 {
+		// Dependencies are shared by many packages; only the package's own
+		// schema file needs to be applied to a cached catalog.
+		deps := orderReversedFiles[:len(orderReversedFiles)-1]
+		if cat, ok := c.cachedCatalog(deps); ok {
+			c.catalog = cat
+			start = len(deps)
+		}
+	}
 	for i, filename := range orderReversedFiles {
+		if i < start {
+			continue
+		}
 		blob, err := os.ReadFile(filename)
 		if err != nil {
 			merr.Add(filename, "", 0, err)
 			continue
 		}
 		contents := migrations.RemoveRollbackStatements(string(blob))
-		stmts, err := c.parser.Parse(strings.NewReader(contents))
+		stmts, err := c.parseSchema(contents)
 		if err != nil {
 			merr.Add(filename, contents, 0, err)
 			continue
@@ -63,7 +79,16 @@ func (c *Compiler) parseCatalog(schemas []string) error {
 		}
 		// XXX(yumin): only the first schema file in the original order is added.
 		if i == len(orderReversedFiles)-1 {
-			c.catalog.AddRawSQL(contents)
+			raw := contents
+			if c.combo.Go.EmitIdempotentSchema && c.conf.Engine == config.EnginePostgreSQL {
+				var pos int
+				raw, pos, err = idempotentSchema(contents, stmts)
+				if err != nil {
+					merr.Add(filename, contents, pos, err)
+					continue
+				}
+			}
+			c.catalog.AddRawSQL(raw)
 		}
 	}
 	if len(merr.Errs()) > 0 {
@@ -86,37 +111,14 @@ func (c *Compiler) parseQueries(o opts.Parser) (*Result, error) {
 			merr.Add(filename, "", 0, err)
 			continue
 		}
-		src := string(blob)
-		stmts, err := c.parser.Parse(strings.NewReader(src))
+		q = append(q, c.parseQueryFile(filename, string(blob), o, set, merr)...)
+	}
+	if c.conf.CRUD.Enabled {
+		src, err := c.crudSource()
 		if err != nil {
-			merr.Add(filename, src, 0, err)
-			continue
-		}
-		for _, stmt := range stmts {
-			query, err := c.parseQuery(stmt.Raw, src, o)
-			if err == ErrUnsupportedStatementType {
-				continue
-			}
-			if err != nil {
-				var e *sqlerr.Error
-				loc := stmt.Raw.Pos()
-				if errors.As(err, &e) && e.Location != 0 {
-					loc = e.Location
-				}
-				merr.Add(filename, src, loc, err)
-				continue
-			}
-			if query.Name != "" {
-				if _, exists := set[query.Name]; exists {
-					merr.Add(filename, src, stmt.Raw.Pos(), fmt.Errorf("duplicate query name: %s", query.Name))
-					continue
-				}
-				set[query.Name] = struct{}{}
-			}
-			query.Filename = filepath.Base(filename)
-			if query != nil {
-				q = append(q, query)
-			}
+			merr.Add(crudFilename, "", 0, err)
+		} else {
+			q = append(q, c.parseQueryFile(crudFilename, src, o, set, merr)...)
 		}
 	}
 	if len(merr.Errs()) > 0 {
@@ -131,6 +133,63 @@ func (c *Compiler) parseQueries(o opts.Parser) (*Result, error) {
 	}, nil
 }
 
+// parseQueryFile parses every statement of a single query file. Errors are
+// recorded in merr; set tracks query names to detect duplicates across files.
+func (c *Compiler) parseQueryFile(filename, src string, o opts.Parser, set map[string]struct{}, merr *multierr.Error) []*Query {
+	var q []*Query
+	// The statements are parsed from src with its `=@` tokens split, and pos
+	// maps their locations back to src.
+	parsed, splits := src, []int(nil)
+	if c.conf.Engine == config.EnginePostgreSQL {
+		parsed, splits = rewrite.SplitParamSigns(src)
+	}
+	pos := func(loc int) int {
+		return loc - sort.SearchInts(splits, loc)
+	}
+	stmts, err := c.parser.Parse(strings.NewReader(parsed))
+	if err != nil {
+		var e *sqlerr.Error
+		if errors.As(err, &e) && e.Location != 0 {
+			e.Location = pos(e.Location)
+		}
+		merr.Add(filename, src, 0, err)
+		return nil
+	}
+	for _, stmt := range stmts {
+		query, err := c.parseQuery(stmt.Raw, parsed, splits, o)
+		if err == ErrUnsupportedStatementType {
+			continue
+		}
+		if err != nil {
+			var e *sqlerr.Error
+			loc := pos(stmt.Raw.Pos())
+			if errors.As(err, &e) && e.Location != 0 {
+				e.Location = pos(e.Location)
+				loc = e.Location
+			}
+			merr.Add(filename, src, loc, err)
+			continue
+		}
+		if query.Name != "" {
+			if _, exists := set[query.Name]; exists {
+				merr.Add(filename, src, pos(stmt.Raw.Pos()), fmt.Errorf("duplicate query name: %s", query.Name))
+				continue
+			}
+			set[query.Name] = struct{}{}
+		}
+		if len(splits) > 0 {
+			end := pos(query.RawStmt.StmtLocation + query.RawStmt.StmtLen)
+			query.RawStmt.StmtLocation = pos(query.RawStmt.StmtLocation)
+			query.RawStmt.StmtLen = end - query.RawStmt.StmtLocation
+		}
+		query.Filename = filepath.Base(filename)
+		if query != nil {
+			q = append(q, query)
+		}
+	}
+	return q
+}
+
 func reversed[V any](arr []V) []V {
 	rv := make([]V, len(arr))
 	for i := range arr {
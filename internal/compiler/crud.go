@@ -0,0 +1,155 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+// crudFilename is the file the queries of crud are compiled from, so their
+// code is generated into crud.sql.go.
+const crudFilename = "crud.sql"
+
+// crudQueries are the queries crud generates, in the order they are written.
+var crudQueries = []string{"GetByID", "ListAfterID", "Insert", "UpsertByPK", "DeleteByID", "BulkInsert"}
+
+// crudSource returns the queries that crud generates for the table of the
+// package, i.e. the table of its own schema file, as the source of a query
+// file.
+func (c *Compiler) crudSource() (string, error) {
+	conf := c.conf.CRUD
+	known := map[string]bool{}
+	for _, name := range crudQueries {
+		known[name] = true
+	}
+	for _, name := range append(append([]string{}, conf.Include...), conf.Exclude...) {
+		if !known[name] {
+			return "", fmt.Errorf("crud: unknown query %q, want one of %s", name, strings.Join(crudQueries, ", "))
+		}
+	}
+	if conf.Cache != "" {
+		if _, err := time.ParseDuration(conf.Cache); err != nil {
+			return "", fmt.Errorf("crud: invalid cache duration %q", conf.Cache)
+		}
+	}
+
+	var tbl *catalog.Table
+	for _, schema := range c.catalog.Schemas {
+		for _, t := range schema.Tables {
+			if t.GenerateModel {
+				tbl = t
+			}
+		}
+	}
+	if tbl == nil {
+		return "", fmt.Errorf("crud: the first schema file does not create a table")
+	}
+	if len(tbl.PrimaryKey) == 0 {
+		return "", fmt.Errorf("crud: table %s has no primary key", tbl.Rel.Name)
+	}
+
+	enabled := map[string]bool{}
+	for _, name := range crudQueries {
+		enabled[name] = len(conf.Include) == 0
+	}
+	for _, name := range conf.Include {
+		enabled[name] = true
+	}
+	for _, name := range conf.Exclude {
+		enabled[name] = false
+	}
+
+	name := c.quoteIdent(tbl.Rel.Name)
+	if tbl.Rel.Schema != "" && tbl.Rel.Schema != c.catalog.DefaultSchema {
+		name = c.quoteIdent(tbl.Rel.Schema) + "." + name
+	}
+	keys := map[string]bool{}
+	var where []string
+	for i, key := range tbl.PrimaryKey {
+		keys[key] = true
+		where = append(where, fmt.Sprintf("%s = $%d", c.quoteIdent(key), i+1))
+	}
+	// Insert leaves the columns with defaults to the database, while
+	// UpsertByPK writes every column it can, the primary key included.
+	var insert, upsert, update []string
+	for _, col := range tbl.Columns {
+		if col.IsGenerated {
+			if keys[col.Name] && enabled["UpsertByPK"] {
+				return "", fmt.Errorf("crud: UpsertByPK can't write the generated primary key column %s, exclude it", col.Name)
+			}
+			continue
+		}
+		ident := c.quoteIdent(col.Name)
+		upsert = append(upsert, ident)
+		if !keys[col.Name] {
+			update = append(update, fmt.Sprintf("%s = EXCLUDED.%s", ident, ident))
+		}
+		if !col.HasDefault {
+			insert = append(insert, ident)
+		}
+	}
+	if len(update) == 0 {
+		// DO NOTHING would return no row on conflicts.
+		for _, key := range tbl.PrimaryKey {
+			ident := c.quoteIdent(key)
+			update = append(update, fmt.Sprintf("%s = EXCLUDED.%s", ident, ident))
+		}
+	}
+	if len(tbl.PrimaryKey) > 1 && len(conf.Include) > 0 && enabled["ListAfterID"] {
+		return "", fmt.Errorf("crud: ListAfterID needs a single column primary key")
+	}
+	enabled["ListAfterID"] = enabled["ListAfterID"] && len(tbl.PrimaryKey) == 1
+	enabled["BulkInsert"] = enabled["BulkInsert"] && len(insert) > 0
+
+	var b strings.Builder
+	write := func(query, cmd, timeout string, options []string, sql string) {
+		if !enabled[query] {
+			return
+		}
+		fmt.Fprintf(&b, "-- name: %s %s\n-- -- timeout : %s\n", query, cmd, timeout)
+		for _, option := range options {
+			fmt.Fprintf(&b, "-- -- %s\n", option)
+		}
+		b.WriteString(sql + ";\n\n")
+	}
+	var cache, invalidate []string
+	if conf.Cache != "" && enabled["GetByID"] {
+		cache = []string{"cache : " + conf.Cache}
+		invalidate = []string{"invalidate : GetByID"}
+	}
+	values := func(n int) string {
+		params := make([]string, n)
+		for i := range params {
+			params[i] = fmt.Sprintf("$%d", i+1)
+		}
+		return strings.Join(params, ", ")
+	}
+	insertSQL := fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", name)
+	if len(insert) > 0 {
+		insertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", name, strings.Join(insert, ", "), values(len(insert)))
+	}
+	key := c.quoteIdent(tbl.PrimaryKey[0])
+
+	write("GetByID", ":one", "500ms", cache,
+		fmt.Sprintf("SELECT * FROM %s WHERE %s", name, strings.Join(where, " AND ")))
+	write("ListAfterID", ":many", "1s", nil,
+		fmt.Sprintf("SELECT * FROM %s WHERE %s > @after ORDER BY %s LIMIT @first", name, key, key))
+	write("Insert", ":one", "500ms", nil, insertSQL+" RETURNING *")
+	write("UpsertByPK", ":one", "500ms", invalidate,
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)\nON CONFLICT (%s) DO UPDATE SET %s\nRETURNING *",
+			name, strings.Join(upsert, ", "), values(len(upsert)), c.quoteIdents(tbl.PrimaryKey), strings.Join(update, ", ")))
+	write("DeleteByID", ":exec", "500ms", invalidate,
+		fmt.Sprintf("DELETE FROM %s WHERE %s", name, strings.Join(where, " AND ")))
+	write("BulkInsert", ":copyfrom", "10s", nil, insertSQL)
+	return b.String(), nil
+}
+
+func (c *Compiler) quoteIdents(idents []string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = c.quoteIdent(ident)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,109 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+)
+
+const crudSchema = `CREATE TABLE books (
+   id         BIGINT GENERATED BY DEFAULT AS IDENTITY,
+   title      TEXT        NOT NULL,
+   "Year"     INTEGER,
+   slug       TEXT        GENERATED ALWAYS AS (lower(title)) STORED,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+   PRIMARY KEY (id)
+);
+`
+
+const crudSource = `-- name: GetByID :one
+-- -- timeout : 500ms
+-- -- cache : 10m
+SELECT * FROM books WHERE id = $1;
+
+-- name: ListAfterID :many
+-- -- timeout : 1s
+SELECT * FROM books WHERE id > @after ORDER BY id LIMIT @first;
+
+-- name: Insert :one
+-- -- timeout : 500ms
+INSERT INTO books (title, "Year") VALUES ($1, $2) RETURNING *;
+
+-- name: UpsertByPK :one
+-- -- timeout : 500ms
+-- -- invalidate : GetByID
+INSERT INTO books (id, title, "Year", created_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, "Year" = EXCLUDED."Year", created_at = EXCLUDED.created_at
+RETURNING *;
+
+-- name: DeleteByID :exec
+-- -- timeout : 500ms
+-- -- invalidate : GetByID
+DELETE FROM books WHERE id = $1;
+
+-- name: BulkInsert :copyfrom
+-- -- timeout : 10s
+INSERT INTO books (title, "Year") VALUES ($1, $2);
+
+`
+
+func TestCRUD(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schema, []byte(crudSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	queries := filepath.Join(dir, "query.sql")
+	if err := os.WriteFile(queries, []byte("-- name: CountBooks :one\n-- -- timeout : 500ms\nSELECT count(*) FROM books;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	compile := func(crud config.CRUD) (*Compiler, error) {
+		conf := config.SQL{Engine: config.EnginePostgreSQL, Queries: []string{queries}, CRUD: crud}
+		c := NewCompiler(conf, config.CombinedSettings{})
+		if err := c.ParseCatalog([]string{schema}); err != nil {
+			t.Fatal(err)
+		}
+		return c, c.ParseQueries(conf.Queries, opts.Parser{})
+	}
+
+	c, err := compile(config.CRUD{Enabled: true, Cache: "10m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := c.crudSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(crudSource, src); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+	var names []string
+	for _, q := range c.Result().Queries {
+		names = append(names, q.Name+" "+q.Filename)
+	}
+	want := "CountBooks query.sql,GetByID crud.sql,ListAfterID crud.sql,Insert crud.sql,UpsertByPK crud.sql,DeleteByID crud.sql,BulkInsert crud.sql"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("queries: got %s, want %s", got, want)
+	}
+
+	c, err = compile(config.CRUD{Enabled: true, Include: []string{"GetByID", "Insert"}, Exclude: []string{"Insert"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.Result().Queries); n != 2 {
+		t.Errorf("include and exclude: got %d queries, want 2", n)
+	}
+
+	_, err = compile(config.CRUD{Enabled: true, Exclude: []string{"Update"}})
+	merr, ok := err.(*multierr.Error)
+	if !ok || !strings.Contains(merr.Errs()[0].Err.Error(), `unknown query "Update"`) {
+		t.Errorf("unknown query: got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/sqlc-dev/sqlc/internal/engine/dolphin"
 	"github.com/sqlc-dev/sqlc/internal/engine/postgresql"
 	"github.com/sqlc-dev/sqlc/internal/engine/sqlite"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
 	"github.com/sqlc-dev/sqlc/internal/opts"
 	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
 )
@@ -17,6 +18,7 @@ type Compiler struct {
 	catalog *catalog.Catalog
 	parser  Parser
 	result  *Result
+	cache   *Cache
 }
 
 func NewCompiler(conf config.SQL, combo config.CombinedSettings) *Compiler {
@@ -41,6 +43,10 @@ func (c *Compiler) Catalog() *catalog.Catalog {
 	return c.catalog
 }
 
+func (c *Compiler) Parser() Parser {
+	return c.parser
+}
+
 func (c *Compiler) ParseCatalog(schema []string) error {
 	return c.parseCatalog(schema)
 }
@@ -54,6 +60,18 @@ func (c *Compiler) ParseQueries(queries []string, o opts.Parser) error {
 	return nil
 }
 
+// ParseQuerySource compiles the queries in src as if they were read from
+// filename, without touching the file system. The catalog must already be
+// parsed. It is used by tools that work on unsaved buffers.
+func (c *Compiler) ParseQuerySource(filename, src string, o opts.Parser) ([]*Query, error) {
+	merr := multierr.New()
+	q := c.parseQueryFile(filename, src, o, map[string]struct{}{}, merr)
+	if len(merr.Errs()) > 0 {
+		return q, merr
+	}
+	return q, nil
+}
+
 func (c *Compiler) Result() *Result {
 	return c.result
 }
@@ -139,12 +139,11 @@ func (c *Compiler) expandStmt(qc *QueryCatalog, raw *ast.RawStmt, node ast.Node)
 			old = append(old, c.quoteIdent(p))
 		}
 
-		var oldString string
 		var oldFunc func(string) int
 
-		// use the sqlc.embed string instead
+		// replace the whole sqlc.embed call instead
 		if embed, ok := qc.embeds.Find(ref); ok {
-			oldString = embed.Orig()
+			oldFunc = embed.Len
 		} else {
 			oldFunc = func(s string) int {
 				length := 0
@@ -166,7 +165,6 @@ func (c *Compiler) expandStmt(qc *QueryCatalog, raw *ast.RawStmt, node ast.Node)
 
 		edits = append(edits, source.Edit{
 			Location: res.Location - raw.StmtLocation,
-			Old:      oldString,
 			OldFunc:  oldFunc,
 			New:      strings.Join(cols, ", "),
 		})
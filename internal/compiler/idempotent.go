@@ -0,0 +1,149 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+)
+
+// leadingComments matches the whitespace and comments before the first
+// keyword of a statement.
+var leadingComments = regexp.MustCompile(`^(?s)(?:\s+|--[^\n]*(?:\n|$)|/\*.*?\*/)*`)
+
+// The keywords after which IF NOT EXISTS is written.
+var (
+	createTableKeywords     = regexp.MustCompile(`^(?is)CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE`)
+	createTableAsKeywords   = regexp.MustCompile(`^(?is)CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?(?:MATERIALIZED\s+VIEW|TABLE)`)
+	createIndexKeywords     = regexp.MustCompile(`^(?is)CREATE\s+(?:UNIQUE\s+)?INDEX(?:\s+CONCURRENTLY)?`)
+	createSequenceKeywords  = regexp.MustCompile(`^(?is)CREATE\s+(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?SEQUENCE`)
+	createSchemaKeywords    = regexp.MustCompile(`^(?is)CREATE\s+SCHEMA`)
+	createExtensionKeywords = regexp.MustCompile(`^(?is)CREATE\s+EXTENSION`)
+	addValueKeywords        = regexp.MustCompile(`(?is)\sADD\s+VALUE`)
+	createKeyword           = regexp.MustCompile(`^(?i)CREATE`)
+)
+
+// idempotentDollarTag quotes the statements wrapped in DO blocks.
+const idempotentDollarTag = "$sqlc$"
+
+// idempotentSchema rewrites the statements of a PostgreSQL schema file so
+// that the file can be run again on a database it was already run on:
+// IF NOT EXISTS is added to tables, indexes, sequences, schemas, extensions
+// and materialized views, functions and views are created with OR REPLACE,
+// and types, domains, triggers and ALTER TABLE ... ADD statements, which
+// can't be written so, are run in DO blocks that ignore the duplicate object
+// errors. Other statements are kept as they are. On error, it returns the
+// location of the statement that can't be rewritten.
+func idempotentSchema(contents string, stmts []ast.Statement) (string, int, error) {
+	var b strings.Builder
+	end := 0
+	for _, stmt := range stmts {
+		raw := stmt.Raw
+		start := raw.StmtLocation
+		stop := raw.StmtLocation + raw.StmtLen
+		if raw.StmtLen == 0 {
+			stop = len(contents)
+		}
+		if start < end || stop > len(contents) {
+			continue
+		}
+		text := contents[start:stop]
+		lead := leadingComments.FindString(text)
+		sql, err := idempotentStmt(raw.Stmt, text[len(lead):])
+		if err != nil {
+			return "", start + len(lead), err
+		}
+		b.WriteString(contents[end:start])
+		b.WriteString(lead)
+		b.WriteString(sql)
+		end = stop
+	}
+	b.WriteString(contents[end:])
+	return b.String(), 0, nil
+}
+
+func idempotentStmt(node ast.Node, sql string) (string, error) {
+	switch n := node.(type) {
+	case *ast.CreateTableStmt:
+		if !n.IfNotExists {
+			return ifNotExists(createTableKeywords, sql), nil
+		}
+	case *ast.CreateTableAsStmt:
+		if !n.IfNotExists {
+			return ifNotExists(createTableAsKeywords, sql), nil
+		}
+	case *ast.IndexStmt:
+		if n.Idxname == nil || *n.Idxname == "" {
+			return "", fmt.Errorf("emit_idempotent_schema: name the index, IF NOT EXISTS needs the name of an index")
+		}
+		if !n.IfNotExists {
+			return ifNotExists(createIndexKeywords, sql), nil
+		}
+	case *ast.CreateSeqStmt:
+		if !n.IfNotExists {
+			return ifNotExists(createSequenceKeywords, sql), nil
+		}
+	case *ast.CreateSchemaStmt:
+		if !n.IfNotExists {
+			return ifNotExists(createSchemaKeywords, sql), nil
+		}
+	case *ast.CreateExtensionStmt:
+		if !n.IfNotExists {
+			return ifNotExists(createExtensionKeywords, sql), nil
+		}
+	case *ast.AlterTypeAddValueStmt:
+		if !n.SkipIfNewValExists {
+			return ifNotExists(addValueKeywords, sql), nil
+		}
+	case *ast.ViewStmt:
+		if !n.Replace {
+			return orReplace(sql), nil
+		}
+	case *ast.CreateFunctionStmt:
+		if !n.Replace {
+			return orReplace(sql), nil
+		}
+	case *ast.CreateEnumStmt, *ast.CompositeTypeStmt, *ast.CreateDomainStmt, *ast.CreateTrigStmt:
+		return ignoreDuplicates(sql, "duplicate_object")
+	case *ast.AlterTableStmt:
+		if n.Cmds == nil || len(n.Cmds.Items) == 0 {
+			break
+		}
+		for _, item := range n.Cmds.Items {
+			cmd, ok := item.(*ast.AlterTableCmd)
+			if !ok || (cmd.Subtype != ast.AT_AddColumn && cmd.Subtype != ast.AT_AddConstraint) {
+				return sql, nil
+			}
+		}
+		// A primary key added again fails with invalid_table_definition.
+		return ignoreDuplicates(sql, "duplicate_column", "duplicate_object", "duplicate_table", "invalid_table_definition")
+	}
+	return sql, nil
+}
+
+func ifNotExists(keywords *regexp.Regexp, sql string) string {
+	loc := keywords.FindStringIndex(sql)
+	if loc == nil {
+		return sql
+	}
+	return sql[:loc[1]] + " IF NOT EXISTS" + sql[loc[1]:]
+}
+
+func orReplace(sql string) string {
+	loc := createKeyword.FindStringIndex(sql)
+	if loc == nil {
+		return sql
+	}
+	return sql[:loc[1]] + " OR REPLACE" + sql[loc[1]:]
+}
+
+// ignoreDuplicates wraps the statement in a DO block that ignores the
+// errors. The semicolon that ends the statement is kept after the block.
+func ignoreDuplicates(sql string, errs ...string) (string, error) {
+	if strings.Contains(sql, idempotentDollarTag) {
+		return "", fmt.Errorf("emit_idempotent_schema: the statement can't contain %s", idempotentDollarTag)
+	}
+	return fmt.Sprintf("DO %s BEGIN\n%s;\nEXCEPTION WHEN %s THEN NULL;\nEND %s",
+		idempotentDollarTag, sql, strings.Join(errs, " OR "), idempotentDollarTag), nil
+}
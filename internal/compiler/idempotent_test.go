@@ -0,0 +1,65 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/engine/postgresql"
+)
+
+func TestIdempotentSchema(t *testing.T) {
+	schema := `-- Books.
+CREATE TYPE book_state AS ENUM ('draft', 'published');
+CREATE TABLE books (id BIGSERIAL PRIMARY KEY, title TEXT NOT NULL);
+CREATE TABLE IF NOT EXISTS authors (id BIGSERIAL PRIMARY KEY);
+create unique index concurrently books_title_idx on books (title);
+ALTER TYPE book_state ADD VALUE 'archived' AFTER 'published';
+CREATE FUNCTION book_count() RETURNS bigint AS $$ SELECT count(*) FROM books $$ LANGUAGE sql;
+/* views */
+CREATE VIEW drafts AS SELECT * FROM books;
+CREATE MATERIALIZED VIEW book_titles AS SELECT title FROM books;
+ALTER TABLE books ADD COLUMN year INT;
+ALTER TABLE books DROP COLUMN year;
+COMMENT ON TABLE books IS 'books'`
+	want := `-- Books.
+DO $sqlc$ BEGIN
+CREATE TYPE book_state AS ENUM ('draft', 'published');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $sqlc$;
+CREATE TABLE IF NOT EXISTS books (id BIGSERIAL PRIMARY KEY, title TEXT NOT NULL);
+CREATE TABLE IF NOT EXISTS authors (id BIGSERIAL PRIMARY KEY);
+create unique index concurrently IF NOT EXISTS books_title_idx on books (title);
+ALTER TYPE book_state ADD VALUE IF NOT EXISTS 'archived' AFTER 'published';
+CREATE OR REPLACE FUNCTION book_count() RETURNS bigint AS $$ SELECT count(*) FROM books $$ LANGUAGE sql;
+/* views */
+CREATE OR REPLACE VIEW drafts AS SELECT * FROM books;
+CREATE MATERIALIZED VIEW IF NOT EXISTS book_titles AS SELECT title FROM books;
+DO $sqlc$ BEGIN
+ALTER TABLE books ADD COLUMN year INT;
+EXCEPTION WHEN duplicate_column OR duplicate_object OR duplicate_table OR invalid_table_definition THEN NULL;
+END $sqlc$;
+ALTER TABLE books DROP COLUMN year;
+COMMENT ON TABLE books IS 'books'`
+	stmts, err := postgresql.NewParser().Parse(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := idempotentSchema(schema, stmts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+
+	schema = "CREATE TABLE books (id INT);\nCREATE INDEX ON books (id);"
+	stmts, err = postgresql.NewParser().Parse(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, pos, err := idempotentSchema(schema, stmts); err == nil || pos != strings.Index(schema, "CREATE INDEX") {
+		t.Errorf("unnamed index: got error %v at %d", err, pos)
+	}
+}
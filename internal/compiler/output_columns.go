@@ -10,6 +10,7 @@ import (
 	"github.com/sqlc-dev/sqlc/internal/sql/astutils"
 	"github.com/sqlc-dev/sqlc/internal/sql/lang"
 	"github.com/sqlc-dev/sqlc/internal/sql/sqlerr"
+	"github.com/sqlc-dev/sqlc/internal/sql/validate"
 )
 
 // OutputColumns determines which columns a statement will output
@@ -59,6 +60,8 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 	}
 
 	var targets *ast.List
+	var grouped bool
+	outer := map[string]bool{}
 	switch n := node.(type) {
 	case *ast.DeleteStmt:
 		targets = n.ReturningList
@@ -67,6 +70,11 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 	case *ast.SelectStmt:
 		targets = n.TargetList
 		isUnion := len(targets.Items) == 0 && n.Larg != nil
+		if n.FromClause != nil {
+			outerJoinedRels(n.FromClause, false, outer)
+		}
+		tables = nullableTables(n, tables, outer)
+		grouped = n.GroupClause != nil && len(n.GroupClause.Items) > 0
 
 		if n.GroupClause != nil {
 			for _, item := range n.GroupClause.Items {
@@ -113,7 +121,19 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 		// For UNION queries, targets is empty and we need to look for the
 		// columns in Largs.
 		if isUnion {
-			return c.outputColumns(qc, n.Larg)
+			cols, err := c.outputColumns(qc, n.Larg)
+			if err != nil {
+				return nil, err
+			}
+			// A column of a UNION may be NULL if it is in any of the queries
+			if n.Op == ast.Union {
+				if rcols, err := c.outputColumns(qc, n.Rarg); err == nil && len(rcols) == len(cols) {
+					for i := range cols {
+						cols[i].NotNull = cols[i].NotNull && rcols[i].NotNull
+					}
+				}
+			}
+			return cols, nil
 		}
 	case *ast.CallStmt:
 		targets = &ast.List{}
@@ -158,13 +178,17 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 				name = *res.Name
 			}
 			switch {
-			case lang.IsComparisonOperator(astutils.Join(n.Name, "")):
+			case n.Kind != ast.A_Expr_Kind_NULLIF && lang.IsComparisonOperator(astutils.Join(n.Name, "")):
 				// TODO: Generate a name for these operations
 				cols = append(cols, &Column{Name: name, DataType: "bool", NotNull: true})
-			case lang.IsMathematicalOperator(astutils.Join(n.Name, "")):
-				cols = append(cols, &Column{Name: name, DataType: "int", NotNull: true})
 			default:
-				cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
+				if col := c.inferColumn(qc, tables, grouped, name, n); col != nil {
+					cols = append(cols, col)
+				} else if lang.IsMathematicalOperator(astutils.Join(n.Name, "")) {
+					cols = append(cols, &Column{Name: name, DataType: "int", NotNull: true})
+				} else {
+					cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
+				}
 			}
 
 		case *ast.BoolExpr:
@@ -224,6 +248,8 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 				default:
 					cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
 				}
+			} else if col := c.inferColumn(qc, tables, grouped, name, n); col != nil {
+				cols = append(cols, col)
 			} else {
 				cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
 			}
@@ -236,12 +262,13 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 			var firstColumn *Column
 			var shouldNotBeNull bool
 			for _, arg := range n.Args.Items {
-				if _, ok := arg.(*ast.A_Const); ok {
-					shouldNotBeNull = true
-					continue
-				}
-				if ref, ok := arg.(*ast.ColumnRef); ok {
-					columns, err := outputColumnRefs(res, tables, ref)
+				switch arg := arg.(type) {
+				case *ast.A_Const:
+					if _, ok := arg.Val.(*ast.Null); !ok {
+						shouldNotBeNull = true
+					}
+				case *ast.ColumnRef:
+					columns, err := outputColumnRefs(res, tables, arg)
 					if err != nil {
 						return nil, err
 					}
@@ -251,12 +278,17 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 						}
 						shouldNotBeNull = shouldNotBeNull || c.NotNull
 					}
+				default:
+					if col := c.inferColumn(qc, tables, grouped, name, arg); col != nil {
+						shouldNotBeNull = shouldNotBeNull || col.NotNull
+					}
 				}
 			}
 			if firstColumn != nil {
 				firstColumn.NotNull = shouldNotBeNull
-				firstColumn.skipTableRequiredCheck = true
 				cols = append(cols, firstColumn)
+			} else if col := c.inferColumn(qc, tables, grouped, name, n); col != nil {
+				cols = append(cols, col)
 			} else {
 				cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
 			}
@@ -266,6 +298,13 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 
 				// add a column with a reference to an embedded table
 				if embed, ok := qc.embeds.Find(n); ok {
+					if scope := astutils.Join(n.Fields, "."); outer[scope] {
+						return nil, &sqlerr.Error{
+							Code:     "42804",
+							Message:  fmt.Sprintf("%s is on the nullable side of an outer join and can't be scanned into a struct; select its columns instead", embed.Orig()),
+							Location: res.Location,
+						}
+					}
 					cols = append(cols, &Column{
 						Name:       embed.Table.Name,
 						EmbedTable: embed.Table,
@@ -315,6 +354,11 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 			if res.Name != nil {
 				name = *res.Name
 			}
+			if col := c.inferColumn(qc, tables, grouped, name, n); col != nil {
+				col.IsFuncCall = true
+				cols = append(cols, col)
+				continue
+			}
 			fun, err := qc.catalog.ResolveFuncCall(n)
 			if err == nil {
 				cols = append(cols, &Column{
@@ -348,6 +392,8 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 				if res.Name != nil {
 					first.Name = *res.Name
 				}
+				// A subquery that returns no rows is NULL
+				first.NotNull = first.NotNull && alwaysOneRow(n.Subselect)
 				cols = append(cols, first)
 			default:
 				cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
@@ -384,6 +430,7 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 			if res.Name != nil {
 				first.Name = *res.Name
 			}
+			first.NotNull = first.NotNull && alwaysOneRow(n)
 			cols = append(cols, first)
 
 		default:
@@ -391,72 +438,142 @@ func (c *Compiler) outputColumns(qc *QueryCatalog, node ast.Node) ([]*Column, er
 			if res.Name != nil {
 				name = *res.Name
 			}
-			cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
+			if col := c.inferColumn(qc, tables, grouped, name, n); col != nil {
+				cols = append(cols, col)
+			} else {
+				cols = append(cols, &Column{Name: name, DataType: "any", NotNull: false})
+			}
 
 		}
 	}
 
-	if n, ok := node.(*ast.SelectStmt); ok {
-		for _, col := range cols {
-			if !col.NotNull || col.Table == nil || col.skipTableRequiredCheck {
-				continue
-			}
-			for _, f := range n.FromClause.Items {
-				if res := isTableRequired(f, col, tableRequired); res != tableNotFound {
-					col.NotNull = res == tableRequired
-					break
+	return cols, nil
+}
+
+// nullableTables adjusts the nullability of the columns of a SELECT's source
+// tables: the columns of tables on the nullable side of an outer join may be
+// NULL, while columns filtered with IS NOT NULL in WHERE may not. outer holds
+// the names found by outerJoinedRels.
+func nullableTables(n *ast.SelectStmt, tables []*Table, outer map[string]bool) []*Table {
+	filtered := notNullColumns(n.WhereClause, nil)
+	if len(outer) == 0 && len(filtered) == 0 {
+		return tables
+	}
+	adjusted := make([]*Table, 0, len(tables))
+	for _, t := range tables {
+		table := &Table{Rel: t.Rel}
+		for _, col := range t.Columns {
+			cp := *col
+			if outer[t.Rel.Name] {
+				cp.NotNull = false
+			}
+			for _, ref := range filtered {
+				parts := stringSlice(ref.Fields)
+				if parts[len(parts)-1] != col.Name {
+					continue
+				}
+				if len(parts) == 1 || parts[len(parts)-2] == t.Rel.Name {
+					cp.NotNull = true
 				}
 			}
+			table.Columns = append(table.Columns, &cp)
 		}
+		adjusted = append(adjusted, table)
 	}
-
-	return cols, nil
+	return adjusted
 }
 
-const (
-	tableNotFound = iota
-	tableRequired
-	tableOptional
-)
-
-func isTableRequired(n ast.Node, col *Column, prior int) int {
-	switch n := n.(type) {
-	case *ast.RangeVar:
-		if n.Alias == nil && *n.Relname == col.Table.Name {
-			return prior
-		}
-		if n.Alias != nil && *n.Alias.Aliasname == col.TableAlias && *n.Relname == col.Table.Name {
-			return prior
+// outerJoinedRels records the names of the FROM items on the nullable side of
+// an outer join.
+func outerJoinedRels(node ast.Node, nullable bool, rels map[string]bool) {
+	switch n := node.(type) {
+	case *ast.List:
+		for _, item := range n.Items {
+			outerJoinedRels(item, nullable, rels)
 		}
 	case *ast.JoinExpr:
-		helper := func(l, r int) int {
-			if res := isTableRequired(n.Larg, col, l); res != tableNotFound {
-				return res
-			}
-			if res := isTableRequired(n.Rarg, col, r); res != tableNotFound {
-				return res
-			}
-			return tableNotFound
-		}
+		left, right := nullable, nullable
 		switch n.Jointype {
 		case ast.JoinTypeLeft:
-			return helper(tableRequired, tableOptional)
+			right = true
 		case ast.JoinTypeRight:
-			return helper(tableOptional, tableRequired)
+			left = true
 		case ast.JoinTypeFull:
-			return helper(tableOptional, tableOptional)
-		case ast.JoinTypeInner:
-			return helper(tableRequired, tableRequired)
+			left, right = true, true
 		}
-	case *ast.List:
-		for _, item := range n.Items {
-			if res := isTableRequired(item, col, prior); res != tableNotFound {
-				return res
+		outerJoinedRels(n.Larg, left, rels)
+		outerJoinedRels(n.Rarg, right, rels)
+	case *ast.RangeVar:
+		if !nullable {
+			return
+		}
+		if n.Alias != nil {
+			rels[*n.Alias.Aliasname] = true
+		} else if n.Relname != nil {
+			rels[*n.Relname] = true
+		}
+	case *ast.RangeSubselect:
+		if nullable && n.Alias != nil {
+			rels[*n.Alias.Aliasname] = true
+		}
+	case *ast.RangeFunction:
+		if !nullable {
+			return
+		}
+		if n.Alias != nil {
+			rels[*n.Alias.Aliasname] = true
+		} else if n.Functions != nil && len(n.Functions.Items) > 0 {
+			item := n.Functions.Items[0]
+			if list, ok := item.(*ast.List); ok && len(list.Items) > 0 {
+				item = list.Items[0]
+			}
+			if call, ok := item.(*ast.FuncCall); ok {
+				rels[call.Func.Name] = true
+			}
+		}
+	}
+}
+
+// notNullColumns returns the columns tested with IS NOT NULL in the
+// top-level conjunction of a WHERE clause.
+func notNullColumns(node ast.Node, refs []*ast.ColumnRef) []*ast.ColumnRef {
+	switch n := node.(type) {
+	case *ast.BoolExpr:
+		if n.Boolop == ast.BoolExprTypeAnd {
+			for _, arg := range n.Args.Items {
+				refs = notNullColumns(arg, refs)
 			}
 		}
+	case *ast.NullTest:
+		if ref, ok := n.Arg.(*ast.ColumnRef); ok && n.Nulltesttype == ast.NullTestTypeIsNotNull && !hasStarRef(ref) {
+			refs = append(refs, ref)
+		}
 	}
+	return refs
+}
 
-	return tableNotFound
+// alwaysOneRow reports whether a scalar subquery is a count without GROUP BY,
+// which yields exactly one row, so that its value is never replaced by NULL
+// for lack of a row.
+func alwaysOneRow(node ast.Node) bool {
+	n, ok := node.(*ast.SelectStmt)
+	if !ok || n.Op != ast.None || n.TargetList == nil || len(n.TargetList.Items) == 0 {
+		return false
+	}
+	if n.GroupClause != nil && len(n.GroupClause.Items) > 0 {
+		return false
+	}
+	for _, clause := range []ast.Node{n.HavingClause, n.LimitCount, n.LimitOffset} {
+		if _, ok := clause.(*ast.TODO); clause != nil && !ok {
+			return false
+		}
+	}
+	res, ok := n.TargetList.Items[0].(*ast.ResTarget)
+	if !ok {
+		return false
+	}
+	call, ok := res.Val.(*ast.FuncCall)
+	return ok && call.Over == nil && call.Func.Name == "count"
 }
 
 type tableVisitor struct {
@@ -522,53 +639,11 @@ func (c *Compiler) sourceTables(qc *QueryCatalog, node ast.Node) ([]*Table, erro
 		switch n := item.(type) {
 
 		case *ast.RangeFunction:
-			var funcCall *ast.FuncCall
-			switch f := n.Functions.Items[0].(type) {
-			case *ast.List:
-				switch fi := f.Items[0].(type) {
-				case *ast.FuncCall:
-					funcCall = fi
-				case *ast.SQLValueFunction:
-					continue // TODO handle this correctly
-				default:
-					continue
-				}
-			case *ast.FuncCall:
-				funcCall = f
-			default:
-				return nil, fmt.Errorf("sourceTables: unsupported function call type %T", n.Functions.Items[0])
-			}
-
 			// If the function or table can't be found, don't error out.  There
 			// are many queries that depend on functions unknown to sqlc.
-			fn, err := qc.GetFunc(funcCall.Func)
-			if err != nil {
-				continue
+			if table := c.rangeFunctionTable(qc, n, tables); table != nil {
+				tables = append(tables, table)
 			}
-			table, err := qc.GetTable(&ast.TableName{
-				Catalog: fn.ReturnType.Catalog,
-				Schema:  fn.ReturnType.Schema,
-				Name:    fn.ReturnType.Name,
-			})
-			if err != nil {
-				if n.Alias == nil || len(n.Alias.Colnames.Items) == 0 {
-					continue
-				}
-
-				table = &Table{}
-				for _, colName := range n.Alias.Colnames.Items {
-					table.Columns = append(table.Columns, &Column{
-						Name:     colName.(*ast.String).Str,
-						DataType: "any",
-					})
-				}
-			}
-			if n.Alias != nil {
-				table.Rel = &ast.TableName{
-					Name: *n.Alias.Aliasname,
-				}
-			}
-			tables = append(tables, table)
 
 		case *ast.RangeSubselect:
 			cols, err := c.outputColumns(qc, n.Subquery)
@@ -740,3 +815,158 @@ func findColumnForRef(ref *ast.ColumnRef, tables []*Table, targetList *ast.List)
 
 	return nil
 }
+
+// rangeFunctionTable returns the table produced by the functions of a
+// FROM-clause item, or nil if its columns are unknown. The tables to the
+// left of the item are visible to the function arguments, as with LATERAL.
+func (c *Compiler) rangeFunctionTable(qc *QueryCatalog, n *ast.RangeFunction, scope []*Table) *Table {
+	if n.Functions == nil || len(n.Functions.Items) == 0 {
+		return nil
+	}
+	var colnames []string
+	if n.Alias != nil && n.Alias.Colnames != nil {
+		for _, item := range n.Alias.Colnames.Items {
+			colnames = append(colnames, item.(*ast.String).Str)
+		}
+	}
+
+	var rel string
+	var cols []*Column
+	scalar := len(n.Functions.Items) == 1
+	// The results of several functions, like the arrays of a multi-argument
+	// unnest, are padded with NULLs up to the longest one.
+	padded := len(n.Functions.Items) > 1
+	for _, item := range n.Functions.Items {
+		var coldefs *ast.List
+		if n.Coldeflist != nil && len(n.Coldeflist.Items) > 0 {
+			coldefs = n.Coldeflist
+		}
+		if list, ok := item.(*ast.List); ok && len(list.Items) > 0 {
+			item = list.Items[0]
+			if len(list.Items) > 1 {
+				if defs, ok := list.Items[1].(*ast.List); ok && len(defs.Items) > 0 {
+					coldefs = defs
+				}
+			}
+		}
+		if fn, ok := item.(*ast.FuncCall); ok && fn.Func != nil && fn.Func.Name == "unnest" && fn.Args != nil && len(fn.Args.Items) > 1 {
+			padded = true
+		}
+		name, fcols, composite := c.functionColumns(qc, item, coldefs, scope)
+		if fcols == nil {
+			// Fall back to untyped columns named by the alias
+			if len(colnames) == 0 {
+				return nil
+			}
+			cols = nil
+			for _, colname := range colnames {
+				cols = append(cols, &Column{Name: colname, DataType: "any"})
+			}
+			scalar = false
+			break
+		}
+		if rel == "" {
+			rel = name
+		}
+		if composite || len(fcols) > 1 {
+			scalar = false
+		}
+		for _, col := range fcols {
+			cp := *col
+			cols = append(cols, &cp)
+		}
+	}
+	if padded {
+		for _, col := range cols {
+			col.NotNull = false
+		}
+	}
+	if n.Ordinality {
+		cols = append(cols, &Column{
+			Name:     "ordinality",
+			DataType: "bigint",
+			NotNull:  true,
+		})
+	}
+
+	if n.Alias != nil && n.Alias.Aliasname != nil {
+		rel = *n.Alias.Aliasname
+		// A function returning a scalar is named after the alias
+		if scalar && len(colnames) == 0 {
+			cols[0].Name = rel
+		}
+	}
+	for i, colname := range colnames {
+		if i < len(cols) {
+			cols[i].Name = colname
+		}
+	}
+	return &Table{
+		Rel:     &ast.TableName{Name: rel},
+		Columns: cols,
+	}
+}
+
+// functionColumns returns the name and the output columns of a single
+// function in FROM, or nil columns if they are unknown. composite is set when
+// the columns are those of a row type rather than a single scalar value.
+func (c *Compiler) functionColumns(qc *QueryCatalog, node ast.Node, coldefs *ast.List, scope []*Table) (name string, cols []*Column, composite bool) {
+	switch n := node.(type) {
+
+	case *ast.SQLValueFunction:
+		f, ok := sqlValueFunctions[n.Op]
+		if !ok {
+			return "", nil, false
+		}
+		t := builtinType(f[1])
+		return f[0], []*Column{{Name: f[0], DataType: t.DataType, NotNull: true}}, false
+
+	case *ast.FuncCall:
+		name = n.Func.Name
+		if coldefs != nil {
+			for _, item := range coldefs.Items {
+				def, ok := item.(*ast.ColumnDef)
+				if !ok || def.TypeName == nil {
+					return name, nil, false
+				}
+				col := toColumn(def.TypeName)
+				col.Name = def.Colname
+				col.NotNull = def.IsNotNull
+				cols = append(cols, col)
+			}
+			return name, cols, true
+		}
+
+		if validate.IsMultiUnnest(n) {
+			for _, arg := range n.Args.Items {
+				col := c.inferColumn(qc, scope, false, name, arg)
+				if col == nil || !col.IsArray {
+					col = &Column{Name: name, DataType: "any"}
+				}
+				col.IsArray = false
+				col.ArrayDims = 0
+				cols = append(cols, col)
+			}
+			return name, cols, false
+		}
+
+		fn, err := qc.GetFunc(n.Func)
+		if err != nil {
+			return name, nil, false
+		}
+		table, err := qc.GetTable(&ast.TableName{
+			Catalog: fn.ReturnType.Catalog,
+			Schema:  fn.ReturnType.Schema,
+			Name:    fn.ReturnType.Name,
+		})
+		if err == nil {
+			return name, table.Columns, true
+		}
+		col := c.inferColumn(qc, scope, false, name, n)
+		if col == nil {
+			return name, nil, false
+		}
+		return name, []*Column{col}, false
+	}
+	return "", nil, false
+}
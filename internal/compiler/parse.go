@@ -21,7 +21,10 @@ import (
 
 var ErrUnsupportedStatementType = errors.New("parseQuery: unsupported statement type")
 
-func (c *Compiler) parseQuery(stmt ast.Node, src string, o opts.Parser) (*Query, error) {
+// parseQuery compiles the statement stmt of src. splits are the offsets of
+// the spaces SplitParamSigns inserted into src, which are removed again from
+// the SQL of the query.
+func (c *Compiler) parseQuery(stmt ast.Node, src string, splits []int, o opts.Parser) (*Query, error) {
 	if o.Debug.DumpAST {
 		debug.Dump(stmt)
 	}
@@ -77,6 +80,15 @@ func (c *Compiler) parseQuery(stmt ast.Node, src string, o opts.Parser) (*Query,
 		return nil, err
 	}
 	raw, namedParams, edits := rewrite.NamedParameters(c.conf.Engine, raw, numbers, dollar)
+	for _, split := range splits {
+		if raw.StmtLocation <= split && split < raw.StmtLocation+raw.StmtLen {
+			edits = append(edits, source.Edit{
+				Location: split - raw.StmtLocation,
+				Old:      " ",
+				New:      "",
+			})
+		}
+	}
 	if err := validate.Cmd(
 		raw.Stmt, queryConfig.Name, queryConfig.Cmd, queryConfig.Options); err != nil {
 		return nil, err
@@ -103,7 +115,8 @@ func (c *Compiler) parseQuery(stmt ast.Node, src string, o opts.Parser) (*Query,
 		return nil, err
 	}
 
-	params, err := c.resolveCatalogRefs(qc, rvs, refs, namedParams, embeds)
+	types := c.inferParamTypes(qc, raw.Stmt)
+	params, err := c.resolveCatalogRefs(qc, rvs, refs, namedParams, embeds, types)
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +124,20 @@ func (c *Compiler) parseQuery(stmt ast.Node, src string, o opts.Parser) (*Query,
 	if err != nil {
 		return nil, err
 	}
+	if err := validateColumnHints(queryConfig.Name, queryConfig.Options, cols); err != nil {
+		return nil, err
+	}
 
 	expandEdits, err := c.expand(qc, raw)
 	if err != nil {
 		return nil, err
 	}
 	edits = append(edits, expandEdits...)
+	if c.conf.Engine == config.EnginePostgreSQL {
+		if err := rewrite.NotReplaced(rawSQL, raw.StmtLocation, edits); err != nil {
+			return nil, err
+		}
+	}
 	expanded, err := source.Mutate(rawSQL, edits)
 	if err != nil {
 		return nil, err
@@ -332,6 +353,40 @@ func validateAndSetDefaultOptions(n ast.Node, name, cmd string, options map[stri
 		return fmt.Errorf("query %q uses invalidate option but is a SELECT", name)
 	}
 
+	_, hasCache := options[golang.WPgxOptionKeyCache]
+	if _, ok := options[golang.WPgxOptionKeyCacheVersion]; ok && !hasCache {
+		return fmt.Errorf("query %q uses cache_version option but is not cached", name)
+	}
+
+	return nil
+}
+
+// validateColumnHints checks that column hints refer to result columns of the
+// query and name valid Go types.
+func validateColumnHints(name string, options map[string]string, cols []*Column) error {
+	for key, val := range options {
+		if !strings.HasPrefix(key, metadata.ColumnHintKey("")) {
+			continue
+		}
+		hint, err := metadata.ParseColumnHint(val)
+		if err != nil {
+			return err
+		}
+		var found bool
+		for _, col := range cols {
+			if col.Name == hint.Column {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("query %q has a hint for unknown column %q", name, hint.Column)
+		}
+		if hint.GoType != "" {
+			if _, err := (config.GoType{Spec: hint.GoType}).Parse(); err != nil {
+				return fmt.Errorf("query %q has an invalid type for column %q: %w", name, hint.Column, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,113 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+)
+
+func TestNamedParameters(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schema, []byte(typecheckSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if err := c.ParseCatalog([]string{schema}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		query  string
+		sql    string
+		params string
+		err    string
+		column int
+	}{
+		{
+			query:  "SELECT id FROM books WHERE qty=@qty AND price<>@price::numeric",
+			sql:    "SELECT id FROM books WHERE qty=$1 AND price<>$2::numeric",
+			params: "qty,price",
+		},
+		{
+			query:  "SELECT id FROM books WHERE name = sqlc.arg(\n  name\n) AND qty > sqlc.narg ( 'qty' )",
+			sql:    "SELECT id FROM books WHERE name = $1 AND qty > $2",
+			params: "name,qty",
+		},
+		{
+			query:  "SELECT id FROM books WHERE qty = @ /* count */ qty AND active = @active\n::boolean",
+			sql:    "SELECT id FROM books WHERE qty = $1 AND active = $2\n::boolean",
+			params: "qty,active",
+		},
+		{
+			query:  "SELECT id FROM books WHERE tags<@@tags AND note = '@note' -- @comment\n",
+			sql:    "SELECT id FROM books WHERE tags<@$1 AND note = '@note' -- @comment",
+			params: "tags",
+		},
+		{
+			query:  "SELECT id FROM books WHERE id IN (sqlc.slice(ids)) AND qty NOT IN ( sqlc.slice('qtys') )",
+			sql:    "SELECT id FROM books WHERE id = ANY($1) AND qty <> ALL($2)",
+			params: "ids,qtys",
+		},
+		{
+			query:  "UPDATE books SET name=@name, qty =@qty WHERE id=@id",
+			sql:    "UPDATE books SET name=$1, qty =$2 WHERE id=$3",
+			params: "name,qty,id",
+		},
+		{
+			query:  "INSERT INTO books (id, name) VALUES (@id, @name) ON CONFLICT (id) DO UPDATE SET name=@new_name",
+			sql:    "INSERT INTO books (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name=$3",
+			params: "id,name,new_name",
+		},
+		{
+			query:  "SELECT id FROM books WHERE qty*@factor+1 > 10",
+			err:    "named parameter @factor could not be rewritten",
+			column: 32,
+		},
+		{
+			query:  "UPDATE books SET name=@name WHERE qty*@factor+1 > 10",
+			err:    "named parameter @factor could not be rewritten",
+			column: 39,
+		},
+	} {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			src := fmt.Sprintf("-- name: Test :exec\n%s;\n", tc.query)
+			queries, err := c.ParseQuerySource("query.sql", src, opts.Parser{})
+			if tc.err != "" {
+				var merr *multierr.Error
+				if !errors.As(err, &merr) || len(merr.Errs()) != 1 {
+					t.Fatalf("want error %q, got %v", tc.err, err)
+				}
+				ferr := merr.Errs()[0]
+				if !strings.Contains(ferr.Err.Error(), tc.err) {
+					t.Errorf("want error %q, got %v", tc.err, ferr.Err)
+				}
+				if ferr.Line != 2 || ferr.Column != tc.column {
+					t.Errorf("want error at 2:%d, got %d:%d", tc.column, ferr.Line, ferr.Column)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := queries[0].SQL; got != tc.sql {
+				t.Errorf("sql: want %q, got %q", tc.sql, got)
+			}
+			var names []string
+			for _, p := range queries[0].Params {
+				names = append(names, p.Column.Name)
+			}
+			if got := strings.Join(names, ","); got != tc.params {
+				t.Errorf("params: want %s, got %s", tc.params, got)
+			}
+		})
+	}
+}
@@ -35,8 +35,6 @@ type Column struct {
 	EmbedTable *ast.TableName
 
 	IsSqlcSlice bool // is this sqlc.slice()
-
-	skipTableRequiredCheck bool
 }
 
 type Query struct {
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/sqlc-dev/sqlc/internal/sql/ast"
@@ -20,7 +21,7 @@ func dataType(n *ast.TypeName) string {
 	}
 }
 
-func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar, args []paramRef, params *named.ParamSet, embeds rewrite.EmbedSet) ([]Parameter, error) {
+func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar, args []paramRef, params *named.ParamSet, embeds rewrite.EmbedSet, types map[int]exprType) ([]Parameter, error) {
 	c := comp.catalog
 
 	aliasMap := map[string]*ast.TableName{}
@@ -186,6 +187,10 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 							}
 						}
 						if !located {
+							if p, ok := inferredParam(ref.ref.Number, params, types); ok {
+								a = append(a, p)
+								continue
+							}
 							return nil, &sqlerr.Error{
 								Code:     "42703",
 								Message:  fmt.Sprintf("table alias %q does not exist", alias),
@@ -229,6 +234,10 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 				}
 
 				if found == 0 {
+					if p, ok := inferredParam(ref.ref.Number, params, types); ok {
+						a = append(a, p)
+						continue
+					}
 					return nil, &sqlerr.Error{
 						Code:     "42703",
 						Message:  fmt.Sprintf("column %q does not exist", key),
@@ -246,7 +255,7 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 
 		case *ast.BetweenExpr:
 			if n == nil || n.Expr == nil || n.Left == nil || n.Right == nil {
-				fmt.Println("ast.BetweenExpr is nil")
+				fmt.Fprintln(os.Stderr, "ast.BetweenExpr is nil")
 				continue
 			}
 
@@ -422,6 +431,10 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 
 		case *ast.ResTarget:
 			if n.Name == nil {
+				if p, ok := inferredParam(ref.ref.Number, params, types); ok {
+					a = append(a, p)
+					continue
+				}
 				return nil, fmt.Errorf("*ast.ResTarget has nil name")
 			}
 			key := *n.Name
@@ -446,6 +459,10 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 
 			tableMap, ok := typeMap[schema][rel]
 			if !ok {
+				if p, ok := inferredParam(ref.ref.Number, params, types); ok {
+					a = append(a, p)
+					continue
+				}
 				return nil, sqlerr.RelationNotFound(rel)
 			}
 
@@ -468,6 +485,8 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 						IsSqlcSlice:  p.IsSqlcSlice(),
 					},
 				})
+			} else if p, ok := inferredParam(ref.ref.Number, params, types); ok {
+				a = append(a, p)
 			} else {
 				return nil, &sqlerr.Error{
 					Code:     "42703",
@@ -496,7 +515,7 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 
 		case *ast.In:
 			if n == nil || n.List == nil {
-				fmt.Println("ast.In is nil")
+				fmt.Fprintln(os.Stderr, "ast.In is nil")
 				continue
 			}
 
@@ -581,7 +600,7 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 					}
 				}
 			} else {
-				fmt.Println("------------------------")
+				fmt.Fprintln(os.Stderr, "------------------------")
 			}
 
 			if found == 0 {
@@ -600,8 +619,38 @@ func (comp *Compiler) resolveCatalogRefs(qc *QueryCatalog, rvs []*ast.RangeVar,
 			}
 
 		default:
-			fmt.Printf("unsupported reference type: %T\n", n)
+			fmt.Fprintf(os.Stderr, "unsupported reference type: %T\n", n)
+		}
+	}
+
+	// Prefer the types inferred by the type checker where the references
+	// above fall back to any, guess the type of an operand from the first
+	// column found in an expression, or take the first overload of a function
+	// with a matching number of arguments.
+	parents := map[int]ast.Node{}
+	for _, ref := range args {
+		parents[ref.ref.Number] = ref.parent
+	}
+	for i, p := range a {
+		t, ok := types[p.Number]
+		if !ok {
+			continue
+		}
+		if p.Column != nil {
+			legacy := exprType{DataType: p.Column.DataType, IsArray: p.Column.IsArray}
+			untyped := legacy.DataType == "" || isPolymorphic(legacy.DataType)
+			if !untyped && implicitCast(legacy, t) && implicitCast(t, legacy) {
+				continue
+			}
+			switch parents[p.Number].(type) {
+			case *ast.A_Expr, *ast.BetweenExpr, *ast.In, *ast.FuncCall:
+			default:
+				if !untyped {
+					continue
+				}
+			}
 		}
+		a[i], _ = inferredParam(p.Number, params, types)
 	}
 	return a, nil
 }
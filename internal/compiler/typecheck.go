@@ -0,0 +1,1408 @@
+package compiler
+
+import (
+	"math"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/astutils"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+	"github.com/sqlc-dev/sqlc/internal/sql/lang"
+	"github.com/sqlc-dev/sqlc/internal/sql/named"
+)
+
+// exprType is the type inferred for an expression. An empty DataType means
+// the type is unknown.
+type exprType struct {
+	DataType  string
+	IsArray   bool
+	ArrayDims int
+	NotNull   bool
+
+	// name is the default name of a parameter taking this type, such as the
+	// column or function argument it is compared to.
+	name string
+	// col is set when the expression refers to a column.
+	col *Column
+	// literal is set for constants that take the type of the other operand,
+	// such as string literals and NULL.
+	literal bool
+}
+
+func (t exprType) known() bool {
+	return t.DataType != ""
+}
+
+// value returns t without the properties that only hold for the expression
+// it was inferred from.
+func (t exprType) value() exprType {
+	t.col = nil
+	t.literal = false
+	return t
+}
+
+func (t exprType) elem() exprType {
+	t.IsArray = false
+	t.ArrayDims = 0
+	return t
+}
+
+func (t exprType) array() exprType {
+	if !t.IsArray {
+		t.IsArray = true
+		t.ArrayDims = 1
+	}
+	return t
+}
+
+func (t exprType) nullable(notNull bool) exprType {
+	t.NotNull = notNull
+	return t
+}
+
+// typeKeys maps the spellings of the built-in types to a single name.
+var typeKeys = map[string]string{
+	"smallint":                    "int2",
+	"smallserial":                 "int2",
+	"serial2":                     "int2",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"serial":                      "int4",
+	"serial4":                     "int4",
+	"bigint":                      "int8",
+	"bigserial":                   "int8",
+	"serial8":                     "int8",
+	"decimal":                     "numeric",
+	"real":                        "float4",
+	"float":                       "float8",
+	"double precision":            "float8",
+	"boolean":                     "bool",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"string":                      "text",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+}
+
+// typeNames spells the types synthesized by the type checker the way the
+// code generators expect them.
+var typeNames = map[string]string{
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"float4":      "real",
+	"float8":      "double precision",
+	"bool":        "boolean",
+	"varchar":     "pg_catalog.varchar",
+	"bpchar":      "pg_catalog.bpchar",
+	"time":        "pg_catalog.time",
+	"timetz":      "pg_catalog.timetz",
+	"timestamp":   "pg_catalog.timestamp",
+	"timestamptz": "pg_catalog.timestamptz",
+}
+
+var numericRank = map[string]int{
+	"int2":    1,
+	"int4":    2,
+	"int8":    3,
+	"numeric": 4,
+	"float4":  5,
+	"float8":  6,
+}
+
+var datetimeRank = map[string]int{
+	"date":        1,
+	"timestamp":   2,
+	"timestamptz": 3,
+}
+
+var textTypes = map[string]bool{
+	"text":    true,
+	"varchar": true,
+	"bpchar":  true,
+	"name":    true,
+	"citext":  true,
+}
+
+func typeKey(dataType string) string {
+	name := strings.ToLower(strings.TrimPrefix(dataType, "pg_catalog."))
+	if key, ok := typeKeys[name]; ok {
+		return key
+	}
+	return name
+}
+
+func isPolymorphic(dataType string) bool {
+	switch typeKey(dataType) {
+	case "any", "anyelement", "anyarray", "anynonarray", "anyenum",
+		"anycompatible", "anycompatiblearray", "anycompatiblenonarray",
+		"anyrange", "anymultirange", "anycompatiblerange", "anycompatiblemultirange":
+		return true
+	}
+	return false
+}
+
+// rangeElems maps the built-in range types to the type of their bounds.
+var rangeElems = map[string]string{
+	"int4range": "int4",
+	"int8range": "int8",
+	"numrange":  "numeric",
+	"tsrange":   "timestamp",
+	"tstzrange": "timestamptz",
+	"daterange": "date",
+}
+
+// multirangeElems maps the built-in multirange types to the type of their
+// bounds.
+var multirangeElems = map[string]string{
+	"int4multirange": "int4",
+	"int8multirange": "int8",
+	"nummultirange":  "numeric",
+	"tsmultirange":   "timestamp",
+	"tstzmultirange": "timestamptz",
+	"datemultirange": "date",
+}
+
+// rangeOf returns the range or multirange type, from elems, of elem.
+func rangeOf(elems map[string]string, elem exprType) exprType {
+	if elem.IsArray {
+		return exprType{}
+	}
+	key := typeKey(elem.DataType)
+	for name, k := range elems {
+		if k == key {
+			return builtinType(name)
+		}
+	}
+	return exprType{}
+}
+
+func builtinType(key string) exprType {
+	name, ok := typeNames[key]
+	if !ok {
+		name = key
+	}
+	return exprType{DataType: name, NotNull: true}
+}
+
+var boolType = builtinType("bool")
+
+// sqlValueFunctions maps the SQL-standard functions called without
+// parentheses, such as CURRENT_DATE, to their name and type.
+var sqlValueFunctions = map[ast.SQLValueFunctionOp][2]string{
+	ast.SQLValueFunctionOpCurrentDate:       {"current_date", "date"},
+	ast.SQLValueFunctionOpCurrentTime:       {"current_time", "timetz"},
+	ast.SQLValueFunctionOpCurrentTimeN:      {"current_time", "timetz"},
+	ast.SQLValueFunctionOpCurrentTimestamp:  {"current_timestamp", "timestamptz"},
+	ast.SQLValueFunctionOpCurrentTimestampN: {"current_timestamp", "timestamptz"},
+	ast.SQLValueFunctionOpLocaltime:         {"localtime", "time"},
+	ast.SQLValueFunctionOpLocaltimeN:        {"localtime", "time"},
+	ast.SQLValueFunctionOpLocaltimestamp:    {"localtimestamp", "timestamp"},
+	ast.SQLValueFunctionOpLocaltimestampN:   {"localtimestamp", "timestamp"},
+	ast.SQLValueFunctionOpCurrentRole:       {"current_role", "name"},
+	ast.SQLValueFunctionOpCurrentUser:       {"current_user", "name"},
+	ast.SQLValueFunctionOpUser:              {"current_user", "name"},
+	ast.SQLValueFunctionOpSessionUser:       {"session_user", "name"},
+	ast.SQLValueFunctionOpCurrentCatalog:    {"current_catalog", "name"},
+	ast.SQLValueFunctionOpCurrentSchema:     {"current_schema", "name"},
+}
+
+// catalogType converts a type from a function signature, where arrays are
+// spelled with a suffix.
+func catalogType(n *ast.TypeName) exprType {
+	if n == nil {
+		return exprType{}
+	}
+	name := dataType(n)
+	var t exprType
+	for strings.HasSuffix(name, "[]") {
+		name = strings.TrimSuffix(name, "[]")
+		t.IsArray = true
+		t.ArrayDims++
+	}
+	if n.ArrayBounds != nil && len(n.ArrayBounds.Items) > 0 {
+		t.IsArray = true
+		t.ArrayDims += len(n.ArrayBounds.Items)
+	}
+	switch key := typeKey(name); key {
+	case "void", "record", "trigger", "cstring", "internal":
+		return exprType{}
+	default:
+		if spelled, ok := typeNames[key]; ok && !strings.HasPrefix(name, "pg_catalog.") {
+			name = spelled
+		}
+	}
+	t.DataType = name
+	t.NotNull = true
+	return t
+}
+
+func columnType(c *Column) exprType {
+	return exprType{
+		DataType:  c.DataType,
+		IsArray:   c.IsArray,
+		ArrayDims: c.ArrayDims,
+		NotNull:   c.NotNull,
+		name:      c.Name,
+		col:       c,
+	}
+}
+
+func sameType(a, b exprType) bool {
+	return typeKey(a.DataType) == typeKey(b.DataType) && a.IsArray == b.IsArray
+}
+
+// implicitCast reports whether a value of type from can be used where type to
+// is expected without an explicit cast.
+func implicitCast(from, to exprType) bool {
+	if from.IsArray != to.IsArray {
+		return false
+	}
+	fk, tk := typeKey(from.DataType), typeKey(to.DataType)
+	switch {
+	case fk == tk:
+		return true
+	case numericRank[fk] > 0 && numericRank[tk] > 0:
+		return numericRank[fk] <= numericRank[tk]
+	case datetimeRank[fk] > 0 && datetimeRank[tk] > 0:
+		return datetimeRank[fk] <= datetimeRank[tk]
+	case textTypes[fk] && textTypes[tk]:
+		return true
+	}
+	return false
+}
+
+// commonType resolves the type of expressions combined by CASE, COALESCE,
+// IN and similar constructs. Unknown expressions are ignored.
+func commonType(types []exprType) exprType {
+	var common exprType
+	for _, t := range types {
+		if !t.known() {
+			continue
+		}
+		if !common.known() || (common.literal && !t.literal) {
+			common = t
+			continue
+		}
+		if t.literal {
+			continue
+		}
+		if implicitCast(common, t) && !implicitCast(t, common) {
+			common = t
+		}
+	}
+	return common.value()
+}
+
+// typeChecker infers the types of expressions bottom-up, using the types of
+// the operands and the functions in the catalog. Parameters without a type of
+// their own take the type expected by the surrounding expression.
+type typeChecker struct {
+	qc     *QueryCatalog
+	comp   *Compiler
+	scopes [][]*Table
+	params map[int]exprType
+	// grouped is set in SELECT statements with GROUP BY, where aggregates
+	// are computed from at least one row.
+	grouped bool
+}
+
+func (comp *Compiler) newTypeChecker(qc *QueryCatalog, tables []*Table) *typeChecker {
+	tc := &typeChecker{qc: qc, comp: comp, params: map[int]exprType{}}
+	if tables != nil {
+		tc.scopes = append(tc.scopes, tables)
+	}
+	return tc
+}
+
+// inferParamTypes returns the types inferred for the parameters of a
+// statement, keyed by parameter number.
+func (comp *Compiler) inferParamTypes(qc *QueryCatalog, stmt ast.Node) map[int]exprType {
+	if comp.conf.Engine != config.EnginePostgreSQL {
+		return nil
+	}
+	tc := comp.newTypeChecker(qc, nil)
+	tc.checkStmt(stmt, nil)
+	return tc.params
+}
+
+// inferColumn returns the output column of an expression, or nil if its type
+// can't be inferred. grouped is set for the targets of a SELECT with GROUP BY.
+func (comp *Compiler) inferColumn(qc *QueryCatalog, tables []*Table, grouped bool, name string, node ast.Node) *Column {
+	if comp.conf.Engine != config.EnginePostgreSQL {
+		return nil
+	}
+	tc := comp.newTypeChecker(qc, tables)
+	tc.grouped = grouped
+	t := tc.infer(node, exprType{})
+	if !t.known() {
+		return nil
+	}
+	return &Column{
+		Name:      name,
+		DataType:  t.DataType,
+		NotNull:   t.NotNull,
+		IsArray:   t.IsArray,
+		ArrayDims: t.ArrayDims,
+	}
+}
+
+// inferredParam builds a parameter from the type inferred for it.
+func inferredParam(number int, params *named.ParamSet, types map[int]exprType) (Parameter, bool) {
+	t, ok := types[number]
+	if !ok {
+		return Parameter{}, false
+	}
+	defaultP := named.NewInferredParam(t.name, t.NotNull)
+	p, isNamed := params.FetchMerge(number, defaultP)
+	col := &Column{
+		Name:         p.Name(),
+		DataType:     t.DataType,
+		NotNull:      p.NotNull(),
+		IsArray:      t.IsArray,
+		ArrayDims:    t.ArrayDims,
+		IsNamedParam: isNamed,
+		IsSqlcSlice:  p.IsSqlcSlice(),
+	}
+	if t.col != nil {
+		col.OriginalName = t.col.Name
+		col.Table = t.col.Table
+		col.Unsigned = t.col.Unsigned
+		col.Length = t.col.Length
+	}
+	return Parameter{Number: number, Column: col}, true
+}
+
+func (tc *typeChecker) setParam(number int, t exprType) {
+	if _, ok := tc.params[number]; ok {
+		return
+	}
+	t.literal = false
+	tc.params[number] = t
+}
+
+func (tc *typeChecker) push(tables []*Table) {
+	tc.scopes = append(tc.scopes, tables)
+}
+
+func (tc *typeChecker) pop() {
+	tc.scopes = tc.scopes[:len(tc.scopes)-1]
+}
+
+// checkStmt infers the types of the parameters of a statement. wants are the
+// types expected for the columns it outputs, such as the target columns of
+// INSERT ... SELECT. It returns the types of the output columns of SELECT.
+func (tc *typeChecker) checkStmt(node ast.Node, wants []exprType) []exprType {
+	switch n := node.(type) {
+
+	case *ast.SelectStmt:
+		return tc.checkSelect(n, wants)
+
+	case *ast.InsertStmt:
+		tc.checkWith(n.WithClause)
+		var table *Table
+		if fqn, err := ParseTableName(n.Relation); err == nil {
+			table, _ = tc.qc.GetTable(fqn)
+		}
+		var cols []exprType
+		if table != nil && n.Cols != nil {
+			for _, item := range n.Cols.Items {
+				var t exprType
+				if res, ok := item.(*ast.ResTarget); ok && res.Name != nil {
+					t = tableColumnType(table, *res.Name)
+				}
+				cols = append(cols, t)
+			}
+		}
+		tc.checkStmt(n.SelectStmt, cols)
+		if table != nil {
+			tc.push([]*Table{table})
+			defer tc.pop()
+		}
+		if oc := n.OnConflictClause; oc != nil {
+			tc.checkAssignments(table, oc.TargetList)
+			tc.infer(oc.WhereClause, boolType)
+		}
+		tc.checkTargets(n.ReturningList)
+
+	case *ast.UpdateStmt:
+		tc.checkWith(n.WithClause)
+		tables, _ := tc.comp.sourceTables(tc.qc, n)
+		tc.push(tables)
+		defer tc.pop()
+		var table *Table
+		if n.Relations != nil && len(n.Relations.Items) > 0 {
+			if rv, ok := n.Relations.Items[0].(*ast.RangeVar); ok {
+				if fqn, err := ParseTableName(rv); err == nil {
+					table, _ = tc.qc.GetTable(fqn)
+				}
+			}
+		}
+		tc.checkFrom(n.FromClause)
+		tc.checkAssignments(table, n.TargetList)
+		tc.infer(n.WhereClause, boolType)
+		tc.checkTargets(n.ReturningList)
+
+	case *ast.DeleteStmt:
+		tc.checkWith(n.WithClause)
+		tables, _ := tc.comp.sourceTables(tc.qc, n)
+		tc.push(tables)
+		defer tc.pop()
+		tc.infer(n.WhereClause, boolType)
+		tc.checkTargets(n.ReturningList)
+	}
+	return nil
+}
+
+func (tc *typeChecker) checkSelect(n *ast.SelectStmt, wants []exprType) []exprType {
+	if n.Larg != nil || n.Rarg != nil {
+		types := tc.checkStmt(n.Larg, wants)
+		if len(types) == 0 {
+			types = wants
+		}
+		tc.checkStmt(n.Rarg, types)
+		return types
+	}
+	tc.checkWith(n.WithClause)
+
+	if n.ValuesLists != nil && len(n.ValuesLists.Items) > 0 {
+		for _, item := range n.ValuesLists.Items {
+			list, ok := item.(*ast.List)
+			if !ok {
+				continue
+			}
+			for i, value := range list.Items {
+				var want exprType
+				if i < len(wants) {
+					want = wants[i]
+				}
+				tc.infer(value, want)
+			}
+		}
+		return wants
+	}
+
+	tables, _ := tc.comp.sourceTables(tc.qc, n)
+	tc.push(tables)
+	defer tc.pop()
+	defer func(grouped bool) { tc.grouped = grouped }(tc.grouped)
+	tc.grouped = n.GroupClause != nil && len(n.GroupClause.Items) > 0
+
+	tc.checkFrom(n.FromClause)
+	tc.infer(n.WhereClause, boolType)
+	tc.infer(n.HavingClause, boolType)
+	if n.GroupClause != nil {
+		for _, item := range n.GroupClause.Items {
+			tc.infer(item, exprType{})
+		}
+	}
+	var types []exprType
+	if n.TargetList != nil {
+		for i, item := range n.TargetList.Items {
+			var want exprType
+			if i < len(wants) {
+				want = wants[i]
+			}
+			types = append(types, tc.infer(item, want))
+		}
+	}
+	return types
+}
+
+func (tc *typeChecker) checkWith(with *ast.WithClause) {
+	if with == nil || with.Ctes == nil {
+		return
+	}
+	for _, item := range with.Ctes.Items {
+		if cte, ok := item.(*ast.CommonTableExpr); ok {
+			tc.checkStmt(cte.Ctequery, nil)
+		}
+	}
+}
+
+func (tc *typeChecker) checkFrom(from *ast.List) {
+	if from == nil {
+		return
+	}
+	for _, item := range from.Items {
+		tc.checkFromItem(item)
+	}
+}
+
+func (tc *typeChecker) checkFromItem(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.JoinExpr:
+		tc.checkFromItem(n.Larg)
+		tc.checkFromItem(n.Rarg)
+		tc.infer(n.Quals, boolType)
+	case *ast.RangeSubselect:
+		tc.checkStmt(n.Subquery, nil)
+	case *ast.RangeFunction:
+		if n.Functions == nil {
+			return
+		}
+		for _, item := range n.Functions.Items {
+			if list, ok := item.(*ast.List); ok && len(list.Items) > 0 {
+				item = list.Items[0]
+			}
+			tc.infer(item, exprType{})
+		}
+	}
+}
+
+func (tc *typeChecker) checkTargets(targets *ast.List) {
+	if targets == nil {
+		return
+	}
+	for _, item := range targets.Items {
+		tc.infer(item, exprType{})
+	}
+}
+
+// checkAssignments checks the SET clause of UPDATE and ON CONFLICT DO UPDATE.
+func (tc *typeChecker) checkAssignments(table *Table, targets *ast.List) {
+	if targets == nil {
+		return
+	}
+	for _, item := range targets.Items {
+		res, ok := item.(*ast.ResTarget)
+		if !ok {
+			continue
+		}
+		if _, ok := res.Val.(*ast.MultiAssignRef); ok {
+			continue
+		}
+		var want exprType
+		if table != nil && res.Name != nil {
+			want = tableColumnType(table, *res.Name)
+		}
+		tc.infer(res.Val, want)
+	}
+}
+
+func tableColumnType(table *Table, name string) exprType {
+	for _, c := range table.Columns {
+		if c.Name == name {
+			return columnType(c)
+		}
+	}
+	return exprType{}
+}
+
+func (tc *typeChecker) lookupColumn(ref *ast.ColumnRef) exprType {
+	if hasStarRef(ref) {
+		return exprType{}
+	}
+	parts := stringSlice(ref.Fields)
+	var alias, name string
+	switch len(parts) {
+	case 1:
+		name = parts[0]
+	case 2:
+		alias, name = parts[0], parts[1]
+	case 3:
+		alias, name = parts[1], parts[2]
+	default:
+		return exprType{}
+	}
+	for i := len(tc.scopes) - 1; i >= 0; i-- {
+		var found []*Column
+		for _, t := range tc.scopes[i] {
+			if alias != "" && (t.Rel == nil || t.Rel.Name != alias) {
+				continue
+			}
+			for _, c := range t.Columns {
+				if c.Name == name {
+					found = append(found, c)
+				}
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return columnType(found[0])
+		default:
+			return exprType{}
+		}
+	}
+	return exprType{}
+}
+
+// infer returns the type of node. Parameters in node without a type of
+// their own take the type want.
+func (tc *typeChecker) infer(node ast.Node, want exprType) exprType {
+	switch n := node.(type) {
+
+	case *ast.ParamRef:
+		if t, ok := tc.params[n.Number]; ok {
+			return t
+		}
+		if want.known() {
+			tc.setParam(n.Number, want)
+			return want.value()
+		}
+		return exprType{}
+
+	case *ast.SQLValueFunction:
+		if f, ok := sqlValueFunctions[n.Op]; ok {
+			return builtinType(f[1])
+		}
+		return exprType{}
+
+	case *ast.A_Const:
+		switch v := n.Val.(type) {
+		case *ast.Integer:
+			if v.Ival > math.MaxInt32 || v.Ival < math.MinInt32 {
+				return builtinType("int8")
+			}
+			return builtinType("int4")
+		case *ast.Float:
+			return builtinType("numeric")
+		case *ast.String:
+			t := builtinType("text")
+			t.literal = true
+			return t
+		case *ast.Boolean:
+			return boolType
+		default:
+			return exprType{literal: true}
+		}
+
+	case *ast.ColumnRef:
+		return tc.lookupColumn(n)
+
+	case *ast.ResTarget:
+		return tc.infer(n.Val, want)
+
+	case *ast.NamedArgExpr:
+		return tc.infer(n.Arg, want)
+
+	case *ast.CollateClause:
+		return tc.infer(n.Arg, want)
+
+	case *ast.TypeCast:
+		if n.TypeName == nil {
+			return tc.infer(n.Arg, want)
+		}
+		col := toColumn(n.TypeName)
+		t := exprType{
+			DataType:  col.DataType,
+			IsArray:   col.IsArray,
+			ArrayDims: col.ArrayDims,
+			NotNull:   true,
+		}
+		if arg := tc.infer(n.Arg, t); arg.known() || arg.literal {
+			t.NotNull = arg.NotNull
+		}
+		return t
+
+	case *ast.A_Expr:
+		return tc.inferA_Expr(n, want)
+
+	case *ast.BoolExpr:
+		t := boolType
+		if n.Args != nil {
+			for _, arg := range n.Args.Items {
+				t.NotNull = tc.infer(arg, boolType).NotNull && t.NotNull
+			}
+		}
+		return t
+
+	case *ast.NullTest:
+		tc.infer(n.Arg, exprType{})
+		return boolType
+
+	case *ast.BooleanTest:
+		tc.infer(n.Arg, boolType)
+		return boolType
+
+	case *ast.CaseExpr:
+		return tc.inferCase(n, want)
+
+	case *ast.CoalesceExpr:
+		if n.Args == nil {
+			return exprType{}
+		}
+		// Parameters of COALESCE are expected to be NULL.
+		t := tc.inferCommon(n.Args.Items, want, false)
+		notNull := false
+		for _, arg := range n.Args.Items {
+			notNull = notNull || tc.infer(arg, exprType{}).NotNull
+		}
+		return t.nullable(notNull)
+
+	case *ast.MinMaxExpr:
+		if n.Args == nil {
+			return exprType{}
+		}
+		t := tc.inferCommon(n.Args.Items, want, true)
+		notNull := true
+		for _, arg := range n.Args.Items {
+			notNull = notNull && tc.infer(arg, exprType{}).NotNull
+		}
+		return t.nullable(notNull)
+
+	case *ast.A_ArrayExpr:
+		if n.Elements == nil {
+			return exprType{}
+		}
+		t := tc.inferCommon(n.Elements.Items, want.elem(), true)
+		if !t.known() {
+			return exprType{}
+		}
+		return t.array().nullable(true)
+
+	case *ast.FuncCall:
+		return tc.inferFuncCall(n, want)
+
+	case *ast.SubLink:
+		return tc.inferSubLink(n)
+	}
+	return exprType{}
+}
+
+// inferCommon resolves the common type of nodes. Parameters among them take
+// that type, or want if none of the nodes has a type.
+func (tc *typeChecker) inferCommon(nodes []ast.Node, want exprType, notNull bool) exprType {
+	types := make([]exprType, len(nodes))
+	for i, node := range nodes {
+		types[i] = tc.infer(node, exprType{})
+	}
+	common := commonType(types)
+	if !common.known() {
+		common = want.value()
+	}
+	if !common.known() {
+		return exprType{}
+	}
+	for i, node := range nodes {
+		if !types[i].known() {
+			tc.infer(node, common.nullable(notNull))
+		}
+	}
+	return common
+}
+
+// unify gives the operands of a binary operator the same type when only one
+// of them has one, or fallback when neither has.
+func (tc *typeChecker) unify(ln, rn ast.Node, l, r, fallback exprType) (exprType, exprType) {
+	switch {
+	case !l.known() && r.known():
+		l = tc.infer(ln, r.value())
+	case l.known() && !r.known():
+		r = tc.infer(rn, l.value())
+	case !l.known() && !r.known() && fallback.known():
+		l = tc.infer(ln, fallback)
+		r = tc.infer(rn, fallback)
+	}
+	return l, r
+}
+
+func (tc *typeChecker) inferA_Expr(n *ast.A_Expr, want exprType) exprType {
+	op := astutils.Join(n.Name, "")
+	switch n.Kind {
+
+	case ast.A_Expr_Kind_OP_ANY, ast.A_Expr_Kind_OP_ALL:
+		l := tc.infer(n.Lexpr, exprType{})
+		r := tc.infer(n.Rexpr, exprType{})
+		switch {
+		case !l.known() && r.known() && r.IsArray:
+			l = tc.infer(n.Lexpr, r.elem().value())
+		case l.known() && !r.known():
+			r = tc.infer(n.Rexpr, l.value().array())
+		}
+		return boolType.nullable(l.NotNull && r.NotNull)
+
+	case ast.A_Expr_Kind_IN, ast.A_Expr_Kind_BETWEEN, ast.A_Expr_Kind_NOT_BETWEEN,
+		ast.A_Expr_Kind_BETWEEN_SYM, ast.A_Expr_Kind_NOT_BETWEEN_SYM:
+		nodes := []ast.Node{n.Lexpr}
+		if list, ok := n.Rexpr.(*ast.List); ok {
+			nodes = append(nodes, list.Items...)
+		} else {
+			nodes = append(nodes, n.Rexpr)
+		}
+		tc.inferCommon(nodes, exprType{}, true)
+		notNull := true
+		for _, node := range nodes {
+			notNull = notNull && tc.infer(node, exprType{}).NotNull
+		}
+		return boolType.nullable(notNull)
+
+	case ast.A_Expr_Kind_NULLIF:
+		l := tc.infer(n.Lexpr, exprType{})
+		r := tc.infer(n.Rexpr, exprType{})
+		l, _ = tc.unify(n.Lexpr, n.Rexpr, l, r, want.value())
+		return l.value().nullable(false)
+
+	case ast.A_Expr_Kind_DISTINCT, ast.A_Expr_Kind_NOT_DISTINCT:
+		l := tc.infer(n.Lexpr, exprType{})
+		r := tc.infer(n.Rexpr, exprType{})
+		tc.unify(n.Lexpr, n.Rexpr, l, r, exprType{})
+		return boolType
+
+	case ast.A_Expr_Kind_LIKE, ast.A_Expr_Kind_ILIKE, ast.A_Expr_Kind_SIMILAR:
+		l := tc.infer(n.Lexpr, exprType{})
+		r := tc.infer(n.Rexpr, exprType{})
+		l, r = tc.unify(n.Lexpr, n.Rexpr, l, r, builtinType("text"))
+		return boolType.nullable(l.NotNull && r.NotNull)
+
+	case ast.A_Expr_Kind_OP:
+	default:
+		return exprType{}
+	}
+
+	// Prefix operators
+	if n.Lexpr == nil {
+		switch op {
+		case "+", "-", "@", "~":
+			return tc.infer(n.Rexpr, want).value()
+		}
+		return exprType{}
+	}
+
+	l := tc.infer(n.Lexpr, exprType{})
+	r := tc.infer(n.Rexpr, exprType{})
+	if lang.IsComparisonOperator(op) {
+		l, r = tc.unify(n.Lexpr, n.Rexpr, l, r, exprType{})
+		return boolType.nullable(l.NotNull && r.NotNull)
+	}
+	if t, ok := tc.inferOperator(n, op, l, r); ok {
+		return t
+	}
+
+	switch {
+
+	case op == "||":
+		if l.IsArray || r.IsArray || typeKey(l.DataType) == "jsonb" || typeKey(r.DataType) == "jsonb" {
+			l, r = tc.unify(n.Lexpr, n.Rexpr, l, r, exprType{})
+			t := l
+			if r.IsArray && !l.IsArray {
+				t = r
+			}
+			return t.value().nullable(l.NotNull && r.NotNull)
+		}
+		text := builtinType("text")
+		if !l.known() {
+			l = tc.infer(n.Lexpr, text)
+		}
+		if !r.known() {
+			r = tc.infer(n.Rexpr, text)
+		}
+		return text.nullable(l.NotNull && r.NotNull)
+
+	case op == "->" || op == "->>" || op == "#>" || op == "#>>":
+		key := builtinType("text")
+		if strings.HasPrefix(op, "#") {
+			key = key.array()
+		}
+		if !r.known() {
+			tc.infer(n.Rexpr, key)
+		}
+		if strings.HasSuffix(op, ">>") {
+			return builtinType("text").nullable(false)
+		}
+		return l.value().nullable(false)
+
+	case op == "?":
+		if !r.known() {
+			tc.infer(n.Rexpr, builtinType("text"))
+		}
+		return boolType.nullable(l.NotNull)
+
+	case op == "?|" || op == "?&":
+		if !r.known() {
+			tc.infer(n.Rexpr, builtinType("text").array())
+		}
+		return boolType.nullable(l.NotNull)
+
+	case op == "@>" || op == "<@" || op == "&&":
+		l, r = tc.unify(n.Lexpr, n.Rexpr, l, r, exprType{})
+		return boolType.nullable(l.NotNull && r.NotNull)
+
+	case op == "~" || op == "~*" || op == "!~" || op == "!~*":
+		l, r = tc.unify(n.Lexpr, n.Rexpr, l, r, builtinType("text"))
+		return boolType.nullable(l.NotNull && r.NotNull)
+
+	case op == "+" || op == "-" || op == "*" || op == "/" || op == "%" || op == "^":
+		return tc.inferArithmetic(n, op, l, r, want)
+	}
+	return exprType{}
+}
+
+// inferOperator resolves a binary operator among the operators of the same
+// name in the catalog, the way inferFuncCall resolves functions, so that an
+// operand without a type takes the type of its side of the operator rather
+// than the type of the other operand. At least one operand must have a type.
+func (tc *typeChecker) inferOperator(n *ast.A_Expr, op string, l, r exprType) (exprType, bool) {
+	if !l.known() && !r.known() {
+		return exprType{}, false
+	}
+	ops, err := tc.qc.catalog.ListOperatorsByName(op)
+	if err != nil || len(ops) == 0 {
+		return exprType{}, false
+	}
+	args := []exprType{l, r}
+
+	var best *catalog.Operator
+	var bestParams []exprType
+	var bestBound exprType
+	bestScore := -1
+	for i := range ops {
+		o := &ops[i]
+		if o.Left == nil {
+			continue
+		}
+		params := []exprType{catalogType(o.Left), catalogType(o.Right)}
+		score, bound, ok := matchArgs(args, params)
+		if !ok {
+			continue
+		}
+		// PostgreSQL can't choose between, say, anyrange @> anyrange and
+		// anyrange @> anyelement for an operand without a type. Containment
+		// takes it to be an element, as in `during @> $1`, and the other
+		// operators take it to be of the same type as the other operand.
+		if !l.known() || !r.known() {
+			known, unknown := l, params[1]
+			if !l.known() {
+				known, unknown = r, params[0]
+			}
+			same := sameType(resolvePolymorphic(unknown, bound), known)
+			if same != (op == "@>" || op == "<@") {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestParams, bestBound, bestScore = o, params, bound, score
+		}
+	}
+	if best == nil {
+		return exprType{}, false
+	}
+
+	for i, node := range []ast.Node{n.Lexpr, n.Rexpr} {
+		if args[i].known() {
+			continue
+		}
+		known := args[1-i]
+		param := resolvePolymorphic(bestParams[i], bestBound)
+		if !param.known() || isPolymorphic(param.DataType) {
+			continue
+		}
+		param.name = known.name
+		args[i] = tc.infer(node, param.nullable(known.NotNull))
+	}
+	ret := resolvePolymorphic(catalogType(best.ReturnType), bestBound)
+	if !ret.known() || isPolymorphic(ret.DataType) {
+		return exprType{}, true
+	}
+	return ret.nullable(args[0].NotNull && args[1].NotNull), true
+}
+
+func (tc *typeChecker) inferArithmetic(n *ast.A_Expr, op string, l, r, want exprType) exprType {
+	// An operand without a type takes the type of the other one, except
+	// that date and time values are shifted by intervals.
+	switch {
+	case !l.known() && !r.known():
+		if want.known() {
+			l = tc.infer(n.Lexpr, want.value())
+			r = tc.infer(n.Rexpr, want.value())
+		}
+	case !l.known() || !r.known():
+		known, unknown := r, n.Lexpr
+		if l.known() {
+			known, unknown = l, n.Rexpr
+		}
+		other := known.value()
+		kk := typeKey(known.DataType)
+		switch {
+		case kk == "interval" && (op == "*" || op == "/"):
+			other = builtinType("float8")
+		case kk == "interval" && unknown == n.Lexpr && (op == "+" || op == "-"):
+			other = builtinType("timestamptz")
+			if datetimeRank[typeKey(want.DataType)] > 0 {
+				other = want.value()
+			}
+		case datetimeRank[kk] > 0 && op == "+":
+			other = builtinType("interval")
+		case numericRank[kk] > 0 && numericRank[typeKey(want.DataType)] > numericRank[kk] && !want.IsArray:
+			// Keep the precision of the value the result is assigned to.
+			other = want.value()
+		}
+		t := tc.infer(unknown, other.nullable(known.NotNull))
+		if unknown == n.Lexpr {
+			l = t
+		} else {
+			r = t
+		}
+	}
+
+	t := arithmeticType(op, l, r)
+	if !t.known() {
+		return exprType{}
+	}
+	return t.nullable(l.NotNull && r.NotNull)
+}
+
+func arithmeticType(op string, l, r exprType) exprType {
+	if l.IsArray || r.IsArray {
+		return exprType{}
+	}
+	lk, rk := typeKey(l.DataType), typeKey(r.DataType)
+	switch {
+
+	case numericRank[lk] > 0 && numericRank[rk] > 0:
+		t := l
+		if numericRank[rk] > numericRank[lk] {
+			t = r
+		}
+		if op == "^" && numericRank[typeKey(t.DataType)] < numericRank["numeric"] {
+			return builtinType("float8")
+		}
+		return t.value()
+
+	case lk == "interval" && rk == "interval" && (op == "+" || op == "-"):
+		return l.value()
+
+	case lk == "interval" && numericRank[rk] > 0 && (op == "*" || op == "/"):
+		return l.value()
+
+	case numericRank[lk] > 0 && rk == "interval" && op == "*":
+		return r.value()
+
+	case lk == "date" && rk == "date" && op == "-":
+		return builtinType("int4")
+
+	case lk == "date" && numericRank[rk] > 0 && numericRank[rk] <= numericRank["int4"] && (op == "+" || op == "-"):
+		return l.value()
+
+	case numericRank[lk] > 0 && numericRank[lk] <= numericRank["int4"] && rk == "date" && op == "+":
+		return r.value()
+
+	case lk == "date" && rk == "interval" && (op == "+" || op == "-"):
+		return builtinType("timestamp")
+
+	case lk == "interval" && rk == "date" && op == "+":
+		return builtinType("timestamp")
+
+	case (datetimeRank[lk] > 0 || lk == "time") && rk == "interval" && (op == "+" || op == "-"):
+		return l.value()
+
+	case lk == "interval" && (datetimeRank[rk] > 0 || rk == "time") && op == "+":
+		return r.value()
+
+	case (datetimeRank[lk] > 0 || lk == "time") && lk == rk && op == "-":
+		return builtinType("interval")
+	}
+	return exprType{}
+}
+
+func (tc *typeChecker) inferCase(n *ast.CaseExpr, want exprType) exprType {
+	arg := tc.infer(n.Arg, exprType{})
+	var results []ast.Node
+	if n.Args != nil {
+		for _, item := range n.Args.Items {
+			when, ok := item.(*ast.CaseWhen)
+			if !ok {
+				continue
+			}
+			if n.Arg == nil {
+				tc.infer(when.Expr, boolType)
+			} else {
+				expr := tc.infer(when.Expr, exprType{})
+				arg, _ = tc.unify(n.Arg, when.Expr, arg, expr, exprType{})
+			}
+			results = append(results, when.Result)
+		}
+	}
+	if n.Defresult != nil {
+		results = append(results, n.Defresult)
+	}
+	t := tc.inferCommon(results, want, true)
+	notNull := n.Defresult != nil
+	for _, result := range results {
+		notNull = notNull && tc.infer(result, exprType{}).NotNull
+	}
+	return t.nullable(notNull)
+}
+
+func (tc *typeChecker) inferSubLink(n *ast.SubLink) exprType {
+	sel, ok := n.Subselect.(*ast.SelectStmt)
+	if !ok {
+		return exprType{}
+	}
+	types := tc.checkSelect(sel, nil)
+	var first exprType
+	if len(types) > 0 {
+		first = types[0]
+	}
+	switch n.SubLinkType {
+	case ast.EXISTS_SUBLINK:
+		return boolType
+	case ast.ANY_SUBLINK, ast.ALL_SUBLINK:
+		test := tc.infer(n.Testexpr, exprType{})
+		if !test.known() && first.known() {
+			tc.infer(n.Testexpr, first.value())
+		}
+		return boolType
+	case ast.EXPR_SUBLINK:
+		return first.value().nullable(false)
+	case ast.ARRAY_SUBLINK:
+		if !first.known() {
+			return exprType{}
+		}
+		return first.value().array()
+	}
+	return exprType{}
+}
+
+// inferFuncCall resolves the function called by n among the functions of the
+// same name, preferring exact matches over implicit casts and the function
+// returning want on ties. Arguments without a type take the type of the
+// matching parameter.
+func (tc *typeChecker) inferFuncCall(n *ast.FuncCall, want exprType) exprType {
+	var args []ast.Node
+	var named bool
+	if n.Args != nil {
+		for _, arg := range n.Args.Items {
+			if _, ok := arg.(*ast.NamedArgExpr); ok {
+				named = true
+			}
+			args = append(args, arg)
+		}
+	}
+	types := make([]exprType, len(args))
+	for i, arg := range args {
+		types[i] = tc.infer(arg, exprType{})
+	}
+
+	if named {
+		fun, err := tc.qc.catalog.ResolveFuncCall(n)
+		if err != nil {
+			return exprType{}
+		}
+		return catalogType(fun.ReturnType).nullable(!fun.ReturnTypeNullable)
+	}
+	funs, err := tc.qc.catalog.ListFuncsByName(n.Func)
+	if err != nil || len(funs) == 0 {
+		return exprType{}
+	}
+
+	var best *catalog.Function
+	var bestParams []exprType
+	var bestBound exprType
+	bestScore := -1
+	for i := range funs {
+		fun := &funs[i]
+		params, ok := funcParams(fun, len(args))
+		if !ok {
+			continue
+		}
+		score, bound, ok := matchArgs(types, params)
+		if !ok {
+			continue
+		}
+		ret := resolvePolymorphic(catalogType(fun.ReturnType), bound)
+		if want.known() && ret.known() && sameType(ret, want) {
+			score++
+		}
+		if score > bestScore {
+			best, bestParams, bestBound, bestScore = fun, params, bound, score
+		}
+	}
+	if best == nil {
+		return exprType{}
+	}
+
+	for i, arg := range args {
+		if types[i].known() {
+			continue
+		}
+		param := resolvePolymorphic(bestParams[i], bestBound)
+		if !param.known() || isPolymorphic(param.DataType) {
+			continue
+		}
+		param.name = bestParams[i].name
+		if param.name == "" {
+			param.name = best.Name
+		}
+		tc.infer(arg, param)
+	}
+	ret := resolvePolymorphic(catalogType(best.ReturnType), bestBound)
+	if !ret.known() || isPolymorphic(ret.DataType) {
+		return exprType{}
+	}
+	ret = ret.nullable(!best.ReturnTypeNullable)
+	// An aggregate over a group is only NULL for NULL inputs
+	if tc.grouped && n.Over == nil && !hasFilter(n) {
+		ret.NotNull = true
+	}
+	for _, t := range types {
+		if ret.NotNull && (t.known() || t.literal) && !t.NotNull && tc.strictFunc(best) {
+			ret.NotNull = false
+		}
+	}
+	return ret
+}
+
+func hasFilter(n *ast.FuncCall) bool {
+	_, ok := n.AggFilter.(*ast.TODO)
+	return n.AggFilter != nil && !ok
+}
+
+// nonStrictFuncs are the pg_catalog functions that don't return NULL for
+// NULL arguments.
+var nonStrictFuncs = map[string]bool{
+	"array_append":       true,
+	"array_cat":          true,
+	"array_prepend":      true,
+	"concat":             true,
+	"concat_ws":          true,
+	"count":              true,
+	"format":             true,
+	"json_build_array":   true,
+	"json_build_object":  true,
+	"jsonb_build_array":  true,
+	"jsonb_build_object": true,
+	"num_nonnulls":       true,
+	"num_nulls":          true,
+	"regr_count":         true,
+	"to_json":            true,
+	"to_jsonb":           true,
+}
+
+// strictFunc reports whether fun is a pg_catalog function that returns NULL
+// when any of its arguments is NULL, as most built-in functions do. Functions
+// created in the schema are not strict unless declared so.
+func (tc *typeChecker) strictFunc(fun *catalog.Function) bool {
+	if nonStrictFuncs[fun.Name] {
+		return false
+	}
+	for _, schema := range tc.qc.catalog.Schemas {
+		if schema.Name != "pg_catalog" {
+			continue
+		}
+		for _, f := range schema.Funcs {
+			if f.Name == fun.Name && f.ReturnType == fun.ReturnType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// funcParams returns the types of the parameters of fun for a call with
+// count positional arguments.
+func funcParams(fun *catalog.Function, count int) ([]exprType, bool) {
+	in := fun.InArgs()
+	var required int
+	var variadic bool
+	for _, arg := range in {
+		if arg.Mode == ast.FuncParamVariadic {
+			variadic = true
+			continue
+		}
+		if !arg.HasDefault {
+			required++
+		}
+	}
+	if count < required || (!variadic && count > len(in)) {
+		return nil, false
+	}
+	params := make([]exprType, count)
+	for i := range params {
+		var arg *catalog.Argument
+		switch {
+		case i < len(in) && in[i].Mode != ast.FuncParamVariadic:
+			arg = in[i]
+		case variadic:
+			arg = in[len(in)-1]
+		default:
+			return nil, false
+		}
+		t := catalogType(arg.Type)
+		if arg.Mode == ast.FuncParamVariadic && t.IsArray {
+			t = t.elem()
+		}
+		t.name = arg.Name
+		params[i] = t
+	}
+	return params, true
+}
+
+// matchArgs scores how well args match params. Polymorphic parameters are
+// bound to the type of the first argument passed to them.
+func matchArgs(args, params []exprType) (int, exprType, bool) {
+	var score int
+	var bound exprType
+	for i, arg := range args {
+		param := params[i]
+		if !arg.known() || !param.known() {
+			continue
+		}
+		if isPolymorphic(param.DataType) {
+			elem := arg
+			switch typeKey(param.DataType) {
+			case "anyarray", "anycompatiblearray":
+				if !arg.IsArray {
+					return 0, bound, false
+				}
+				elem = arg.elem()
+			case "anyrange", "anycompatiblerange":
+				key, ok := rangeElems[typeKey(arg.DataType)]
+				if !ok || arg.IsArray {
+					return 0, bound, false
+				}
+				elem = builtinType(key)
+			case "anymultirange", "anycompatiblemultirange":
+				key, ok := multirangeElems[typeKey(arg.DataType)]
+				if !ok || arg.IsArray {
+					return 0, bound, false
+				}
+				elem = builtinType(key)
+			}
+			if typeKey(param.DataType) != "any" && !arg.literal && !bound.known() {
+				bound = elem.value()
+			}
+			score++
+			continue
+		}
+		switch {
+		case arg.literal:
+			if param.IsArray == arg.IsArray && textTypes[typeKey(param.DataType)] {
+				score++
+			}
+		case sameType(arg, param):
+			score += 3
+		case implicitCast(arg, param):
+			score += 2
+		default:
+			return 0, bound, false
+		}
+	}
+	return score, bound, true
+}
+
+func resolvePolymorphic(t, bound exprType) exprType {
+	if !bound.known() {
+		return t
+	}
+	switch typeKey(t.DataType) {
+	case "anyelement", "anycompatible", "anynonarray", "anyenum", "anycompatiblenonarray":
+		return bound.nullable(t.NotNull)
+	case "anyarray", "anycompatiblearray":
+		return bound.array().nullable(t.NotNull)
+	case "anyrange", "anycompatiblerange":
+		if r := rangeOf(rangeElems, bound); r.known() {
+			return r.nullable(t.NotNull)
+		}
+	case "anymultirange", "anycompatiblemultirange":
+		if r := rangeOf(multirangeElems, bound); r.known() {
+			return r.nullable(t.NotNull)
+		}
+	}
+	return t
+}
@@ -0,0 +1,249 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+)
+
+const typecheckSchema = `CREATE TABLE books (
+   id         BIGINT       NOT NULL,
+   name       VARCHAR(255) NOT NULL,
+   price      NUMERIC      NOT NULL,
+   qty        INTEGER      NOT NULL,
+   weight     DOUBLE PRECISION,
+   note       TEXT,
+   tags       TEXT[]       NOT NULL,
+   active     BOOLEAN      NOT NULL,
+   created_at TIMESTAMPTZ  NOT NULL,
+   CONSTRAINT books_id_pkey PRIMARY KEY (id)
+);
+`
+
+const typecheckSlotsSchema = `CREATE TABLE slots (
+   id     BIGINT    NOT NULL,
+   during TSTZRANGE NOT NULL,
+   area   BOX       NOT NULL,
+   tsv    TSVECTOR  NOT NULL,
+   doc    JSONB
+);
+`
+
+func describe(cols []*Column) string {
+	var parts []string
+	for _, c := range cols {
+		s := c.Name + ":" + typeKey(c.DataType)
+		if c.IsArray {
+			s += "[]"
+		}
+		if !c.NotNull {
+			s += "?"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestTypeCheck(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schema, []byte(typecheckSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	slots := filepath.Join(dir, "slots.sql")
+	if err := os.WriteFile(slots, []byte(typecheckSlotsSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if err := c.ParseCatalog([]string{schema, slots}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		query   string
+		params  string
+		columns string
+	}{
+		{
+			query:  "SELECT id FROM books WHERE CASE WHEN active THEN qty ELSE $1 END > 3",
+			params: "qty:int4",
+		},
+		{
+			query:  "SELECT id FROM books WHERE qty = -$1",
+			params: "qty:int4",
+		},
+		{
+			query:  "SELECT id FROM books WHERE created_at > $1 + interval '1 day'",
+			params: ":timestamptz",
+		},
+		{
+			query:  "SELECT id FROM books WHERE id = ANY($1)",
+			params: "id:int8[]",
+		},
+		{
+			query:  "SELECT id FROM books WHERE $1 = ANY(tags)",
+			params: "tags:text",
+		},
+		{
+			query:  "UPDATE books SET tags = array_append(tags, $1) WHERE id = $2",
+			params: "array_append:text,id:int8",
+		},
+		{
+			query:   "SELECT COALESCE(note, $1) AS n FROM books",
+			params:  "note:text?",
+			columns: "n:text?",
+		},
+		{
+			query:   "SELECT CASE WHEN qty > $1 THEN $2 ELSE name END AS label FROM books",
+			params:  "qty:int4,name:varchar",
+			columns: "label:varchar",
+		},
+		{
+			query:   "SELECT price * qty AS total, weight / 2 AS w, NULLIF(qty, 0) AS q FROM books",
+			columns: "total:numeric,w:float8?,q:int4?",
+		},
+		{
+			query:   "SELECT GREATEST(qty, 10) AS g, LEAST(price, $1) AS l, max(created_at) AS latest FROM books",
+			params:  "price:numeric",
+			columns: "g:int4,l:numeric,latest:timestamptz?",
+		},
+		{
+			query:  "INSERT INTO books (id, name, price, qty, created_at, tags, active) VALUES ($1, $2, $3 * 2, $4, now(), $5, true)",
+			params: "id:int8,name:varchar,price:numeric,qty:int4,tags:text[]",
+		},
+		{
+			query:  "UPDATE books SET price = t.price FROM unnest($1::bigint[], $2::numeric[]) AS t(id, price) WHERE books.id = t.id AND t.price > $3",
+			params: ":int8[],:numeric[],price:numeric?",
+		},
+		{
+			query:   "SELECT * FROM unnest($1::bigint[], $2::int[]) WITH ORDINALITY AS t(id, qty, n)",
+			params:  ":int8[],:int4[]",
+			columns: "id:int8?,qty:int4?,n:int8",
+		},
+		{
+			query:   "SELECT * FROM ROWS FROM (unnest($1::int[]), generate_series(1, 3)) AS u(x, y)",
+			params:  ":int4[]",
+			columns: "x:int4?,y:int4?",
+		},
+		{
+			query:   "SELECT * FROM unnest($1::text[]) WITH ORDINALITY AS u(tag, n)",
+			params:  ":text[]",
+			columns: "tag:text,n:int8",
+		},
+		{
+			query:   "SELECT g, x.a FROM generate_series(1, $1) AS g, jsonb_to_recordset($2) AS x(a int, b text)",
+			params:  "generate_series:int4,jsonb_to_recordset:jsonb",
+			columns: "g:int4,a:int4?",
+		},
+		{
+			query:   "SELECT books.id, tag FROM books, unnest(books.tags) AS tag",
+			columns: "id:int8,tag:text",
+		},
+		{
+			query:   "SELECT count(*) AS n, COALESCE(sum(qty), 0) AS s, (SELECT count(*) FROM books) AS c, (SELECT id FROM books LIMIT 1) AS first FROM books",
+			columns: "n:int8,s:int8,c:int8,first:int8?",
+		},
+		{
+			query:   "SELECT a.id, b.id AS bid, upper(b.name) AS bname FROM books a LEFT JOIN books b ON b.id = a.id WHERE a.note IS NOT NULL",
+			columns: "id:int8,bid:int8?,bname:text?",
+		},
+		{
+			query:   "SELECT note, upper(note) AS up FROM books WHERE note IS NOT NULL",
+			columns: "note:text,up:text",
+		},
+		{
+			query:   "SELECT qty, max(price) AS p, max(note) AS n, sum(price) FILTER (WHERE active) AS s FROM books GROUP BY qty",
+			columns: "qty:int4,p:numeric,n:text?,s:numeric?",
+		},
+		{
+			query:  "SELECT id FROM slots WHERE during @> $1 AND $2 <@ during AND during && $3",
+			params: "during:timestamptz,during:timestamptz,during:tstzrange",
+		},
+		{
+			query:  "SELECT id FROM slots WHERE area @> $1 AND tsv @@ $2 AND doc ? $3",
+			params: "area:point,tsv:tsquery,doc:text?",
+		},
+		{
+			query:  "SELECT id FROM books WHERE tags && $1",
+			params: "tags:text[]",
+		},
+		{
+			query:   "SELECT during -|- $1 AS adj, area <-> point(0, 0) AS dist, upper(during) AS ends FROM slots",
+			params:  "during:tstzrange",
+			columns: "adj:bool,dist:float8,ends:timestamptz",
+		},
+	} {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			src := fmt.Sprintf("-- name: Test :exec\n%s;\n", tc.query)
+			queries, err := c.ParseQuerySource("query.sql", src, opts.Parser{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var params []*Column
+			for _, p := range queries[0].Params {
+				params = append(params, p.Column)
+			}
+			if got := describe(params); got != tc.params {
+				t.Errorf("params: want %s, got %s", tc.params, got)
+			}
+			if tc.columns == "" {
+				return
+			}
+			if got := describe(queries[0].Columns); got != tc.columns {
+				t.Errorf("columns: want %s, got %s", tc.columns, got)
+			}
+		})
+	}
+}
+
+func TestEmbedOuterJoin(t *testing.T) {
+	schema := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(schema, []byte(typecheckSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if err := c.ParseCatalog([]string{schema}); err != nil {
+		t.Fatal(err)
+	}
+	parse := func(query string) error {
+		t.Helper()
+		_, err := c.ParseQuerySource("query.sql", "-- name: Test :many\n"+query+";\n", opts.Parser{})
+		return err
+	}
+
+	for _, query := range []string{
+		"SELECT a.id, sqlc.embed(p) FROM books a JOIN books p ON p.id = a.id",
+		"SELECT sqlc.embed(a), p.id FROM books a LEFT JOIN books p ON p.id = a.id",
+	} {
+		if err := parse(query); err != nil {
+			t.Errorf("%s: %s", query, err)
+		}
+	}
+	for _, query := range []string{
+		"SELECT a.id, sqlc.embed(p) FROM books a LEFT JOIN books p ON p.id = a.id",
+		"SELECT sqlc.embed(a), p.id FROM books a RIGHT JOIN books p ON p.id = a.id",
+		"SELECT a.id, sqlc.embed(p) FROM books a FULL JOIN books p ON p.id = a.id",
+	} {
+		err := parse(query)
+		var merr *multierr.Error
+		if !errors.As(err, &merr) || len(merr.Errs()) != 1 {
+			t.Errorf("%s: expected an error, got %v", query, err)
+			continue
+		}
+		ferr := merr.Errs()[0]
+		if !strings.Contains(ferr.Err.Error(), "nullable side of an outer join") {
+			t.Errorf("%s: unexpected error %v", query, ferr.Err)
+		}
+		if col := strings.Index(query, "sqlc.embed") + 1; ferr.Line != 2 || ferr.Column != col {
+			t.Errorf("%s: error at %d:%d, want the sqlc.embed call at 2:%d", query, ferr.Line, ferr.Column, col)
+		}
+	}
+}
@@ -62,6 +62,11 @@ type Config struct {
 	Gen     Gen      `json:"overrides,omitempty" yaml:"overrides"`
 	Plugins []Plugin `json:"plugins" yaml:"plugins"`
 	Rules   []Rule   `json:"rules" yaml:"rules"`
+	// Include lists globs of fragments with more packages, relative to the
+	// configuration. ParseConfigFile reads them.
+	Include []string `json:"include" yaml:"include"`
+	// Fragments are the paths of the fragments read by ParseConfigFile.
+	Fragments []string `json:"-" yaml:"-"`
 }
 
 type Project struct {
@@ -82,7 +87,8 @@ type Plugin struct {
 	Name    string   `json:"name" yaml:"name"`
 	Env     []string `json:"env" yaml:"env"`
 	Process *struct {
-		Cmd string `json:"cmd" yaml:"cmd"`
+		Cmd      string `json:"cmd" yaml:"cmd"`
+		Protocol string `json:"protocol" yaml:"protocol"`
 	} `json:"process" yaml:"process"`
 	WASM *struct {
 		URL    string `json:"url" yaml:"url"`
@@ -91,9 +97,10 @@ type Plugin struct {
 }
 
 type Rule struct {
-	Name string `json:"name" yaml:"name"`
-	Rule string `json:"rule" yaml:"rule"`
-	Msg  string `json:"message" yaml:"message"`
+	Name   string `json:"name" yaml:"name"`
+	Rule   string `json:"rule" yaml:"rule"`
+	Msg    string `json:"message" yaml:"message"`
+	Plugin string `json:"plugin" yaml:"plugin"`
 }
 
 type Gen struct {
@@ -115,6 +122,27 @@ type SQL struct {
 	Gen                  SQLGen    `json:"gen" yaml:"gen"`
 	Codegen              []Codegen `json:"codegen" yaml:"codegen"`
 	Rules                []string  `json:"rules" yaml:"rules"`
+	CRUD                 CRUD      `json:"crud" yaml:"crud"`
+
+	// file is the fragment the package is defined in, relative to the
+	// configuration; empty for the packages of the configuration itself.
+	file string
+}
+
+// where returns the fragment of the package, for error messages.
+func (s SQL) where() string {
+	if s.file == "" {
+		return ""
+	}
+	return " (in " + s.file + ")"
+}
+
+// source returns the file the package is defined in, for error messages.
+func (s SQL) source() string {
+	if s.file == "" {
+		return "the configuration"
+	}
+	return s.file
 }
 
 // TODO: Figure out a better name for this
@@ -144,6 +172,7 @@ type SQLGo struct {
 	EmitPointersForNullTypes    bool              `json:"emit_pointers_for_null_types" yaml:"emit_pointers_for_null_types"`
 	EmitEnumValidMethod         bool              `json:"emit_enum_valid_method,omitempty" yaml:"emit_enum_valid_method"`
 	EmitAllEnumValues           bool              `json:"emit_all_enum_values,omitempty" yaml:"emit_all_enum_values"`
+	EmitIdempotentSchema        bool              `json:"emit_idempotent_schema,omitempty" yaml:"emit_idempotent_schema"`
 	JSONTagsCaseStyle           string            `json:"json_tags_case_style,omitempty" yaml:"json_tags_case_style"`
 	Package                     string            `json:"package" yaml:"package"`
 	Out                         string            `json:"out" yaml:"out"`
@@ -160,6 +189,7 @@ type SQLGo struct {
 	InflectionExcludeTableNames []string          `json:"inflection_exclude_table_names,omitempty" yaml:"inflection_exclude_table_names"`
 	QueryParameterLimit         *int32            `json:"query_parameter_limit,omitempty" yaml:"query_parameter_limit"`
 	OmitUnusedStructs           bool              `json:"omit_unused_structs,omitempty" yaml:"omit_unused_structs"`
+	NumericType                 string            `json:"numeric_type,omitempty" yaml:"numeric_type"`
 }
 
 type SQLJSON struct {
@@ -178,6 +208,7 @@ var ErrNoQuerierType = errors.New("no querier emit type enabled")
 var ErrUnknownEngine = errors.New("invalid engine")
 var ErrUnknownVersion = errors.New("invalid version number")
 var ErrInvalidQueryParameterLimit = errors.New("invalid query parameter limit")
+var ErrInvalidNumericType = errors.New("invalid numeric type: must be `pgtype`, `string` or `shopspring`")
 
 var ErrPluginBuiltin = errors.New("a built-in plugin with that name already exists")
 var ErrPluginNoName = errors.New("missing plugin name")
@@ -186,6 +217,7 @@ var ErrPluginNotFound = errors.New("no plugin found")
 var ErrPluginNoType = errors.New("plugin: field `process` or `wasm` required")
 var ErrPluginBothTypes = errors.New("plugin: both `process` and `wasm` cannot both be defined")
 var ErrPluginProcessNoCmd = errors.New("plugin: missing process command")
+var ErrPluginProcessProtocol = errors.New("plugin: process protocol must be `stdio` or `grpc`")
 
 func ParseConfig(rd io.Reader) (Config, error) {
 	var buf bytes.Buffer
@@ -23,6 +23,14 @@ const unknownFields = `{
   "foo": "bar"
 }`
 
+const invalidNumericType = `{
+  "version": "2",
+  "sql": [{
+    "engine": "postgresql",
+    "gen": {"go": {"out": "db", "numeric_type": "float"}}
+  }]
+}`
+
 func TestBadConfigs(t *testing.T) {
 	for _, test := range []struct {
 		name string
@@ -50,6 +58,11 @@ func TestBadConfigs(t *testing.T) {
   line 3: field foo not found in type config.V1GenerateSettings`,
 			unknownFields,
 		},
+		{
+			"invalid numeric type",
+			"invalid numeric type: must be `pgtype`, `string` or `shopspring`",
+			invalidNumericType,
+		},
 	} {
 		tt := test
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,47 @@
+package config
+
+import "encoding/json"
+
+// CRUD configures the queries sqlc generates for the table of a package. It
+// is either a boolean or a mapping, which enables it.
+type CRUD struct {
+	Enabled bool `json:"-" yaml:"-"`
+	// Include lists the queries to generate, all of them if empty.
+	Include []string `json:"include" yaml:"include"`
+	// Exclude lists the queries not to generate.
+	Exclude []string `json:"exclude" yaml:"exclude"`
+	// Cache is the cache duration of GetByID; it is not cached if empty.
+	Cache string `json:"cache" yaml:"cache"`
+}
+
+func (c *CRUD) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		*c = CRUD{Enabled: enabled}
+		return nil
+	}
+	type alias CRUD
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*c = CRUD(a)
+	c.Enabled = true
+	return nil
+}
+
+func (c *CRUD) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		*c = CRUD{Enabled: enabled}
+		return nil
+	}
+	type alias CRUD
+	var a alias
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	*c = CRUD(a)
+	c.Enabled = true
+	return nil
+}
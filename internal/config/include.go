@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// fragment is a file listed by the include globs of a configuration. It only
+// holds packages.
+type fragment struct {
+	Version string `yaml:"version"`
+	SQL     []SQL  `yaml:"sql"`
+}
+
+// ParseConfigFile parses the configuration at path, and appends the packages
+// of the fragments its include globs match, in the order of the globs and
+// then of the file names. Relative paths of a fragment are resolved from its
+// directory, and made relative to the directory of path like the paths of
+// the configuration itself.
+func ParseConfigFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	conf, err := ParseConfig(f)
+	if err != nil {
+		return conf, err
+	}
+	if len(conf.Include) == 0 {
+		return conf, nil
+	}
+
+	dir := filepath.Dir(path)
+	plugins := map[string]struct{}{}
+	for _, p := range conf.Plugins {
+		plugins[p.Name] = struct{}{}
+	}
+	seen := map[string]bool{}
+	for _, pattern := range conf.Include {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return conf, fmt.Errorf("include %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return conf, fmt.Errorf("include %q: no files match", pattern)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			seen[match] = true
+			conf.Fragments = append(conf.Fragments, match)
+			rel, err := filepath.Rel(dir, match)
+			if err != nil {
+				rel = match
+			}
+			pkgs, err := parseFragment(match, filepath.Dir(rel), plugins)
+			if err != nil {
+				return conf, fmt.Errorf("%s: %w", rel, err)
+			}
+			for i := range pkgs {
+				pkgs[i].file = rel
+			}
+			conf.SQL = append(conf.SQL, pkgs...)
+		}
+	}
+	if len(conf.SQL) == 0 {
+		return conf, ErrNoPackages
+	}
+	if err := conf.validateGlobalOverrides(); err != nil {
+		return conf, err
+	}
+	return conf, nil
+}
+
+// parseFragment parses the packages of the fragment at path, whose directory
+// is dir relative to the configuration.
+func parseFragment(path, dir string, plugins map[string]struct{}) ([]SQL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	var frag fragment
+	if err := dec.Decode(&frag); err != nil {
+		return nil, err
+	}
+	if frag.Version != "" && frag.Version != "2" {
+		return nil, ErrUnknownVersion
+	}
+	if len(frag.SQL) == 0 {
+		return nil, ErrNoPackages
+	}
+	for i := range frag.SQL {
+		sql := &frag.SQL[i]
+		sql.Schema = rebase(dir, sql.Schema)
+		sql.Queries = rebase(dir, sql.Queries)
+		if sql.Gen.Go != nil && sql.Gen.Go.Out != "" {
+			sql.Gen.Go.Out = rebase(dir, []string{sql.Gen.Go.Out})[0]
+		}
+		if sql.Gen.JSON != nil && sql.Gen.JSON.Out != "" {
+			sql.Gen.JSON.Out = rebase(dir, []string{sql.Gen.JSON.Out})[0]
+		}
+		for j := range sql.Codegen {
+			if sql.Codegen[j].Out != "" {
+				sql.Codegen[j].Out = rebase(dir, []string{sql.Codegen[j].Out})[0]
+			}
+		}
+		if err := v2ParseSQL(sql, plugins); err != nil {
+			return nil, err
+		}
+	}
+	return frag.SQL, nil
+}
+
+// rebase joins the relative paths to dir.
+func rebase(dir string, paths []string) []string {
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		if filepath.IsAbs(p) {
+			out[i] = p
+		} else {
+			out[i] = filepath.Join(dir, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"sqlc.yaml": `version: '2'
+include:
+  - '*/sqlc.part.yaml'
+sql:
+  - schema: shared/schema.sql
+    queries: shared/query.sql
+    engine: postgresql
+    gen:
+      go:
+        package: shared
+        out: shared
+`,
+		"books/sqlc.part.yaml": `sql:
+  - schema:
+      - schema.sql
+      - ../authors/schema.sql
+    queries: query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        out: .
+`,
+		"authors/sqlc.part.yaml": `version: '2'
+sql:
+  - schema: schema.sql
+    queries: queries/
+    engine: postgresql
+    gen:
+      go:
+        package: authors
+        out: gen
+`,
+	})
+	conf, err := ParseConfigFile(filepath.Join(dir, "sqlc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type pkg struct {
+		Package, Out, File string
+		Schema, Queries    []string
+	}
+	var got []pkg
+	for _, sql := range conf.SQL {
+		got = append(got, pkg{sql.Gen.Go.Package, sql.Gen.Go.Out, sql.file, sql.Schema, sql.Queries})
+	}
+	want := []pkg{
+		{"shared", "shared", "", []string{"shared/schema.sql"}, []string{"shared/query.sql"}},
+		{"authors", "authors/gen", "authors/sqlc.part.yaml", []string{"authors/schema.sql"}, []string{"authors/queries"}},
+		{"books", "books", "books/sqlc.part.yaml", []string{"books/schema.sql", "authors/schema.sql"}, []string{"books/query.sql"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("packages mismatch (-want +got):\n%s", diff)
+	}
+	if err := Validate(&conf); err != nil {
+		t.Errorf("validate: %s", err)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"shared/sqlc.part.yaml": `sql:
+  - schema: schema.sql
+    queries: query.sql
+    engine: postgresql
+    gen:
+      go:
+        package: books
+        out: .
+`,
+	})
+	conf, err = ParseConfigFile(filepath.Join(dir, "sqlc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Validate(&conf)
+	if want := "duplicated package name is not allowed: books (in books/sqlc.part.yaml and shared/sqlc.part.yaml)"; err == nil || err.Error() != want {
+		t.Errorf("duplicate package: got %v, want %s", err, want)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"shared/sqlc.part.yaml": `sql:
+  - schema: schema.sql
+    queries: query.sql
+    gen:
+      go:
+        out: .
+`,
+	})
+	_, err = ParseConfigFile(filepath.Join(dir, "sqlc.yaml"))
+	if want := "shared/sqlc.part.yaml: unknown engine"; err == nil || err.Error() != want {
+		t.Errorf("invalid fragment: got %v, want %s", err, want)
+	}
+}
@@ -21,7 +21,7 @@ func v2ParseConfig(rd io.Reader) (Config, error) {
 	if conf.Version != "2" {
 		return conf, ErrUnknownVersion
 	}
-	if len(conf.SQL) == 0 {
+	if len(conf.SQL) == 0 && len(conf.Include) == 0 {
 		return conf, ErrNoPackages
 	}
 	if err := conf.validateGlobalOverrides(); err != nil {
@@ -60,62 +60,82 @@ func v2ParseConfig(rd io.Reader) (Config, error) {
 			if conf.Plugins[i].Process.Cmd == "" {
 				return conf, ErrPluginProcessNoCmd
 			}
+			switch conf.Plugins[i].Process.Protocol {
+			case "", "stdio", "grpc":
+			default:
+				return conf, ErrPluginProcessProtocol
+			}
 		}
 		plugins[conf.Plugins[i].Name] = struct{}{}
 	}
 	for j := range conf.SQL {
-		if conf.SQL[j].Engine == "" {
-			return conf, ErrMissingEngine
+		if err := v2ParseSQL(&conf.SQL[j], plugins); err != nil {
+			return conf, err
 		}
-		if conf.SQL[j].Gen.Go != nil {
-			if conf.SQL[j].Gen.Go.Out == "" {
-				return conf, ErrNoPackagePath
-			}
-			if conf.SQL[j].Gen.Go.Package == "" {
-				conf.SQL[j].Gen.Go.Package = filepath.Base(conf.SQL[j].Gen.Go.Out)
-			}
+	}
+	return conf, nil
+}
 
-			if conf.SQL[j].Gen.Go.QueryParameterLimit != nil && (*conf.SQL[j].Gen.Go.QueryParameterLimit < 0) {
-				return conf, ErrInvalidQueryParameterLimit
-			}
+// v2ParseSQL checks a package and sets its defaults. plugins holds the names
+// of the plugins of the configuration.
+func v2ParseSQL(sql *SQL, plugins map[string]struct{}) error {
+	if sql.Engine == "" {
+		return ErrMissingEngine
+	}
+	if sql.Gen.Go != nil {
+		if sql.Gen.Go.Out == "" {
+			return ErrNoPackagePath
+		}
+		if sql.Gen.Go.Package == "" {
+			sql.Gen.Go.Package = filepath.Base(sql.Gen.Go.Out)
+		}
 
-			if conf.SQL[j].Gen.Go.QueryParameterLimit == nil {
-				conf.SQL[j].Gen.Go.QueryParameterLimit = new(int32)
-				*conf.SQL[j].Gen.Go.QueryParameterLimit = 1
-			}
+		if sql.Gen.Go.QueryParameterLimit != nil && (*sql.Gen.Go.QueryParameterLimit < 0) {
+			return ErrInvalidQueryParameterLimit
+		}
 
-			for i := range conf.SQL[j].Gen.Go.Overrides {
-				if err := conf.SQL[j].Gen.Go.Overrides[i].Parse(); err != nil {
-					return conf, err
-				}
-			}
-			for k, v := range conf.SQL[j].Gen.Go.Rename {
-				conf.SQL[j].Gen.Go.Rename[k] = v
-			}
+		switch sql.Gen.Go.NumericType {
+		case "", "pgtype", "string", "shopspring":
+		default:
+			return ErrInvalidNumericType
 		}
-		if conf.SQL[j].Gen.JSON != nil {
-			if conf.SQL[j].Gen.JSON.Out == "" {
-				return conf, ErrNoOutPath
-			}
+
+		if sql.Gen.Go.QueryParameterLimit == nil {
+			sql.Gen.Go.QueryParameterLimit = new(int32)
+			*sql.Gen.Go.QueryParameterLimit = 1
 		}
-		for _, cg := range conf.SQL[j].Codegen {
-			if cg.Plugin == "" {
-				return conf, ErrPluginNoName
-			}
-			if cg.Out == "" {
-				return conf, ErrNoOutPath
-			}
-			// TOOD: Allow the use of built-in codegen from here
-			if _, ok := plugins[cg.Plugin]; !ok {
-				return conf, ErrPluginNotFound
+
+		for i := range sql.Gen.Go.Overrides {
+			if err := sql.Gen.Go.Overrides[i].Parse(); err != nil {
+				return err
 			}
 		}
-		if conf.SQL[j].StrictOrderBy == nil {
-			defaultValidate := true
-			conf.SQL[j].StrictOrderBy = &defaultValidate
+		for k, v := range sql.Gen.Go.Rename {
+			sql.Gen.Go.Rename[k] = v
 		}
 	}
-	return conf, nil
+	if sql.Gen.JSON != nil {
+		if sql.Gen.JSON.Out == "" {
+			return ErrNoOutPath
+		}
+	}
+	for _, cg := range sql.Codegen {
+		if cg.Plugin == "" {
+			return ErrPluginNoName
+		}
+		if cg.Out == "" {
+			return ErrNoOutPath
+		}
+		// TOOD: Allow the use of built-in codegen from here
+		if _, ok := plugins[cg.Plugin]; !ok {
+			return ErrPluginNotFound
+		}
+	}
+	if sql.StrictOrderBy == nil {
+		defaultValidate := true
+		sql.StrictOrderBy = &defaultValidate
+	}
+	return nil
 }
 
 func (c *Config) validateGlobalOverrides() error {
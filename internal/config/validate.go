@@ -3,24 +3,27 @@ package config
 import "fmt"
 
 func Validate(c *Config) error {
-	seen := make(map[string]struct{})
+	seen := make(map[string]SQL)
 	for _, sql := range c.SQL {
 		sqlGo := sql.Gen.Go
 		if sqlGo == nil {
 			continue
 		}
 		if sqlGo.EmitMethodsWithDBArgument && sqlGo.EmitPreparedQueries {
-			return fmt.Errorf("invalid config: emit_methods_with_db_argument and emit_prepared_queries settings are mutually exclusive")
+			return fmt.Errorf("invalid config%s: emit_methods_with_db_argument and emit_prepared_queries settings are mutually exclusive", sql.where())
 		}
 		if sql.Database != nil {
 			if sql.Database.URI == "" {
-				return fmt.Errorf("invalid config: database must have a non-empty URI")
+				return fmt.Errorf("invalid config%s: database must have a non-empty URI", sql.where())
 			}
 		}
-		if _, ok := seen[sql.Gen.Go.Package]; ok {
-			return fmt.Errorf("duplicated package name is not allowed: %s", sql.Gen.Go.Package)
+		if first, ok := seen[sql.Gen.Go.Package]; ok {
+			if first.file == "" && sql.file == "" {
+				return fmt.Errorf("duplicated package name is not allowed: %s", sql.Gen.Go.Package)
+			}
+			return fmt.Errorf("duplicated package name is not allowed: %s (in %s and %s)", sql.Gen.Go.Package, first.source(), sql.source())
 		}
-		seen[sql.Gen.Go.Package] = struct{}{}
+		seen[sql.Gen.Go.Package] = sql
 	}
 	return nil
 }
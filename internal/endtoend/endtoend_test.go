@@ -121,7 +121,7 @@ func TestReplay(t *testing.T) {
 			}
 			switch args.Command {
 			case "diff":
-				err = cmd.Diff(ctx, env, path, "", &stderr)
+				err = cmd.Diff(ctx, env, path, "", cmd.DiffOptions{Format: "text"}, &stderr, &stderr)
 			case "generate":
 				output, err = cmd.Generate(ctx, env, path, "", &stderr)
 				if err == nil {
@@ -17,3 +17,61 @@ func NewCatalog() *catalog.Catalog {
 	c.LoadExtension = loadExtension
 	return c
 }
+
+// nullableFuncs are the pg_catalog aggregate and window functions that return
+// NULL when there are no rows, or no non-NULL input values, to compute them
+// from. count is the only common aggregate that never does.
+var nullableFuncs = map[string]bool{
+	"array_agg":           true,
+	"avg":                 true,
+	"bit_and":             true,
+	"bit_or":              true,
+	"bit_xor":             true,
+	"bool_and":            true,
+	"bool_or":             true,
+	"corr":                true,
+	"covar_pop":           true,
+	"covar_samp":          true,
+	"every":               true,
+	"first_value":         true,
+	"json_agg":            true,
+	"json_object_agg":     true,
+	"jsonb_agg":           true,
+	"jsonb_object_agg":    true,
+	"lag":                 true,
+	"last_value":          true,
+	"lead":                true,
+	"max":                 true,
+	"min":                 true,
+	"mode":                true,
+	"nth_value":           true,
+	"percentile_cont":     true,
+	"percentile_disc":     true,
+	"range_agg":           true,
+	"range_intersect_agg": true,
+	"regr_avgx":           true,
+	"regr_avgy":           true,
+	"regr_intercept":      true,
+	"regr_r2":             true,
+	"regr_slope":          true,
+	"regr_sxx":            true,
+	"regr_sxy":            true,
+	"regr_syy":            true,
+	"stddev":              true,
+	"stddev_pop":          true,
+	"stddev_samp":         true,
+	"string_agg":          true,
+	"sum":                 true,
+	"var_pop":             true,
+	"var_samp":            true,
+	"variance":            true,
+	"xmlagg":              true,
+}
+
+func init() {
+	for _, f := range funcsgenPGCatalog {
+		if nullableFuncs[f.Name] {
+			f.ReturnTypeNullable = true
+		}
+	}
+}
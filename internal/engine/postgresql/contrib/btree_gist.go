@@ -262,8 +262,84 @@ var funcsBtreeGist = []*catalog.Function{
 	},
 }
 
+var opsBtreeGist = []*catalog.Operator{
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "date"},
+		Right:      &ast.TypeName{Name: "date"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "double precision"},
+		Right:      &ast.TypeName{Name: "double precision"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "interval"},
+		Right:      &ast.TypeName{Name: "interval"},
+		ReturnType: &ast.TypeName{Name: "interval"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "money"},
+		Right:      &ast.TypeName{Name: "money"},
+		ReturnType: &ast.TypeName{Name: "money"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "oid"},
+		Right:      &ast.TypeName{Name: "oid"},
+		ReturnType: &ast.TypeName{Name: "oid"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "real"},
+		Right:      &ast.TypeName{Name: "real"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "time without time zone"},
+		Right:      &ast.TypeName{Name: "time without time zone"},
+		ReturnType: &ast.TypeName{Name: "interval"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "timestamp with time zone"},
+		Right:      &ast.TypeName{Name: "timestamp with time zone"},
+		ReturnType: &ast.TypeName{Name: "interval"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "timestamp without time zone"},
+		Right:      &ast.TypeName{Name: "timestamp without time zone"},
+		ReturnType: &ast.TypeName{Name: "interval"},
+	},
+}
+
 func BtreeGist() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsBtreeGist
+	s.Operators = opsBtreeGist
 	return s
 }
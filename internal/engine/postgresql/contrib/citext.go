@@ -565,8 +565,132 @@ var funcsCitext = []*catalog.Function{
 	},
 }
 
+var opsCitext = []*catalog.Operator{
+	{
+		Name:       "!~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<=~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>=~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "citext"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~*",
+		Left:       &ast.TypeName{Name: "citext"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func Citext() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsCitext
+	s.Operators = opsCitext
 	return s
 }
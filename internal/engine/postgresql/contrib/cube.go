@@ -388,8 +388,60 @@ var funcsCube = []*catalog.Function{
 	},
 }
 
+var opsCube = []*catalog.Operator{
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<#>",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<=>",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "cube"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>",
+		Left:       &ast.TypeName{Name: "cube"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+}
+
 func Cube() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsCube
+	s.Operators = opsCube
 	return s
 }
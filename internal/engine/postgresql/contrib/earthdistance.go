@@ -99,8 +99,18 @@ var funcsEarthdistance = []*catalog.Function{
 	},
 }
 
+var opsEarthdistance = []*catalog.Operator{
+	{
+		Name:       "<@>",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+}
+
 func Earthdistance() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsEarthdistance
+	s.Operators = opsEarthdistance
 	return s
 }
@@ -515,8 +515,82 @@ var funcsHstore = []*catalog.Function{
 	},
 }
 
+var opsHstore = []*catalog.Operator{
+	{
+		Name:       "#<#",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "#<=#",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "#=",
+		Left:       &ast.TypeName{Name: "anyelement"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "anyelement"},
+	},
+	{
+		Name:       "#>#",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "#>=#",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "%#",
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "text[]"},
+	},
+	{
+		Name:       "%%",
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "text[]"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?&",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "text[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?|",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "text[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "hstore"},
+		Right:      &ast.TypeName{Name: "hstore"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func Hstore() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsHstore
+	s.Operators = opsHstore
 	return s
 }
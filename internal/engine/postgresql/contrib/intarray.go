@@ -328,8 +328,77 @@ var funcsIntarray = []*catalog.Function{
 	},
 }
 
+var opsIntarray = []*catalog.Operator{
+	{
+		Name:       "#",
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "integer[]"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "query_int"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer[]"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "integer[]"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "integer[]"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "query_int"},
+		Right:      &ast.TypeName{Name: "integer[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func Intarray() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsIntarray
+	s.Operators = opsIntarray
 	return s
 }
@@ -745,8 +745,252 @@ var funcsLtree = []*catalog.Function{
 	},
 }
 
+var opsLtree = []*catalog.Operator{
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "lquery[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "lquery[]"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "lquery[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "lquery[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?<@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "ltree"},
+	},
+	{
+		Name:       "?@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltxtquery"},
+		ReturnType: &ast.TypeName{Name: "ltree"},
+	},
+	{
+		Name:       "?@>",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "ltree"},
+	},
+	{
+		Name:       "?~",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "lquery"},
+		ReturnType: &ast.TypeName{Name: "ltree"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltxtquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltxtquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "ltxtquery"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "ltxtquery"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^<@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^<@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^<@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^?",
+		Left:       &ast.TypeName{Name: "lquery[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^?",
+		Left:       &ast.TypeName{Name: "lquery[]"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^?",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "lquery[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^?",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "lquery[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltxtquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltxtquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@",
+		Left:       &ast.TypeName{Name: "ltxtquery"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@",
+		Left:       &ast.TypeName{Name: "ltxtquery"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@>",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@>",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@>",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^~",
+		Left:       &ast.TypeName{Name: "lquery"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^~",
+		Left:       &ast.TypeName{Name: "lquery"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^~",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "lquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^~",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "lquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "lquery"},
+		Right:      &ast.TypeName{Name: "ltree"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "lquery"},
+		Right:      &ast.TypeName{Name: "ltree[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "ltree"},
+		Right:      &ast.TypeName{Name: "lquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "ltree[]"},
+		Right:      &ast.TypeName{Name: "lquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func Ltree() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsLtree
+	s.Operators = opsLtree
 	return s
 }
@@ -207,8 +207,66 @@ var funcsPgTrgm = []*catalog.Function{
 	},
 }
 
+var opsPgTrgm = []*catalog.Operator{
+	{
+		Name:       "%>",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "%>>",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<%",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "<->>",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "<->>>",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "<<%",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<->",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "<<<->",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+}
+
 func PgTrgm() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsPgTrgm
+	s.Operators = opsPgTrgm
 	return s
 }
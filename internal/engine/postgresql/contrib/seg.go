@@ -256,8 +256,60 @@ var funcsSeg = []*catalog.Function{
 	},
 }
 
+var opsSeg = []*catalog.Operator{
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "seg"},
+		Right:      &ast.TypeName{Name: "seg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func Seg() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsSeg
+	s.Operators = opsSeg
 	return s
 }
@@ -283,11 +283,13 @@ func translate(node *nodes.Node) (ast.Node, error) {
 					}
 					item.Subtype = ast.AT_AddColumn
 					item.Def = &ast.ColumnDef{
-						Colname:   d.ColumnDef.Colname,
-						TypeName:  rel.TypeName(),
-						IsNotNull: isNotNull(d.ColumnDef),
-						IsArray:   isArray(d.ColumnDef.TypeName),
-						ArrayDims: len(d.ColumnDef.TypeName.ArrayBounds),
+						Colname:     d.ColumnDef.Colname,
+						TypeName:    rel.TypeName(),
+						IsNotNull:   isNotNull(d.ColumnDef),
+						IsArray:     isArray(d.ColumnDef.TypeName),
+						ArrayDims:   len(d.ColumnDef.TypeName.ArrayBounds),
+						Constraints: convertSlice(d.ColumnDef.Constraints),
+						Location:    int(d.ColumnDef.Location),
 					}
 
 				case nodes.AlterTableType_AT_AlterColumnType:
@@ -314,6 +316,7 @@ func translate(node *nodes.Node) (ast.Node, error) {
 						IsNotNull: isNotNull(d.ColumnDef),
 						IsArray:   isArray(d.ColumnDef.TypeName),
 						ArrayDims: len(d.ColumnDef.TypeName.ArrayBounds),
+						Location:  int(d.ColumnDef.Location),
 					}
 
 				case nodes.AlterTableType_AT_DropColumn:
@@ -325,6 +328,14 @@ func translate(node *nodes.Node) (ast.Node, error) {
 				case nodes.AlterTableType_AT_SetNotNull:
 					item.Subtype = ast.AT_SetNotNull
 
+				case nodes.AlterTableType_AT_AddConstraint:
+					c, ok := altercmd.Def.Node.(*nodes.Node_Constraint)
+					if !ok {
+						return nil, fmt.Errorf("expected alter table defintion to be a Constraint")
+					}
+					item.Subtype = ast.AT_AddConstraint
+					item.Constraint = convertConstraint(c.Constraint)
+
 				default:
 					continue
 				}
@@ -395,9 +406,26 @@ func translate(node *nodes.Node) (ast.Node, error) {
 	case *nodes.Node_CompositeTypeStmt:
 		n := inner.CompositeTypeStmt
 		rel := parseRelationFromRangeVar(n.Typevar)
-		return &ast.CompositeTypeStmt{
+		stmt := &ast.CompositeTypeStmt{
 			TypeName: rel.TypeName(),
-		}, nil
+		}
+		for _, elt := range n.Coldeflist {
+			switch item := elt.Node.(type) {
+			case *nodes.Node_ColumnDef:
+				rel, err := parseRelationFromNodes(item.ColumnDef.TypeName.Names)
+				if err != nil {
+					return nil, err
+				}
+				stmt.Cols = append(stmt.Cols, &ast.ColumnDef{
+					Colname:   item.ColumnDef.Colname,
+					TypeName:  rel.TypeName(),
+					IsArray:   isArray(item.ColumnDef.TypeName),
+					ArrayDims: len(item.ColumnDef.TypeName.ArrayBounds),
+					Location:  int(item.ColumnDef.Location),
+				})
+			}
+		}
+		return stmt, nil
 
 	case *nodes.Node_CreateStmt:
 		n := inner.CreateStmt
@@ -422,7 +450,9 @@ func translate(node *nodes.Node) (ast.Node, error) {
 				if item.Constraint.Contype == nodes.ConstrType_CONSTR_PRIMARY {
 					for _, key := range item.Constraint.Keys {
 						// FIXME: Possible nil pointer dereference
-						primaryKey[key.Node.(*nodes.Node_String_).String_.Sval] = true
+						name := key.Node.(*nodes.Node_String_).String_.Sval
+						primaryKey[name] = true
+						create.PrimaryKey = append(create.PrimaryKey, name)
 					}
 				}
 
@@ -439,12 +469,17 @@ func translate(node *nodes.Node) (ast.Node, error) {
 					return nil, err
 				}
 				create.Cols = append(create.Cols, &ast.ColumnDef{
-					Colname:   item.ColumnDef.Colname,
-					TypeName:  rel.TypeName(),
-					IsNotNull: isNotNull(item.ColumnDef) || primaryKey[item.ColumnDef.Colname],
-					IsArray:   isArray(item.ColumnDef.TypeName),
-					ArrayDims: len(item.ColumnDef.TypeName.ArrayBounds),
+					Colname:     item.ColumnDef.Colname,
+					TypeName:    rel.TypeName(),
+					IsNotNull:   isNotNull(item.ColumnDef) || primaryKey[item.ColumnDef.Colname],
+					IsArray:     isArray(item.ColumnDef.TypeName),
+					ArrayDims:   len(item.ColumnDef.TypeName.ArrayBounds),
+					Constraints: convertSlice(item.ColumnDef.Constraints),
+					Location:    int(item.ColumnDef.Location),
 				})
+				if isPrimaryKey(item.ColumnDef) {
+					create.PrimaryKey = append(create.PrimaryKey, item.ColumnDef.Colname)
+				}
 			}
 		}
 		return create, nil
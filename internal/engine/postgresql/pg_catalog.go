@@ -30175,9 +30175,1557 @@ var funcsgenPGCatalog = []*catalog.Function{
 	},
 }
 
+var opsgenPGCatalog = []*catalog.Operator{
+	{
+		Name:       "!!",
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "tsquery"},
+	},
+	{
+		Name:       "!~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~*",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~*",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~*",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "bytea"},
+		Right:      &ast.TypeName{Name: "bytea"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~*",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~*",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "!~~*",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "#",
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "#",
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "bit"},
+		Right:      &ast.TypeName{Name: "bit"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "box"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "#",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "##",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "#-",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "text[]"},
+		ReturnType: &ast.TypeName{Name: "jsonb"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "bit"},
+		Right:      &ast.TypeName{Name: "bit"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "inet"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "macaddr"},
+		Right:      &ast.TypeName{Name: "macaddr"},
+		ReturnType: &ast.TypeName{Name: "macaddr"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "macaddr8"},
+		Right:      &ast.TypeName{Name: "macaddr8"},
+		ReturnType: &ast.TypeName{Name: "macaddr8"},
+	},
+	{
+		Name:       "&",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "anyarray"},
+		Right:      &ast.TypeName{Name: "anyarray"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&&",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "tsquery"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<|",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<|",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&<|",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "&>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*<",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*<=",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*<>",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*=",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*>",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "*>=",
+		Left:       &ast.TypeName{Name: "record"},
+		Right:      &ast.TypeName{Name: "record"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "-|-",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "-|-",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "-|-",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "-|-",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "path"},
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "path"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "<->",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "tsquery"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "bit"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "<<=",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<|",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<|",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<|",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<<|",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anyarray"},
+		Right:      &ast.TypeName{Name: "anyarray"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anyelement"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anyelement"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "jsonb"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<@",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<^",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "<^",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "bit"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">>",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       ">>=",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">^",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       ">^",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?#",
+		Left:       &ast.TypeName{Name: "path"},
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?&",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "text[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?-",
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?-",
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?-",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?-|",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?-|",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?|",
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?|",
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?|",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "text[]"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?|",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?||",
+		Left:       &ast.TypeName{Name: "line"},
+		Right:      &ast.TypeName{Name: "line"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "?||",
+		Left:       &ast.TypeName{Name: "lseg"},
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "double precision"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "numeric"},
+		ReturnType: &ast.TypeName{Name: "numeric"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "real"},
+		ReturnType: &ast.TypeName{Name: "real"},
+	},
+	{
+		Name:       "@",
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "@-@",
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "@-@",
+		Right:      &ast.TypeName{Name: "path"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "aclitem[]"},
+		Right:      &ast.TypeName{Name: "aclitem"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anyarray"},
+		Right:      &ast.TypeName{Name: "anyarray"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyelement"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anymultirange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyelement"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anymultirange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "anyrange"},
+		Right:      &ast.TypeName{Name: "anyrange"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "jsonb"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "path"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@>",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@?",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "jsonpath"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "@@",
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "@@",
+		Right:      &ast.TypeName{Name: "lseg"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "@@",
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "point"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "jsonb"},
+		Right:      &ast.TypeName{Name: "jsonpath"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsvector"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@",
+		Left:       &ast.TypeName{Name: "tsvector"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@@",
+		Left:       &ast.TypeName{Name: "tsquery"},
+		Right:      &ast.TypeName{Name: "tsvector"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "@@@",
+		Left:       &ast.TypeName{Name: "tsvector"},
+		Right:      &ast.TypeName{Name: "tsquery"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "^@",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "bigint"},
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "bit"},
+		Right:      &ast.TypeName{Name: "bit"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "inet"},
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "inet"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "integer"},
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "macaddr"},
+		Right:      &ast.TypeName{Name: "macaddr"},
+		ReturnType: &ast.TypeName{Name: "macaddr"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "macaddr8"},
+		Right:      &ast.TypeName{Name: "macaddr8"},
+		ReturnType: &ast.TypeName{Name: "macaddr8"},
+	},
+	{
+		Name:       "|",
+		Left:       &ast.TypeName{Name: "smallint"},
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "|&>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|&>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|&>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|/",
+		Right:      &ast.TypeName{Name: "double precision"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "|>>",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|>>",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|>>",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "|>>",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "||/",
+		Right:      &ast.TypeName{Name: "double precision"},
+		ReturnType: &ast.TypeName{Name: "double precision"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "bigint"},
+		ReturnType: &ast.TypeName{Name: "bigint"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "bit"},
+		ReturnType: &ast.TypeName{Name: "bit"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "inet"},
+		ReturnType: &ast.TypeName{Name: "inet"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "integer"},
+		ReturnType: &ast.TypeName{Name: "integer"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "macaddr"},
+		ReturnType: &ast.TypeName{Name: "macaddr"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "macaddr8"},
+		ReturnType: &ast.TypeName{Name: "macaddr8"},
+	},
+	{
+		Name:       "~",
+		Right:      &ast.TypeName{Name: "smallint"},
+		ReturnType: &ast.TypeName{Name: "smallint"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~*",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~*",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~*",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<=~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "character"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<=~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "character"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~<~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~=",
+		Left:       &ast.TypeName{Name: "box"},
+		Right:      &ast.TypeName{Name: "box"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~=",
+		Left:       &ast.TypeName{Name: "circle"},
+		Right:      &ast.TypeName{Name: "circle"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~=",
+		Left:       &ast.TypeName{Name: "point"},
+		Right:      &ast.TypeName{Name: "point"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~=",
+		Left:       &ast.TypeName{Name: "polygon"},
+		Right:      &ast.TypeName{Name: "polygon"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>=~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "character"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>=~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "character"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~>~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "bytea"},
+		Right:      &ast.TypeName{Name: "bytea"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~*",
+		Left:       &ast.TypeName{Name: "character"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~*",
+		Left:       &ast.TypeName{Name: "name"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+	{
+		Name:       "~~*",
+		Left:       &ast.TypeName{Name: "text"},
+		Right:      &ast.TypeName{Name: "text"},
+		ReturnType: &ast.TypeName{Name: "boolean"},
+	},
+}
+
 func genPGCatalog() *catalog.Schema {
 	s := &catalog.Schema{Name: "pg_catalog"}
 	s.Funcs = funcsgenPGCatalog
+	s.Operators = opsgenPGCatalog
 	s.Tables = []*catalog.Table{
 		{
 			Rel: &ast.TableName{
@@ -32,6 +32,15 @@ func isNotNull(n *nodes.ColumnDef) bool {
 	return false
 }
 
+func isPrimaryKey(n *nodes.ColumnDef) bool {
+	for _, c := range n.Constraints {
+		if inner, ok := c.Node.(*nodes.Node_Constraint); ok && inner.Constraint.Contype == nodes.ConstrType_CONSTR_PRIMARY {
+			return true
+		}
+	}
+	return false
+}
+
 func IsNamedParamFunc(node *nodes.Node) bool {
 	fun, ok := node.Node.(*nodes.Node_FuncCall)
 	return ok && joinNodes(fun.FuncCall.Funcname, ".") == "sqlc.arg"
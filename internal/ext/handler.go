@@ -4,12 +4,18 @@ import (
 	"context"
 
 	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/vet"
 )
 
 type Handler interface {
 	Generate(context.Context, *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error)
 }
 
+// Checker runs a vet rule implemented by a plugin.
+type Checker interface {
+	Check(context.Context, *vet.CheckRequest) (*vet.CheckResponse, error)
+}
+
 type wrapper struct {
 	fn func(context.Context, *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error)
 }
@@ -5,36 +5,72 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/sqlc-dev/sqlc/internal/info"
 	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/vet"
 )
 
 type Runner struct {
 	Cmd string
 	Env []string
+
+	// Protocol is empty to start the plugin for every request, or the
+	// protocol of a persistent plugin kept running by Pool.
+	Protocol string
+	Pool     *Pool
+	// Stderr receives the standard error of a persistent plugin while it
+	// handles the request.
+	Stderr io.Writer
 }
 
 // TODO: Update the gen func signature to take a ctx
 func (r Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error) {
+	if r.Protocol != "" {
+		if r.Pool == nil {
+			return nil, fmt.Errorf("process: no pool for persistent plugin %s", r.Cmd)
+		}
+		return r.Pool.get(r).generate(ctx, req, r.Stderr)
+	}
+
+	var resp plugin.CodeGenResponse
+	if err := r.run(ctx, req.SqlcVersion, "codegen", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Check runs a vet rule implemented by the plugin. The plugin is started for
+// every request, whatever its protocol.
+func (r Runner) Check(ctx context.Context, req *vet.CheckRequest) (*vet.CheckResponse, error) {
+	var resp vet.CheckResponse
+	if err := r.run(ctx, info.Version, "vet", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r Runner) run(ctx context.Context, version, kind string, req, resp proto.Message) error {
 	stdin, err := proto.Marshal(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode codegen request: %s", err)
+		return fmt.Errorf("failed to encode %s request: %s", kind, err)
 	}
 
 	// Check if the output plugin exists
 	path, err := exec.LookPath(r.Cmd)
 	if err != nil {
-		return nil, fmt.Errorf("process: %s not found", r.Cmd)
+		return fmt.Errorf("process: %s not found", r.Cmd)
 	}
 
 	cmd := exec.CommandContext(ctx, path)
 	cmd.Stdin = bytes.NewReader(stdin)
 	cmd.Env = []string{
-		fmt.Sprintf("SQLC_VERSION=%s", req.SqlcVersion),
+		fmt.Sprintf("SQLC_VERSION=%s", version),
 	}
 	for _, key := range r.Env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, os.Getenv(key)))
@@ -47,13 +83,11 @@ func (r Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plug
 		if errors.As(err, &exit) {
 			stderr = string(exit.Stderr)
 		}
-		return nil, fmt.Errorf("process: error running command %s", stderr)
+		return fmt.Errorf("process: error running command %s", stderr)
 	}
 
-	var resp plugin.CodeGenResponse
-	if err := proto.Unmarshal(out, &resp); err != nil {
-		return nil, fmt.Errorf("process: failed to read codegen resp: %s", err)
+	if err := proto.Unmarshal(out, resp); err != nil {
+		return fmt.Errorf("process: failed to read %s resp: %s", kind, err)
 	}
-
-	return &resp, nil
+	return nil
 }
@@ -0,0 +1,386 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sqlc-dev/sqlc/internal/info"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// startTimeout bounds how long a gRPC plugin may take to accept connections.
+const startTimeout = 10 * time.Second
+
+// Pool keeps persistent plugins running across the packages of a single
+// run. Each plugin is started once and handles one request at a time, so
+// that its standard error can be attributed to the package being generated.
+type Pool struct {
+	stderr io.Writer
+
+	mu      sync.Mutex
+	plugins map[string]*persistentPlugin
+}
+
+// NewPool returns a pool that copies standard error written by idle plugins
+// to stderr.
+func NewPool(stderr io.Writer) *Pool {
+	return &Pool{stderr: stderr, plugins: map[string]*persistentPlugin{}}
+}
+
+// Close stops all plugins started by the pool.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, plug := range p.plugins {
+		plug.close()
+		delete(p.plugins, key)
+	}
+}
+
+func (p *Pool) get(r Runner) *persistentPlugin {
+	key := strings.Join(append([]string{r.Protocol, r.Cmd}, r.Env...), "\x00")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	plug, ok := p.plugins[key]
+	if !ok {
+		plug = &persistentPlugin{
+			cmd:      r.Cmd,
+			env:      r.Env,
+			protocol: r.Protocol,
+			idle:     p.stderr,
+		}
+		p.plugins[key] = plug
+	}
+	return plug
+}
+
+// stderrPipe forwards the standard error of a plugin to the writer of the
+// request it is handling, or to the idle writer between requests.
+type stderrPipe struct {
+	r *os.File
+
+	mu   sync.Mutex
+	idle io.Writer
+	w    io.Writer
+
+	paused chan struct{}
+	resume chan struct{}
+	done   chan struct{}
+}
+
+func newStderrPipe(r *os.File, idle io.Writer) *stderrPipe {
+	s := &stderrPipe{
+		r:      r,
+		idle:   idle,
+		paused: make(chan struct{}),
+		resume: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *stderrPipe) write(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.w
+	if w == nil {
+		w = s.idle
+	}
+	if w != nil {
+		w.Write(p)
+	}
+}
+
+func (s *stderrPipe) run() {
+	defer close(s.done)
+	buf := make([]byte, 4096)
+	for {
+		n, err := s.r.Read(buf)
+		if n > 0 {
+			s.write(buf[:n])
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			s.paused <- struct{}{}
+			<-s.resume
+			continue
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// start sends the standard error of the plugin to w.
+func (s *stderrPipe) start(w io.Writer) {
+	s.mu.Lock()
+	s.w = w
+	s.mu.Unlock()
+}
+
+// stop waits for the standard error written by the plugin before it answered
+// to reach the writer of the request, then sends it to the idle writer.
+func (s *stderrPipe) stop() {
+	defer s.start(nil)
+	// The plugin may write to standard error right before its response, and
+	// the two pipes are read independently. Pause the reader, then drain what
+	// is already buffered. Without deadline support the output is forwarded
+	// as it arrives.
+	if err := s.r.SetReadDeadline(time.Now()); err != nil {
+		return
+	}
+	select {
+	case <-s.paused:
+	case <-s.done:
+		return
+	}
+	buf := make([]byte, 4096)
+	for {
+		s.r.SetReadDeadline(time.Now().Add(time.Millisecond))
+		n, err := s.r.Read(buf)
+		if n > 0 {
+			s.write(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	s.r.SetReadDeadline(time.Time{})
+	s.resume <- struct{}{}
+}
+
+type persistentPlugin struct {
+	cmd      string
+	env      []string
+	protocol string
+	idle     io.Writer
+
+	// mu serializes requests.
+	mu      sync.Mutex
+	started bool
+	err     error
+
+	proc   *exec.Cmd
+	stderr *stderrPipe
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	conn   *grpc.ClientConn
+	tmpdir string
+}
+
+func (p *persistentPlugin) start(ctx context.Context, stderr io.Writer) error {
+	path, err := exec.LookPath(p.cmd)
+	if err != nil {
+		return fmt.Errorf("process: %s not found", p.cmd)
+	}
+	// The process outlives the context of the request that started it and is
+	// stopped by Pool.Close.
+	p.proc = exec.Command(path)
+	p.proc.Env = []string{
+		fmt.Sprintf("SQLC_VERSION=%s", info.Version),
+		fmt.Sprintf("%s=%s", EnvProtocol, p.protocol),
+	}
+	for _, key := range p.env {
+		p.proc.Env = append(p.proc.Env, fmt.Sprintf("%s=%s", key, os.Getenv(key)))
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stderrW.Close()
+	p.proc.Stderr = stderrW
+
+	switch p.protocol {
+	case ProtocolStdio:
+		stdin, err := p.proc.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stdout, err := p.proc.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		p.stdin = stdin
+		p.stdout = bufio.NewReader(stdout)
+	case ProtocolGRPC:
+		p.tmpdir, err = os.MkdirTemp("", "sqlc-plugin-")
+		if err != nil {
+			return err
+		}
+		p.proc.Stdout = stderrW
+		socket := filepath.Join(p.tmpdir, "plugin.sock")
+		p.proc.Env = append(p.proc.Env, fmt.Sprintf("%s=%s", EnvSocket, socket))
+		// Retry quickly while the plugin starts listening.
+		params := grpc.ConnectParams{Backoff: backoff.DefaultConfig, MinConnectTimeout: startTimeout}
+		params.Backoff.BaseDelay = 10 * time.Millisecond
+		params.Backoff.MaxDelay = 100 * time.Millisecond
+		p.conn, err = grpc.Dial("unix://"+socket,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithConnectParams(params))
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("process: unknown protocol %q", p.protocol)
+	}
+
+	if err := p.proc.Start(); err != nil {
+		stderrR.Close()
+		return fmt.Errorf("process: error starting %s: %w", p.cmd, err)
+	}
+	p.stderr = newStderrPipe(stderrR, p.idle)
+	p.stderr.start(stderr)
+	return p.handshake(ctx)
+}
+
+func (p *persistentPlugin) handshake(ctx context.Context) error {
+	offered := wrapperspb.UInt32(ProtocolVersion)
+	var version wrapperspb.UInt32Value
+	switch p.protocol {
+	case ProtocolStdio:
+		kind, payload, err := p.exchange(ctx, frameHandshake, offered)
+		if err != nil {
+			return err
+		}
+		if kind != frameHandshake {
+			return fmt.Errorf("process: %s: expected handshake, got frame kind %d", p.cmd, kind)
+		}
+		if err := proto.Unmarshal(payload, &version); err != nil {
+			return fmt.Errorf("process: %s: invalid handshake: %w", p.cmd, err)
+		}
+	case ProtocolGRPC:
+		ctx, cancel := context.WithTimeout(ctx, startTimeout)
+		defer cancel()
+		err := p.conn.Invoke(ctx, methodHandshake, offered, &version, grpc.WaitForReady(true))
+		if err != nil {
+			return fmt.Errorf("process: %s: handshake failed: %s", p.cmd, status.Convert(err).Message())
+		}
+	}
+	return checkVersion(p.cmd, version.Value)
+}
+
+// exchange sends a frame to a stdio plugin and reads its reply. Reading from
+// the plugin can't be interrupted, so when ctx is done before the plugin
+// answers, the plugin is killed and the error of ctx returned.
+func (p *persistentPlugin) exchange(ctx context.Context, kind byte, m proto.Message) (byte, []byte, error) {
+	type reply struct {
+		kind    byte
+		payload []byte
+		err     error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		if err := writeMessage(p.stdin, kind, m); err != nil {
+			done <- reply{err: p.exited(err)}
+			return
+		}
+		kind, payload, err := readFrame(p.stdout)
+		if err != nil {
+			err = p.exited(err)
+		}
+		done <- reply{kind, payload, err}
+	}()
+	select {
+	case r := <-done:
+		return r.kind, r.payload, r.err
+	case <-ctx.Done():
+		p.proc.Process.Kill()
+		return 0, nil, fmt.Errorf("process: %s: %w", p.cmd, ctx.Err())
+	}
+}
+
+func (p *persistentPlugin) exited(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("process: %s exited unexpectedly", p.cmd)
+	}
+	return fmt.Errorf("process: %s: %w", p.cmd, err)
+}
+
+func (p *persistentPlugin) generate(ctx context.Context, req *plugin.CodeGenRequest, stderr io.Writer) (*plugin.CodeGenResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.started {
+		p.started = true
+		p.err = p.start(ctx, stderr)
+	}
+	if p.stderr != nil {
+		p.stderr.start(stderr)
+		defer p.stderr.stop()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	switch p.protocol {
+	case ProtocolStdio:
+		kind, payload, err := p.exchange(ctx, frameRequest, req)
+		if err != nil {
+			p.err = err
+			return nil, p.err
+		}
+		switch kind {
+		case frameResponse:
+			var resp plugin.CodeGenResponse
+			if err := proto.Unmarshal(payload, &resp); err != nil {
+				return nil, fmt.Errorf("process: failed to read codegen resp: %s", err)
+			}
+			return &resp, nil
+		case frameError:
+			return nil, fmt.Errorf("process: %s: %s", p.cmd, payload)
+		default:
+			p.err = fmt.Errorf("process: %s: unexpected frame kind %d", p.cmd, kind)
+			return nil, p.err
+		}
+	default:
+		var resp plugin.CodeGenResponse
+		if err := p.conn.Invoke(ctx, methodGenerate, req, &resp); err != nil {
+			return nil, fmt.Errorf("process: %s: %s", p.cmd, status.Convert(err).Message())
+		}
+		return &resp, nil
+	}
+}
+
+func (p *persistentPlugin) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	if p.tmpdir != "" {
+		defer os.RemoveAll(p.tmpdir)
+	}
+	if p.proc == nil || p.proc.Process == nil {
+		return
+	}
+	// Stdio plugins exit once their input is closed; others are stopped.
+	if p.stdin != nil {
+		p.stdin.Close()
+	} else {
+		p.proc.Process.Kill()
+	}
+	done := make(chan error, 1)
+	go func() { done <- p.proc.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		p.proc.Process.Kill()
+		<-done
+	}
+}
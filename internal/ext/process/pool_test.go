@@ -0,0 +1,150 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sqlc-dev/sqlc/internal/ext"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// TestMain runs the test binary as a persistent plugin when started by a
+// Pool.
+func TestMain(m *testing.M) {
+	h := ext.HandleFunc(func(ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error) {
+		fmt.Fprintf(os.Stderr, "generating %s\n", req.PluginOptions)
+		switch string(req.PluginOptions) {
+		case "fail":
+			return nil, fmt.Errorf("failed")
+		case "hang":
+			time.Sleep(time.Hour)
+		}
+		return &plugin.CodeGenResponse{
+			Files: []*plugin.File{{Name: "pid", Contents: []byte(fmt.Sprint(os.Getpid()))}},
+		}, nil
+	})
+	var err error
+	switch os.Getenv(EnvProtocol) {
+	case ProtocolStdio:
+		err = ServeStdio(context.Background(), os.Stdin, os.Stdout, h)
+	case ProtocolGRPC:
+		var lis net.Listener
+		lis, err = net.Listen("unix", os.Getenv(EnvSocket))
+		if err == nil {
+			err = ServeGRPC(lis, h)
+		}
+	default:
+		os.Exit(m.Run())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestPersistent(t *testing.T) {
+	for _, protocol := range []string{ProtocolStdio, ProtocolGRPC} {
+		t.Run(protocol, func(t *testing.T) {
+			pool := NewPool(nil)
+			defer pool.Close()
+
+			var pids []string
+			for _, pkg := range []string{"a", "b", "fail"} {
+				var stderr bytes.Buffer
+				r := Runner{Cmd: os.Args[0], Protocol: protocol, Pool: pool, Stderr: &stderr}
+				resp, err := r.Generate(context.Background(), &plugin.CodeGenRequest{PluginOptions: []byte(pkg)})
+				if pkg == "fail" {
+					if err == nil {
+						t.Fatalf("expected an error")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				pids = append(pids, string(resp.Files[0].Contents))
+				if want := fmt.Sprintf("generating %s\n", pkg); stderr.String() != want {
+					t.Errorf("stderr: want %q, got %q", want, stderr.String())
+				}
+			}
+			if pids[0] != pids[1] {
+				t.Errorf("plugin was started more than once: %v", pids)
+			}
+		})
+	}
+}
+
+// TestPersistentCancel checks that a request to a stdio plugin that never
+// answers returns when its context is done, and that the plugin isn't used
+// again.
+func TestPersistentCancel(t *testing.T) {
+	pool := NewPool(nil)
+	defer pool.Close()
+
+	r := Runner{Cmd: os.Args[0], Protocol: ProtocolStdio, Pool: pool, Stderr: io.Discard}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := r.Generate(ctx, &plugin.CodeGenRequest{PluginOptions: []byte("hang")})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if _, err := r.Generate(context.Background(), &plugin.CodeGenRequest{PluginOptions: []byte("a")}); err == nil {
+		t.Errorf("the plugin was used again after it was killed")
+	}
+}
+
+// TestCodegenService checks the service served to grpc plugins against
+// protos/plugin/process.proto.
+func TestCodegenService(t *testing.T) {
+	svc := plugin.File_plugin_process_proto.Services().ByName("CodegenService")
+	if svc == nil {
+		t.Fatal("no CodegenService in plugin/process.proto")
+	}
+	if got, want := codegenServiceDesc.ServiceName, string(svc.FullName()); got != want {
+		t.Errorf("service name: got %s, want %s", got, want)
+	}
+	if got, want := len(codegenServiceDesc.Methods), svc.Methods().Len(); got != want {
+		t.Errorf("methods: got %d, want %d", got, want)
+	}
+	for _, m := range codegenServiceDesc.Methods {
+		if svc.Methods().ByName(protoreflect.Name(m.MethodName)) == nil {
+			t.Errorf("method %s is not in plugin/process.proto", m.MethodName)
+		}
+	}
+	for _, m := range []struct {
+		path string
+		in   proto.Message
+		out  proto.Message
+	}{
+		{methodHandshake, &wrapperspb.UInt32Value{}, &wrapperspb.UInt32Value{}},
+		{methodGenerate, &plugin.CodeGenRequest{}, &plugin.CodeGenResponse{}},
+	} {
+		desc := svc.Methods().ByName(protoreflect.Name(path.Base(m.path)))
+		if desc == nil {
+			t.Errorf("%s is not in plugin/process.proto", m.path)
+			continue
+		}
+		if got, want := "/"+string(svc.FullName())+"/"+string(desc.Name()), m.path; got != want {
+			t.Errorf("method path: got %s, want %s", got, want)
+		}
+		if desc.Input().FullName() != m.in.ProtoReflect().Descriptor().FullName() {
+			t.Errorf("%s input: got %s", m.path, desc.Input().FullName())
+		}
+		if desc.Output().FullName() != m.out.ProtoReflect().Descriptor().FullName() {
+			t.Errorf("%s output: got %s", m.path, desc.Output().FullName())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sqlc-dev/sqlc/internal/ext"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// ProtocolVersion is the version of the persistent plugin protocol spoken by
+// this sqlc. During the handshake sqlc sends the highest version it supports
+// and the plugin answers with the version it will use.
+const ProtocolVersion = 1
+
+const (
+	ProtocolStdio = "stdio"
+	ProtocolGRPC  = "grpc"
+)
+
+// Environment variables passed to persistent plugins.
+const (
+	EnvProtocol = "SQLC_PLUGIN_PROTOCOL"
+	EnvSocket   = "SQLC_PLUGIN_SOCKET"
+)
+
+// Frames exchanged over stdin and stdout by stdio plugins: a kind byte, the
+// payload length as a big-endian uint32, then the payload.
+const (
+	frameHandshake byte = 1 // wrapperspb.UInt32Value
+	frameRequest   byte = 2 // plugin.CodeGenRequest
+	frameResponse  byte = 3 // plugin.CodeGenResponse
+	frameError     byte = 4 // UTF-8 error message
+)
+
+func writeFrame(w io.Writer, kind byte, payload []byte) error {
+	var header [5]byte
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func writeMessage(w io.Writer, kind byte, m proto.Message) error {
+	blob, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFrame(w, kind, blob)
+}
+
+// negotiate picks the protocol version to use given the highest version
+// supported by sqlc.
+func negotiate(offered uint32) (uint32, error) {
+	if offered < 1 {
+		return 0, fmt.Errorf("unsupported protocol version %d", offered)
+	}
+	return min(offered, ProtocolVersion), nil
+}
+
+func checkVersion(cmd string, version uint32) error {
+	if version < 1 || version > ProtocolVersion {
+		return fmt.Errorf("process: %s speaks protocol version %d, sqlc supports versions 1 to %d", cmd, version, ProtocolVersion)
+	}
+	return nil
+}
+
+// ServeStdio answers framed requests read from r with h until r is closed.
+// It is used by plugins written in Go that run with protocol stdio.
+func ServeStdio(ctx context.Context, r io.Reader, w io.Writer, h ext.Handler) error {
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	for {
+		kind, payload, err := readFrame(in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case frameHandshake:
+			var offered wrapperspb.UInt32Value
+			if err := proto.Unmarshal(payload, &offered); err != nil {
+				return err
+			}
+			version, err := negotiate(offered.Value)
+			if err != nil {
+				return err
+			}
+			err = writeMessage(out, frameHandshake, wrapperspb.UInt32(version))
+			if err != nil {
+				return err
+			}
+		case frameRequest:
+			var req plugin.CodeGenRequest
+			if err := proto.Unmarshal(payload, &req); err != nil {
+				return err
+			}
+			resp, err := h.Generate(ctx, &req)
+			if err != nil {
+				err = writeFrame(out, frameError, []byte(err.Error()))
+			} else {
+				err = writeMessage(out, frameResponse, resp)
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown frame kind %d", kind)
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+const (
+	methodHandshake = "/plugin.CodegenService/Handshake"
+	methodGenerate  = "/plugin.CodegenService/Generate"
+)
+
+type codegenServer struct {
+	h ext.Handler
+}
+
+func (s *codegenServer) handshake(ctx context.Context, offered *wrapperspb.UInt32Value) (*wrapperspb.UInt32Value, error) {
+	version, err := negotiate(offered.Value)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return wrapperspb.UInt32(version), nil
+}
+
+type methodHandler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error)
+
+func unaryHandler[Req, Resp any](fn func(*codegenServer, context.Context, *Req) (*Resp, error)) methodHandler {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		return fn(srv.(*codegenServer), ctx, in)
+	}
+}
+
+var codegenServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.CodegenService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    unaryHandler((*codegenServer).handshake),
+		},
+		{
+			MethodName: "Generate",
+			Handler: unaryHandler(func(s *codegenServer, ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error) {
+				return s.h.Generate(ctx, req)
+			}),
+		},
+	},
+}
+
+// ServeGRPC serves the plugin.CodegenService with h on lis until it is
+// closed. Plugins written in Go that run with protocol grpc listen on the unix
+// socket named by SQLC_PLUGIN_SOCKET.
+func ServeGRPC(lis net.Listener, h ext.Handler) error {
+	srv := grpc.NewServer()
+	srv.RegisterService(&codegenServiceDesc, &codegenServer{h: h})
+	return srv.Serve(lis)
+}
@@ -7,8 +7,13 @@ import (
 	"fmt"
 
 	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/vet"
 )
 
 func (r *Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error) {
 	return nil, fmt.Errorf("sqlc built without wasmtime support")
 }
+
+func (r *Runner) Check(ctx context.Context, req *vet.CheckRequest) (*vet.CheckResponse, error) {
+	return nil, fmt.Errorf("sqlc built without wasmtime support")
+}
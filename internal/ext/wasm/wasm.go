@@ -20,30 +20,17 @@ import (
 
 	wasmtime "github.com/bytecodealliance/wasmtime-go/v12"
 	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/sqlc-dev/sqlc/internal/cache"
 	"github.com/sqlc-dev/sqlc/internal/info"
 	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/vet"
 )
 
 // This version must be updated whenever the wasmtime-go dependency is updated
 const wasmtimeVersion = `v12.0.0`
 
-func cacheDir() (string, error) {
-	cache := os.Getenv("SQLCCACHE")
-	if cache != "" {
-		return cache, nil
-	}
-	cacheHome := os.Getenv("XDG_CACHE_HOME")
-	if cacheHome == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		cacheHome = filepath.Join(home, ".cache")
-	}
-	return filepath.Join(cacheHome, "sqlc"), nil
-}
-
 var flight singleflight.Group
 
 // Verify the provided sha256 is valid.
@@ -77,12 +64,8 @@ func (r *Runner) loadSerializedModule(ctx context.Context, engine *wasmtime.Engi
 	if err != nil {
 		return nil, err
 	}
-	cacheRoot, err := cacheDir()
+	cache, err := cache.Dir("plugins")
 	if err != nil {
-		return nil, err
-	}
-	cache := filepath.Join(cacheRoot, "plugins")
-	if err := os.MkdirAll(cache, 0755); err != nil && !os.IsExist(err) {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
@@ -193,13 +176,13 @@ func (r *Runner) loadWASM(ctx context.Context, cache string, expected string) ([
 // mysterious (reason unknown) bug with wasm plugins when a large amount of
 // tables (like there are in the catalog) are sent.
 // @see https://github.com/sqlc-dev/sqlc/pull/1748
-func removePGCatalog(req *plugin.CodeGenRequest) {
-	if req.Catalog == nil || req.Catalog.Schemas == nil {
+func removePGCatalog(catalog *plugin.Catalog) {
+	if catalog == nil || catalog.Schemas == nil {
 		return
 	}
 
-	filtered := make([]*plugin.Schema, 0, len(req.Catalog.Schemas))
-	for _, schema := range req.Catalog.Schemas {
+	filtered := make([]*plugin.Schema, 0, len(catalog.Schemas))
+	for _, schema := range catalog.Schemas {
 		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
 			continue
 		}
@@ -207,18 +190,58 @@ func removePGCatalog(req *plugin.CodeGenRequest) {
 		filtered = append(filtered, schema)
 	}
 
-	req.Catalog.Schemas = filtered
+	catalog.Schemas = filtered
 }
 
 func (r *Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plugin.CodeGenResponse, error) {
 	// Remove the pg_catalog schema. Its sheer size causes unknown issues with wasm plugins
-	removePGCatalog(req)
+	removePGCatalog(req.Catalog)
 
 	stdinBlob, err := req.MarshalVT()
 	if err != nil {
 		return nil, err
 	}
+	stdoutBlob, err := r.run(ctx, req.SqlcVersion, stdinBlob)
+	if err != nil {
+		return nil, err
+	}
+	var resp plugin.CodeGenResponse
+	return &resp, resp.UnmarshalVT(stdoutBlob)
+}
 
+// Check runs a vet rule implemented by the plugin.
+func (r *Runner) Check(ctx context.Context, req *vet.CheckRequest) (*vet.CheckResponse, error) {
+	if req.Catalog != nil {
+		// The catalog is shared with other rules, so filter a copy.
+		catalog := &plugin.Catalog{
+			Comment:       req.Catalog.Comment,
+			DefaultSchema: req.Catalog.DefaultSchema,
+			Name:          req.Catalog.Name,
+			Schemas:       req.Catalog.Schemas,
+			RawSqls:       req.Catalog.RawSqls,
+		}
+		removePGCatalog(catalog)
+		req = &vet.CheckRequest{
+			Rule:    req.Rule,
+			Config:  req.Config,
+			Queries: req.Queries,
+			Catalog: catalog,
+		}
+	}
+
+	stdinBlob, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	stdoutBlob, err := r.run(ctx, info.Version, stdinBlob)
+	if err != nil {
+		return nil, err
+	}
+	var resp vet.CheckResponse
+	return &resp, proto.Unmarshal(stdoutBlob, &resp)
+}
+
+func (r *Runner) run(ctx context.Context, version string, stdinBlob []byte) ([]byte, error) {
 	engine := wasmtime.NewEngine()
 	module, err := r.loadModule(ctx, engine)
 	if err != nil {
@@ -251,7 +274,7 @@ func (r *Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plu
 	wasiConfig.SetStderrFile(stderrPath)
 
 	keys := []string{"SQLC_VERSION"}
-	vals := []string{req.SqlcVersion}
+	vals := []string{version}
 	for _, key := range r.Env {
 		keys = append(keys, key)
 		vals = append(vals, os.Getenv(key))
@@ -287,9 +310,7 @@ func (r *Runner) Generate(ctx context.Context, req *plugin.CodeGenRequest) (*plu
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
-
-	var resp plugin.CodeGenResponse
-	return &resp, resp.UnmarshalVT(stdoutBlob)
+	return stdoutBlob, nil
 }
 
 func checkError(err error, stderrPath string) error {
@@ -0,0 +1,281 @@
+package introspect
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// A File is written by sqlc introspect, relative to its output directory.
+type File struct {
+	Path     string
+	Contents string
+}
+
+var serialTypes = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+// Files returns a schema file per table and view of db, in a directory named
+// after it, and a sqlc.yaml with a package per schema file. Partitions are
+// written with their partitioned table, and enums with the first table that
+// uses them. The schema of a package lists its own file first, followed by
+// the files it depends on, each after the files that depend on it.
+func (db *Database) Files() []File {
+	rels := map[uint32]*Relation{}
+	partitions := map[uint32][]*Relation{}
+	var tops []*Relation
+	for _, r := range db.Relations {
+		rels[r.OID] = r
+	}
+	for _, r := range db.Relations {
+		if _, ok := rels[r.Parent]; ok && r.Parent != 0 {
+			partitions[r.Parent] = append(partitions[r.Parent], r)
+			continue
+		}
+		tops = append(tops, r)
+	}
+	// family returns the partitions of r, and theirs, each after its
+	// partitioned table.
+	var family func(r *Relation) []*Relation
+	family = func(r *Relation) []*Relation {
+		var out []*Relation
+		for _, p := range partitions[r.OID] {
+			out = append(out, p)
+			out = append(out, family(p)...)
+		}
+		return out
+	}
+	// top returns the relation whose schema file holds r.
+	top := func(oid uint32) *Relation {
+		r, ok := rels[oid]
+		for ok && r.Parent != 0 {
+			parent, found := rels[r.Parent]
+			if !found {
+				break
+			}
+			r = parent
+		}
+		return r
+	}
+
+	enums := map[uint32]*Enum{}
+	for _, e := range db.Enums {
+		enums[e.OID] = e
+	}
+	owners := map[uint32]*Relation{}
+	owned := map[*Relation][]*Enum{}
+	for _, r := range tops {
+		for _, col := range r.Columns {
+			e, ok := enums[col.TypeOID]
+			if _, taken := owners[col.TypeOID]; !ok || taken {
+				continue
+			}
+			owners[col.TypeOID] = r
+			owned[r] = append(owned[r], e)
+		}
+	}
+
+	deps := map[*Relation][]*Relation{}
+	for _, r := range tops {
+		seen := map[*Relation]bool{r: true}
+		add := func(dep *Relation) {
+			if dep != nil && !seen[dep] {
+				seen[dep] = true
+				deps[r] = append(deps[r], dep)
+			}
+		}
+		for _, rel := range append([]*Relation{r}, family(r)...) {
+			for _, col := range rel.Columns {
+				add(owners[col.TypeOID])
+			}
+			for _, c := range rel.Constraints {
+				if c.Ref != 0 {
+					add(top(c.Ref))
+				}
+			}
+			for _, ref := range rel.Refs {
+				add(top(ref))
+			}
+		}
+		sort.Slice(deps[r], func(i, j int) bool {
+			return db.dir(deps[r][i]) < db.dir(deps[r][j])
+		})
+	}
+
+	var files []File
+	var config strings.Builder
+	config.WriteString("version: '2'\nsql:\n")
+	sort.Slice(tops, func(i, j int) bool {
+		return db.dir(tops[i]) < db.dir(tops[j])
+	})
+	for _, r := range tops {
+		dir := db.dir(r)
+		files = append(files, File{
+			Path:     path.Join(dir, "schema.sql"),
+			Contents: db.schemaFile(r, owned[r], family(r), rels),
+		})
+
+		// The dependencies are listed after the files that depend on them,
+		// see compiler.ParseCatalog.
+		var order []*Relation
+		visited := map[*Relation]bool{r: true}
+		var visit func(*Relation)
+		visit = func(rel *Relation) {
+			if visited[rel] {
+				return
+			}
+			visited[rel] = true
+			for _, dep := range deps[rel] {
+				visit(dep)
+			}
+			order = append(order, rel)
+		}
+		for _, dep := range deps[r] {
+			visit(dep)
+		}
+		config.WriteString("  - schema:\n")
+		fmt.Fprintf(&config, "      - %s/schema.sql\n", dir)
+		for i := len(order) - 1; i >= 0; i-- {
+			fmt.Fprintf(&config, "      - %s/schema.sql\n", db.dir(order[i]))
+		}
+		fmt.Fprintf(&config, "    queries: %s/query.sql\n", dir)
+		config.WriteString("    engine: postgresql\n")
+		config.WriteString("    gen:\n      go:\n        sql_package: wpgx\n")
+		fmt.Fprintf(&config, "        package: %s\n        out: %s\n", dir, dir)
+	}
+	return append(files, File{Path: "sqlc.yaml", Contents: config.String()})
+}
+
+// dir returns the directory, and the Go package, of the schema file of r.
+func (db *Database) dir(r *Relation) string {
+	name := r.Name
+	if r.Schema != db.DefaultSchema {
+		name = r.Schema + "_" + name
+	}
+	dir := []byte(strings.ToLower(name))
+	for i, c := range dir {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			dir[i] = '_'
+		}
+	}
+	if len(dir) == 0 || (dir[0] >= '0' && dir[0] <= '9') {
+		return "t_" + string(dir)
+	}
+	return string(dir)
+}
+
+func (db *Database) schemaFile(r *Relation, enums []*Enum, partitions []*Relation, rels map[uint32]*Relation) string {
+	var b strings.Builder
+	idents := []string{r.SchemaIdent}
+	for _, e := range enums {
+		idents = append(idents, e.SchemaIdent)
+	}
+	schemas := map[string]bool{}
+	for _, ident := range idents {
+		if ident != "" && !schemas[ident] {
+			schemas[ident] = true
+			fmt.Fprintf(&b, "CREATE SCHEMA IF NOT EXISTS %s;\n\n", ident)
+		}
+	}
+	for _, e := range enums {
+		vals := make([]string, len(e.Vals))
+		for i, v := range e.Vals {
+			vals[i] = "    '" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+		fmt.Fprintf(&b, "CREATE TYPE %s AS ENUM (\n%s\n);\n\n", e.Ident, strings.Join(vals, ",\n"))
+	}
+
+	switch r.Kind {
+	case "v":
+		fmt.Fprintf(&b, "CREATE OR REPLACE VIEW %s AS\n%s;\n", r.Ident, r.ViewDef)
+	case "m":
+		fmt.Fprintf(&b, "CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS\n%s;\n", r.Ident, r.ViewDef)
+	default:
+		fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n%s\n)", r.Ident, strings.Join(tableElements(r), ",\n"))
+		if r.PartitionKey != "" {
+			fmt.Fprintf(&b, " PARTITION BY %s", r.PartitionKey)
+		}
+		b.WriteString(";\n")
+	}
+
+	for _, p := range partitions {
+		fmt.Fprintf(&b, "\nCREATE TABLE IF NOT EXISTS %s PARTITION OF %s\n    %s", p.Ident, rels[p.Parent].Ident, p.Bound)
+		if p.PartitionKey != "" {
+			fmt.Fprintf(&b, "\n    PARTITION BY %s", p.PartitionKey)
+		}
+		b.WriteString(";\n")
+	}
+
+	var indexes []string
+	for _, rel := range append([]*Relation{r}, partitions...) {
+		for _, def := range rel.Indexes {
+			indexes = append(indexes, indexStmt(rel, def))
+		}
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n")
+		for _, stmt := range indexes {
+			b.WriteString(stmt + ";\n")
+		}
+	}
+	return b.String()
+}
+
+// tableElements returns the column definitions and constraints of a table,
+// with the column types aligned.
+func tableElements(r *Relation) []string {
+	nameWidth, typeWidth := 0, 0
+	types := make([]string, len(r.Columns))
+	for i, col := range r.Columns {
+		types[i] = col.Type
+		if serial, ok := serialTypes[col.Type]; ok && strings.HasPrefix(col.Default, "nextval(") {
+			types[i] = serial
+		}
+		nameWidth = max(nameWidth, len(col.Ident))
+		typeWidth = max(typeWidth, len(types[i]))
+	}
+	var elems []string
+	for i, col := range r.Columns {
+		var attrs []string
+		switch {
+		case col.Identity == "a":
+			attrs = append(attrs, "GENERATED ALWAYS AS IDENTITY")
+		case col.Identity == "d":
+			attrs = append(attrs, "GENERATED BY DEFAULT AS IDENTITY")
+		case col.Generated == "s":
+			attrs = append(attrs, "GENERATED ALWAYS AS ("+col.Default+") STORED")
+		case col.Default != "" && types[i] == col.Type:
+			attrs = append(attrs, "DEFAULT "+col.Default)
+		}
+		if col.NotNull {
+			attrs = append(attrs, "NOT NULL")
+		}
+		elem := fmt.Sprintf("    %-*s %s", nameWidth, col.Ident, types[i])
+		if len(attrs) > 0 {
+			elem = fmt.Sprintf("    %-*s %-*s %s", nameWidth, col.Ident, typeWidth, types[i], strings.Join(attrs, " "))
+		}
+		elems = append(elems, elem)
+	}
+	for _, c := range r.Constraints {
+		elems = append(elems, fmt.Sprintf("    CONSTRAINT %s %s", c.Ident, c.Def))
+	}
+	return elems
+}
+
+// indexStmt turns the definition PostgreSQL prints for an index of r into
+// the statement of a schema file.
+func indexStmt(r *Relation, def string) string {
+	for _, prefix := range []string{"CREATE INDEX ", "CREATE UNIQUE INDEX "} {
+		if strings.HasPrefix(def, prefix) {
+			def = prefix + "IF NOT EXISTS " + strings.TrimPrefix(def, prefix)
+			break
+		}
+	}
+	def = strings.Replace(def, " ON ONLY ", " ON ", 1)
+	def = strings.Replace(def, " ON "+r.qualified+" ", " ON "+r.Ident+" ", 1)
+	return strings.Replace(def, " USING btree (", " (", 1)
+}
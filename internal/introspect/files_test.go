@@ -0,0 +1,223 @@
+package introspect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/compiler"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+)
+
+func testDatabase() *Database {
+	return &Database{
+		DefaultSchema: "public",
+		Enums: []*Enum{
+			{OID: 100, Ident: "category", Vals: []string{"computer_science", "philosophy", "kid's"}},
+		},
+		Relations: []*Relation{
+			{
+				OID: 1, Schema: "public", Name: "books", Ident: "books", Kind: "r", qualified: "public.books",
+				Columns: []*Column{
+					{Ident: "id", Type: "bigint", Default: "nextval('books_id_seq'::regclass)", NotNull: true},
+					{Ident: "name", Type: "character varying(255)", NotNull: true},
+					{Ident: "category", Type: "category", TypeOID: 100, NotNull: true},
+					{Ident: "created_at", Type: "timestamp with time zone", Default: "now()", NotNull: true},
+				},
+				Constraints: []*Constraint{
+					{Ident: "books_id_pkey", Def: "PRIMARY KEY (id)"},
+				},
+				Indexes: []string{
+					"CREATE INDEX book_name_idx ON public.books USING btree (name)",
+					"CREATE UNIQUE INDEX book_category_id_idx ON public.books USING btree (category, id)",
+				},
+			},
+			{
+				OID: 2, Schema: "public", Name: "orders", Ident: "orders", Kind: "r", qualified: "public.orders",
+				Columns: []*Column{
+					{Ident: "id", Type: "integer", Identity: "a", NotNull: true},
+					{Ident: "user_id", Type: "integer", NotNull: true},
+					{Ident: "book_id", Type: "bigint", NotNull: true},
+					{Ident: "price", Type: "numeric(10,2)", NotNull: true},
+				},
+				Constraints: []*Constraint{
+					{Ident: "orders_id_pkey", Def: "PRIMARY KEY (id)"},
+					{Ident: "orders_book_id_fkey", Def: "FOREIGN KEY (book_id) REFERENCES books(id)", Ref: 1},
+					{Ident: "orders_user_id_fkey", Def: "FOREIGN KEY (user_id) REFERENCES users(id)", Ref: 3},
+				},
+			},
+			{
+				OID: 3, Schema: "public", Name: "users", Ident: "users", Kind: "r", qualified: "public.users",
+				Columns: []*Column{
+					{Ident: "id", Type: "integer", Identity: "d", NotNull: true},
+					{Ident: "favorite", Type: "category[]", TypeOID: 100},
+				},
+				Constraints: []*Constraint{
+					{Ident: "users_id_pkey", Def: "PRIMARY KEY (id)"},
+				},
+			},
+			{
+				OID: 4, Schema: "public", Name: "by_book_revenues", Ident: "by_book_revenues", Kind: "m",
+				ViewDef: "SELECT books.id,\n    sum(orders.price) AS total\n   FROM books\n     LEFT JOIN orders ON books.id = orders.book_id\n  GROUP BY books.id",
+				Refs:    []uint32{1, 2},
+			},
+			{
+				OID: 5, Schema: "audit", SchemaIdent: "audit", Name: "Measurement", Ident: `audit."Measurement"`, Kind: "p", qualified: `audit."Measurement"`,
+				PartitionKey: "RANGE (logdate)",
+				Columns: []*Column{
+					{Ident: "city_id", Type: "integer", NotNull: true},
+					{Ident: "logdate", Type: "date", NotNull: true},
+				},
+				Indexes: []string{
+					`CREATE INDEX measurement_logdate_idx ON ONLY audit."Measurement" USING btree (logdate)`,
+				},
+			},
+			{
+				OID: 6, Schema: "audit", SchemaIdent: "audit", Name: "measurement_y2006m02", Ident: "audit.measurement_y2006m02", Kind: "r", Parent: 5,
+				Bound: "FOR VALUES FROM ('2006-02-01') TO ('2006-03-01')",
+			},
+		},
+	}
+}
+
+func TestFiles(t *testing.T) {
+	files := map[string]string{}
+	for _, f := range testDatabase().Files() {
+		files[f.Path] = f.Contents
+	}
+
+	books := `CREATE TYPE category AS ENUM (
+    'computer_science',
+    'philosophy',
+    'kid''s'
+);
+
+CREATE TABLE IF NOT EXISTS books (
+    id         bigserial                NOT NULL,
+    name       character varying(255)   NOT NULL,
+    category   category                 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT books_id_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS book_name_idx ON books (name);
+CREATE UNIQUE INDEX IF NOT EXISTS book_category_id_idx ON books (category, id);
+`
+	if diff := cmp.Diff(books, files["books/schema.sql"]); diff != "" {
+		t.Errorf("books/schema.sql differed (-want +got):\n%s", diff)
+	}
+
+	measurement := `CREATE SCHEMA IF NOT EXISTS audit;
+
+CREATE TABLE IF NOT EXISTS audit."Measurement" (
+    city_id integer NOT NULL,
+    logdate date    NOT NULL
+) PARTITION BY RANGE (logdate);
+
+CREATE TABLE IF NOT EXISTS audit.measurement_y2006m02 PARTITION OF audit."Measurement"
+    FOR VALUES FROM ('2006-02-01') TO ('2006-03-01');
+
+CREATE INDEX IF NOT EXISTS measurement_logdate_idx ON audit."Measurement" (logdate);
+`
+	if diff := cmp.Diff(measurement, files["audit_measurement/schema.sql"]); diff != "" {
+		t.Errorf("audit_measurement/schema.sql differed (-want +got):\n%s", diff)
+	}
+
+	conf := `version: '2'
+sql:
+  - schema:
+      - audit_measurement/schema.sql
+    queries: audit_measurement/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: audit_measurement
+        out: audit_measurement
+  - schema:
+      - books/schema.sql
+    queries: books/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: books
+        out: books
+  - schema:
+      - by_book_revenues/schema.sql
+      - orders/schema.sql
+      - users/schema.sql
+      - books/schema.sql
+    queries: by_book_revenues/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: by_book_revenues
+        out: by_book_revenues
+  - schema:
+      - orders/schema.sql
+      - users/schema.sql
+      - books/schema.sql
+    queries: orders/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: orders
+        out: orders
+  - schema:
+      - users/schema.sql
+      - books/schema.sql
+    queries: users/query.sql
+    engine: postgresql
+    gen:
+      go:
+        sql_package: wpgx
+        package: users
+        out: users
+`
+	if diff := cmp.Diff(conf, files["sqlc.yaml"]); diff != "" {
+		t.Errorf("sqlc.yaml differed (-want +got):\n%s", diff)
+	}
+
+	// Every package's schema files must build a catalog in the listed order.
+	dir := t.TempDir()
+	for path, contents := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blob, err := os.Open(filepath.Join(dir, "sqlc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+	parsed, err := config.ParseConfig(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sql := range parsed.SQL {
+		var schema []string
+		for _, s := range sql.Schema {
+			schema = append(schema, filepath.Join(dir, s))
+		}
+		c := compiler.NewCompiler(sql, config.CombinedSettings{})
+		if err := c.ParseCatalog(schema); err != nil {
+			if merr, ok := err.(*multierr.Error); ok {
+				for _, e := range merr.Errs() {
+					t.Errorf("%s:%d: %s", e.Filename, e.Line, e.Err)
+				}
+				continue
+			}
+			t.Errorf("%s: %s", sql.Schema[0], err)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package introspect reads the schema of an existing PostgreSQL database and
+// writes it as the schema files and configuration this fork expects: one
+// schema file per table or view, listed with the files it depends on.
+package introspect
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Database is the schema of a database, with the definitions PostgreSQL
+// prints for its constraints, indexes and views.
+type Database struct {
+	// DefaultSchema is the schema whose objects are written unqualified.
+	DefaultSchema string
+	Relations     []*Relation
+	Enums         []*Enum
+}
+
+type Relation struct {
+	OID    uint32
+	Schema string
+	Name   string
+	// SchemaIdent is the quoted name of the schema, empty for the default
+	// schema.
+	SchemaIdent string
+	// Ident is the quoted name of the relation, qualified unless it is in
+	// the default schema.
+	Ident string
+	// Kind is the relkind: "r" for tables, "p" for partitioned tables, "v"
+	// for views and "m" for materialized views.
+	Kind string
+	// Parent is the partitioned table of a partition, with its bound.
+	Parent       uint32
+	Bound        string
+	PartitionKey string
+	ViewDef      string
+	Columns      []*Column
+	Constraints  []*Constraint
+	Indexes      []string
+	// Refs are the relations a view selects from.
+	Refs []uint32
+
+	// qualified is the schema qualified name PostgreSQL prints in the
+	// definitions of indexes.
+	qualified string
+}
+
+type Column struct {
+	Ident   string
+	Type    string
+	TypeOID uint32
+	NotNull bool
+	Default string
+	// Identity is "a" for GENERATED ALWAYS and "d" for GENERATED BY DEFAULT
+	// identity columns.
+	Identity string
+	// Generated is "s" for stored generated columns, whose expression is
+	// Default.
+	Generated string
+}
+
+type Constraint struct {
+	Ident string
+	Def   string
+	// Ref is the table a foreign key references.
+	Ref uint32
+}
+
+type Enum struct {
+	OID         uint32
+	SchemaIdent string
+	Ident       string
+	Vals        []string
+}
+
+const systemSchemas = `n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND n.nspname NOT LIKE 'pg_temp%'`
+
+const relationsQuery = `SELECT c.oid, n.nspname, c.relname, quote_ident(n.nspname), quote_ident(c.relname),
+  c.relkind::text, COALESCE(i.inhparent, 0), COALESCE(pg_get_expr(c.relpartbound, c.oid), ''),
+  CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) ELSE '' END,
+  CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) ELSE '' END
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_inherits i ON i.inhrelid = c.oid AND c.relispartition
+WHERE c.relkind IN ('r', 'p', 'v', 'm')
+  AND ` + systemSchemas + `
+ORDER BY n.nspname, c.relname`
+
+const columnsQuery = `SELECT a.attrelid, quote_ident(a.attname), format_type(a.atttypid, a.atttypmod),
+  COALESCE(e.oid, t.oid), a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+  a.attidentity::text, a.attgenerated::text
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_type e ON e.oid = t.typelem AND t.typcategory = 'A'
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+  AND ` + systemSchemas + `
+ORDER BY a.attrelid, a.attnum`
+
+// Constraints are listed as pg_dump orders them in CREATE TABLE, starting
+// with the primary key. The ones partitions inherit are left out.
+const constraintsQuery = `SELECT c.conrelid, quote_ident(c.conname), pg_get_constraintdef(c.oid, true), c.confrelid
+FROM pg_constraint c
+JOIN pg_class r ON r.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = r.relnamespace
+WHERE c.contype IN ('p', 'u', 'f', 'c', 'x') AND c.conislocal
+  AND ` + systemSchemas + `
+ORDER BY c.conrelid, array_position(ARRAY['p', 'u', 'x', 'f', 'c'], c.contype::text), c.conname`
+
+// Indexes that back constraints are created with the constraint, and the
+// indexes of partitions with the index of their partitioned table.
+const indexesQuery = `SELECT x.indrelid, pg_get_indexdef(x.indexrelid)
+FROM pg_index x
+JOIN pg_class i ON i.oid = x.indexrelid
+JOIN pg_class c ON c.oid = x.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conindid = x.indexrelid)
+  AND NOT i.relispartition
+  AND ` + systemSchemas + `
+ORDER BY x.indrelid, i.relname`
+
+const viewRefsQuery = `SELECT DISTINCT r.ev_class, d.refobjid
+FROM pg_depend d
+JOIN pg_rewrite r ON r.oid = d.objid
+WHERE d.classid = 'pg_rewrite'::regclass AND d.refclassid = 'pg_class'::regclass
+  AND d.refobjid <> r.ev_class
+ORDER BY 1, 2`
+
+const enumsQuery = `SELECT t.oid, n.nspname, quote_ident(n.nspname), quote_ident(t.typname),
+  array_agg(e.enumlabel ORDER BY e.enumsortorder)
+FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+JOIN pg_enum e ON e.enumtypid = t.oid
+WHERE ` + systemSchemas + `
+GROUP BY t.oid, n.nspname, t.typname
+ORDER BY n.nspname, t.typname`
+
+// Load reads the tables, views, enums, constraints and indexes of the
+// database conn is connected to.
+func Load(ctx context.Context, conn *pgx.Conn) (*Database, error) {
+	db := &Database{}
+	if err := conn.QueryRow(ctx, "SELECT current_schema()").Scan(&db.DefaultSchema); err != nil {
+		return nil, err
+	}
+	qualify := func(schema, quotedSchema, quotedName string) string {
+		if schema == db.DefaultSchema {
+			return quotedName
+		}
+		return quotedSchema + "." + quotedName
+	}
+
+	rels := map[uint32]*Relation{}
+	rows, err := conn.Query(ctx, relationsQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var r Relation
+		var quotedSchema, quotedName string
+		if err := rows.Scan(&r.OID, &r.Schema, &r.Name, &quotedSchema, &quotedName, &r.Kind, &r.Parent, &r.Bound, &r.PartitionKey, &r.ViewDef); err != nil {
+			return nil, err
+		}
+		r.Ident = qualify(r.Schema, quotedSchema, quotedName)
+		r.qualified = quotedSchema + "." + quotedName
+		if r.Schema != db.DefaultSchema {
+			r.SchemaIdent = quotedSchema
+		}
+		r.ViewDef = strings.TrimSuffix(strings.TrimSpace(r.ViewDef), ";")
+		rels[r.OID] = &r
+		db.Relations = append(db.Relations, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, columnsQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var oid uint32
+		var col Column
+		if err := rows.Scan(&oid, &col.Ident, &col.Type, &col.TypeOID, &col.NotNull, &col.Default, &col.Identity, &col.Generated); err != nil {
+			return nil, err
+		}
+		if r, ok := rels[oid]; ok {
+			r.Columns = append(r.Columns, &col)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, constraintsQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var oid uint32
+		var c Constraint
+		if err := rows.Scan(&oid, &c.Ident, &c.Def, &c.Ref); err != nil {
+			return nil, err
+		}
+		if r, ok := rels[oid]; ok {
+			r.Constraints = append(r.Constraints, &c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, indexesQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var oid uint32
+		var def string
+		if err := rows.Scan(&oid, &def); err != nil {
+			return nil, err
+		}
+		if r, ok := rels[oid]; ok {
+			r.Indexes = append(r.Indexes, def)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, viewRefsQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var view, ref uint32
+		if err := rows.Scan(&view, &ref); err != nil {
+			return nil, err
+		}
+		if r, ok := rels[view]; ok {
+			if _, ok := rels[ref]; ok {
+				r.Refs = append(r.Refs, ref)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx, enumsQuery)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var e Enum
+		var schema, quotedSchema, quotedName string
+		if err := rows.Scan(&e.OID, &schema, &quotedSchema, &quotedName, &e.Vals); err != nil {
+			return nil, err
+		}
+		e.Ident = qualify(schema, quotedSchema, quotedName)
+		if schema != db.DefaultSchema {
+			e.SchemaIdent = quotedSchema
+		}
+		db.Enums = append(db.Enums, &e)
+	}
+	return db, rows.Err()
+}
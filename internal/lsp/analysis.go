@@ -0,0 +1,551 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/golang"
+	"github.com/sqlc-dev/sqlc/internal/compiler"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+	"github.com/sqlc-dev/sqlc/internal/multierr"
+	"github.com/sqlc-dev/sqlc/internal/opts"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+	"github.com/sqlc-dev/sqlc/internal/sql/sqlpath"
+)
+
+var errNotAQueryFile = errors.New("file is not part of any queries path")
+
+// analysis is the result of compiling one query document against the schema
+// of the sql block it belongs to.
+type analysis struct {
+	sql         config.SQL
+	compiler    *compiler.Compiler
+	queries     []*compiler.Query
+	signatures  map[string]golang.Signature
+	diagnostics []Diagnostic
+}
+
+func joinPaths(dir string, paths []string) []string {
+	joined := make([]string, 0, len(paths))
+	for _, p := range paths {
+		joined = append(joined, filepath.Join(dir, p))
+	}
+	return joined
+}
+
+// schema is the compiled schema of a sql block. The compiler is shared by
+// the analyses of its query files, which don't modify its catalog.
+type schema struct {
+	compiler *compiler.Compiler
+	err      error
+}
+
+// findPackage returns the index of the sql block whose queries include path,
+// and the block with all paths made absolute.
+func (s *Server) findPackage(path string) (int, config.SQL, bool) {
+	for i, sql := range s.conf.SQL {
+		queries := joinPaths(s.dir, sql.Queries)
+		files, err := sqlpath.Glob(queries)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if filepath.Clean(f) == path {
+				sql.Queries = queries
+				sql.Schema = joinPaths(s.dir, sql.Schema)
+				return i, sql, true
+			}
+		}
+	}
+	return 0, config.SQL{}, false
+}
+
+// schema returns the compiled schema of the sql block at index i, compiling
+// it on first use.
+func (s *Server) schema(i int, sql config.SQL, combo config.CombinedSettings) *schema {
+	if cached, ok := s.schemas[i]; ok {
+		return cached
+	}
+	c := compiler.NewCompiler(sql, combo)
+	compiled := &schema{compiler: c, err: c.ParseCatalog(sql.Schema)}
+	s.schemas[i] = compiled
+	return compiled
+}
+
+// invalidateSchema drops the compiled schemas of the sql blocks that path is
+// a schema file of.
+func (s *Server) invalidateSchema(path string) {
+	for i, sql := range s.conf.SQL {
+		files, err := sqlpath.Glob(joinPaths(s.dir, sql.Schema))
+		if err != nil {
+			delete(s.schemas, i)
+			continue
+		}
+		for _, f := range files {
+			if filepath.Clean(f) == path {
+				delete(s.schemas, i)
+			}
+		}
+	}
+}
+
+func (s *Server) analyze(path, text string) (a *analysis, err error) {
+	i, sql, ok := s.findPackage(path)
+	if !ok {
+		return nil, errNotAQueryFile
+	}
+	// A panic while compiling a half-typed query must not take down the
+	// editor session.
+	defer func() {
+		if r := recover(); r != nil {
+			a, err = nil, fmt.Errorf("internal compiler error: %v", r)
+		}
+	}()
+
+	combo := config.Combine(*s.conf, sql)
+	compiled := s.schema(i, sql, combo)
+	c := compiled.compiler
+	a = &analysis{
+		sql:        sql,
+		compiler:   c,
+		signatures: map[string]golang.Signature{},
+	}
+	if compiled.err != nil {
+		a.diagnostics = append(a.diagnostics, s.schemaDiagnostics(compiled.err)...)
+		return a, nil
+	}
+	queries, err := c.ParseQuerySource(path, text, opts.Parser{})
+	a.queries = queries
+	if err != nil {
+		a.diagnostics = append(a.diagnostics, queryDiagnostics(text, err)...)
+	}
+	if s.request != nil && sql.Gen.Go != nil && len(queries) > 0 {
+		req := s.request(&compiler.Result{Catalog: c.Catalog(), Queries: queries}, combo)
+		if sigs, err := golang.Signatures(req); err == nil {
+			for _, sig := range sigs {
+				a.signatures[sig.Name] = sig
+			}
+		}
+	}
+	return a, nil
+}
+
+// schemaDiagnostics reports schema errors at the top of the query file, as
+// they cannot be attributed to a position in it.
+func (s *Server) schemaDiagnostics(err error) []Diagnostic {
+	var merr *multierr.Error
+	if !errors.As(err, &merr) {
+		return []Diagnostic{{Severity: severityError, Source: "sqlc", Message: "error parsing schema: " + err.Error()}}
+	}
+	var diags []Diagnostic
+	for _, fileErr := range merr.Errs() {
+		filename, err := filepath.Rel(s.dir, fileErr.Filename)
+		if err != nil {
+			filename = fileErr.Filename
+		}
+		diags = append(diags, Diagnostic{
+			Severity: severityError,
+			Source:   "sqlc",
+			Message:  fmt.Sprintf("%s:%d:%d: %s", filename, fileErr.Line, fileErr.Column, fileErr.Err),
+		})
+	}
+	return diags
+}
+
+func queryDiagnostics(text string, err error) []Diagnostic {
+	var merr *multierr.Error
+	if !errors.As(err, &merr) {
+		return []Diagnostic{{Severity: severityError, Source: "sqlc", Message: err.Error()}}
+	}
+	var diags []Diagnostic
+	for _, fileErr := range merr.Errs() {
+		start := Position{Line: fileErr.Line - 1, Character: fileErr.Column - 1}
+		if start.Line < 0 {
+			start.Line = 0
+		}
+		if start.Character < 0 {
+			start.Character = 0
+		}
+		offset := offsetOf(text, start)
+		_, end := identAt(text, offset)
+		if end == offset {
+			end = offset + 1
+		}
+		diags = append(diags, Diagnostic{
+			Range:    Range{Start: positionOf(text, offset), End: positionOf(text, end)},
+			Severity: severityError,
+			Source:   "sqlc",
+			Message:  fileErr.Err.Error(),
+		})
+	}
+	return diags
+}
+
+func (s *Server) hover(p TextDocumentPositionParams) (*Hover, error) {
+	text, err := s.text(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.analyze(uriToPath(p.TextDocument.URI), text)
+	if err != nil {
+		return nil, nil
+	}
+	offset := offsetOf(text, p.Position)
+	for _, q := range a.queries {
+		start := q.RawStmt.StmtLocation
+		end := start + q.RawStmt.StmtLen
+		if offset < start || offset > end || q.Name == "" {
+			continue
+		}
+		sig, ok := a.signatures[q.Name]
+		var value string
+		if ok {
+			value = goHover(q, sig)
+		} else {
+			value = sqlHover(q)
+		}
+		return &Hover{
+			Contents: MarkupContent{Kind: markupKindMarkdown, Value: value},
+			Range:    &Range{Start: positionOf(text, start), End: positionOf(text, end)},
+		}, nil
+	}
+	return nil, nil
+}
+
+func goHover(q *compiler.Query, sig golang.Signature) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** `%s`\n\n```go\n", q.Name, q.Cmd)
+	var params []string
+	for _, arg := range sig.Params {
+		params = append(params, arg.Name+" "+arg.Type)
+	}
+	fmt.Fprintf(&b, "// params\n(%s)\n", strings.Join(params, ", "))
+	if sig.Ret != "" {
+		fmt.Fprintf(&b, "// returns\n%s\n", sig.Ret)
+	}
+	if len(sig.RetFields) > 0 {
+		fmt.Fprintf(&b, "\ntype %s struct {\n", strings.TrimPrefix(sig.Ret, "*"))
+		for _, f := range sig.RetFields {
+			fmt.Fprintf(&b, "\t%s %s\n", f.Name, f.Type)
+		}
+		b.WriteString("}\n")
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+func sqlHover(q *compiler.Query) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** `%s`\n\n```sql\n", q.Name, q.Cmd)
+	b.WriteString("-- params\n")
+	for _, p := range q.Params {
+		fmt.Fprintf(&b, "$%d %s %s\n", p.Number, p.Column.Name, sqlType(p.Column))
+	}
+	if len(q.Columns) > 0 {
+		b.WriteString("-- columns\n")
+		for _, c := range q.Columns {
+			fmt.Fprintf(&b, "%s %s\n", c.Name, sqlType(c))
+		}
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+func sqlType(c *compiler.Column) string {
+	typ := c.DataType
+	if c.IsArray {
+		typ += strings.Repeat("[]", max(c.ArrayDims, 1))
+	}
+	if c.NotNull {
+		typ += " not null"
+	}
+	return typ
+}
+
+func (s *Server) completion(p TextDocumentPositionParams) (*CompletionList, error) {
+	list := &CompletionList{Items: []CompletionItem{}}
+	text, err := s.text(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.analyze(uriToPath(p.TextDocument.URI), text)
+	if err != nil {
+		return list, nil
+	}
+	offset := offsetOf(text, p.Position)
+	start, _ := identAt(text, offset)
+	tables := userTables(a.compiler.Catalog())
+
+	if start > 0 && text[start-1] == '.' {
+		qualStart, _ := identAt(text, start-1)
+		qualifier := text[qualStart : start-1]
+		if name, ok := statementTables(text, offset)[strings.ToLower(qualifier)]; ok {
+			qualifier = name
+		}
+		for _, t := range tables {
+			if !strings.EqualFold(t.Rel.Name, qualifier) {
+				continue
+			}
+			for _, c := range t.Columns {
+				list.Items = append(list.Items, columnItem(t, c))
+			}
+		}
+		return list, nil
+	}
+
+	seen := map[string]bool{}
+	for _, t := range tables {
+		list.Items = append(list.Items, CompletionItem{
+			Label:  t.Rel.Name,
+			Kind:   completionKindClass,
+			Detail: "table",
+		})
+		for _, c := range t.Columns {
+			if seen[c.Name] {
+				continue
+			}
+			seen[c.Name] = true
+			list.Items = append(list.Items, columnItem(t, c))
+		}
+	}
+	return list, nil
+}
+
+func columnItem(t *catalog.Table, c *catalog.Column) CompletionItem {
+	typ := c.Type.Name
+	if c.IsArray {
+		typ += "[]"
+	}
+	if c.IsNotNull {
+		typ += " not null"
+	}
+	return CompletionItem{
+		Label:  c.Name,
+		Kind:   completionKindField,
+		Detail: fmt.Sprintf("%s.%s %s", t.Rel.Name, c.Name, typ),
+	}
+}
+
+func userTables(c *catalog.Catalog) []*catalog.Table {
+	var tables []*catalog.Table
+	for _, schema := range c.Schemas {
+		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
+			continue
+		}
+		tables = append(tables, schema.Tables...)
+	}
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].Rel.Name < tables[j].Rel.Name })
+	return tables
+}
+
+func (s *Server) definition(p TextDocumentPositionParams) ([]Location, error) {
+	text, err := s.text(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.analyze(uriToPath(p.TextDocument.URI), text)
+	if err != nil {
+		return nil, nil
+	}
+	offset := offsetOf(text, p.Position)
+	start, end := identAt(text, offset)
+	if start == end {
+		return nil, nil
+	}
+	name := text[start:end]
+	aliases := statementTables(text, offset)
+	defs, err := schemaDefinitions(a)
+	if err != nil {
+		return nil, err
+	}
+
+	// The identifier is a qualifier, e.g. the `b` in `b.title`.
+	if end < len(text) && text[end] == '.' {
+		if table, ok := aliases[strings.ToLower(name)]; ok {
+			name = table
+		}
+		return defs.tableLocations(name), nil
+	}
+	if start > 0 && text[start-1] == '.' {
+		qualStart, _ := identAt(text, start-1)
+		qualifier := text[qualStart : start-1]
+		if table, ok := aliases[strings.ToLower(qualifier)]; ok {
+			qualifier = table
+		}
+		return defs.columnLocations([]string{qualifier}, name), nil
+	}
+	if locs := defs.tableLocations(name); len(locs) > 0 {
+		return locs, nil
+	}
+	var referenced []string
+	for _, table := range aliases {
+		referenced = append(referenced, table)
+	}
+	if locs := defs.columnLocations(referenced, name); len(locs) > 0 {
+		return locs, nil
+	}
+	return defs.columnLocations(nil, name), nil
+}
+
+type definition struct {
+	path   string
+	text   string
+	offset int
+	name   string
+}
+
+func (d definition) location() Location {
+	return Location{
+		URI: pathToURI(d.path),
+		Range: Range{
+			Start: positionOf(d.text, d.offset),
+			End:   positionOf(d.text, d.offset+len(d.name)),
+		},
+	}
+}
+
+type tableDefinition struct {
+	definition
+	columns []definition
+}
+
+type definitions []tableDefinition
+
+func (defs definitions) tableLocations(name string) []Location {
+	var locs []Location
+	for _, t := range defs {
+		if strings.EqualFold(t.name, name) {
+			locs = append(locs, t.location())
+		}
+	}
+	return locs
+}
+
+// columnLocations finds the definitions of column name. If tables is not
+// empty, only those tables are searched.
+func (defs definitions) columnLocations(tables []string, name string) []Location {
+	var locs []Location
+	for _, t := range defs {
+		if len(tables) > 0 && !containsFold(tables, t.name) {
+			continue
+		}
+		for _, c := range t.columns {
+			if strings.EqualFold(c.name, name) {
+				locs = append(locs, c.location())
+			}
+		}
+	}
+	return locs
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaDefinitions parses the schema files of the package and records where
+// each table and column is created.
+func schemaDefinitions(a *analysis) (definitions, error) {
+	files, err := sqlpath.Glob(a.sql.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var defs definitions
+	for _, filename := range files {
+		blob, err := os.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		contents := migrations.RemoveRollbackStatements(string(blob))
+		stmts, err := a.compiler.Parser().Parse(strings.NewReader(contents))
+		if err != nil {
+			continue
+		}
+		for _, stmt := range stmts {
+			create, ok := stmt.Raw.Stmt.(*ast.CreateTableStmt)
+			if !ok || create.Name == nil {
+				continue
+			}
+			raw := contents[stmt.Raw.StmtLocation : stmt.Raw.StmtLocation+stmt.Raw.StmtLen]
+			offset := stmt.Raw.StmtLocation
+			if i := strings.Index(strings.ToLower(raw), strings.ToLower(create.Name.Name)); i >= 0 {
+				offset += i
+			}
+			t := tableDefinition{
+				definition: definition{path: filename, text: contents, offset: offset, name: create.Name.Name},
+			}
+			for _, col := range create.Cols {
+				loc := col.Location
+				if loc == 0 {
+					loc = offset
+				}
+				t.columns = append(t.columns, definition{path: filename, text: contents, offset: loc, name: col.Colname})
+			}
+			defs = append(defs, t)
+		}
+	}
+	return defs, nil
+}
+
+var tableRefPattern = regexp.MustCompile(`(?i)\b(?:from|join|update|into)\s+(?:only\s+)?((?:[a-z_][a-z0-9_]*\.)?[a-z_][a-z0-9_]*)(?:\s+(?:as\s+)?([a-z_][a-z0-9_]*))?`)
+
+var notAnAlias = map[string]bool{
+	"where": true, "on": true, "using": true, "set": true, "join": true,
+	"left": true, "right": true, "inner": true, "full": true, "cross": true,
+	"natural": true, "group": true, "order": true, "limit": true, "offset": true,
+	"returning": true, "values": true, "select": true, "default": true,
+	"union": true, "for": true, "having": true, "window": true, "as": true,
+}
+
+// statementTables maps the lower-cased names and aliases of the tables
+// referenced by the statement around offset to their table names.
+func statementTables(text string, offset int) map[string]string {
+	start := strings.LastIndexByte(text[:offset], ';') + 1
+	end := len(text)
+	if i := strings.IndexByte(text[offset:], ';'); i >= 0 {
+		end = offset + i
+	}
+	tables := map[string]string{}
+	for _, m := range tableRefPattern.FindAllStringSubmatch(text[start:end], -1) {
+		name := m[1]
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[i+1:]
+		}
+		tables[strings.ToLower(name)] = name
+		if alias := strings.ToLower(m[2]); alias != "" && !notAnAlias[alias] {
+			tables[alias] = name
+		}
+	}
+	return tables
+}
+
+func isIdentChar(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+// identAt returns the bounds of the identifier touching offset.
+func identAt(text string, offset int) (int, int) {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	start := offset
+	for start > 0 && isIdentChar(text[start-1]) {
+		start--
+	}
+	end := offset
+	for end < len(text) && isIdentChar(text[end]) {
+		end++
+	}
+	return start, end
+}
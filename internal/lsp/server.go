@@ -0,0 +1,211 @@
+// Package lsp implements a language server for sqlc query files. It reuses
+// the compiler to report diagnostics and to answer hover, completion and
+// go-to-definition requests against the configured schema.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sqlc-dev/sqlc/internal/compiler"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/info"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+)
+
+// RequestFunc builds the code generation request for a compiled package. It
+// is used to infer the Go types shown on hover. It may be nil, in which case
+// only SQL types are shown.
+type RequestFunc func(*compiler.Result, config.CombinedSettings) *plugin.CodeGenRequest
+
+type Server struct {
+	dir     string
+	conf    *config.Config
+	request RequestFunc
+
+	conn *conn
+	docs map[string]string
+	// schemas are the compiled schemas of the sql blocks, by index, until
+	// one of their files changes.
+	schemas  map[int]*schema
+	shutdown bool
+}
+
+func NewServer(dir string, conf *config.Config, request RequestFunc) *Server {
+	return &Server{
+		dir:     dir,
+		conf:    conf,
+		request: request,
+		docs:    map[string]string{},
+		schemas: map[int]*schema{},
+	}
+}
+
+// Serve handles messages from r until the client sends exit or r is closed.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		body, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req *request) error {
+	if req.ID == nil {
+		s.notify(req)
+		return nil
+	}
+	var result interface{}
+	var err error
+	switch req.Method {
+	case "initialize":
+		result = s.initialize()
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		result, err = s.hover(params)
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		result, err = s.completion(params)
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		result, err = s.definition(params)
+	default:
+		return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method))
+	}
+	if err != nil {
+		return s.replyError(req.ID, codeInternalError, err.Error())
+	}
+	return s.conn.write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func (s *Server) notify(req *request) {
+	switch req.Method {
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return
+		}
+		s.docs[params.TextDocument.URI] = params.TextDocument.Text
+		s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil || len(params.ContentChanges) == 0 {
+			return
+		}
+		// Only full document sync is advertised, so the last change holds
+		// the whole text.
+		s.docs[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.invalidateSchema(uriToPath(params.TextDocument.URI))
+		s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didSave":
+		var params DidCloseTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return
+		}
+		s.invalidateSchema(uriToPath(params.TextDocument.URI))
+		s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.conn.write(notification{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}},
+		})
+	}
+}
+
+func (s *Server) initialize() InitializeResult {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:   textDocumentSyncFull,
+			HoverProvider:      true,
+			CompletionProvider: CompletionOptions{TriggerCharacters: []string{"."}},
+			DefinitionProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: "sqlc", Version: info.Version},
+	}
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, msg string) error {
+	return s.conn.write(errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &responseError{Code: code, Message: msg},
+	})
+}
+
+// text returns the contents of the document, preferring the open buffer
+// over the file on disk.
+func (s *Server) text(uri string) (string, error) {
+	if text, ok := s.docs[uri]; ok {
+		return text, nil
+	}
+	blob, err := os.ReadFile(uriToPath(uri))
+	if err != nil {
+		return "", err
+	}
+	return string(blob), nil
+}
+
+func (s *Server) publishDiagnostics(uri string) {
+	text, err := s.text(uri)
+	if err != nil {
+		return
+	}
+	diags := []Diagnostic{}
+	a, err := s.analyze(uriToPath(uri), text)
+	if err == nil {
+		diags = append(diags, a.diagnostics...)
+	} else if err != errNotAQueryFile {
+		diags = append(diags, Diagnostic{
+			Severity: severityError,
+			Source:   "sqlc",
+			Message:  err.Error(),
+		})
+	}
+	s.conn.write(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  PublishDiagnosticsParams{URI: uri, Diagnostics: diags},
+	})
+}
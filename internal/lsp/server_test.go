@@ -0,0 +1,244 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/sqlc/internal/config"
+)
+
+const testSchema = `CREATE TABLE IF NOT EXISTS books (
+   id     BIGINT        NOT NULL,
+   title  VARCHAR(255)  NOT NULL,
+   CONSTRAINT books_id_pkey PRIMARY KEY (id)
+);
+`
+
+const testQuery = `-- name: GetBook :one
+-- -- timeout : 500ms
+SELECT * FROM books b WHERE b.id = @id;
+`
+
+func setup(t *testing.T) (string, *config.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "books"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"books/schema.sql": testSchema,
+		"books/query.sql":  testQuery,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conf := &config.Config{
+		Version: "2",
+		SQL: []config.SQL{{
+			Engine:  config.EnginePostgreSQL,
+			Schema:  config.Paths{"books/schema.sql"},
+			Queries: config.Paths{"books/query.sql"},
+		}},
+	}
+	return dir, conf
+}
+
+func frame(t *testing.T, msgs ...interface{}) *bytes.Buffer {
+	t.Helper()
+	var in bytes.Buffer
+	for _, msg := range msgs {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	return &in
+}
+
+func call(id int, method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params}
+}
+
+func notice(method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+}
+
+func readAll(t *testing.T, out *bytes.Buffer) []map[string]json.RawMessage {
+	t.Helper()
+	c := newConn(out, nil)
+	var msgs []map[string]json.RawMessage
+	for {
+		body, err := c.read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestServer(t *testing.T) {
+	dir, conf := setup(t)
+	uri := pathToURI(filepath.Join(dir, "books", "query.sql"))
+	broken := strings.Replace(testQuery, "b.id", "b.isbn", 1)
+	pos := func(line, char int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": map[string]string{"uri": uri},
+			"position":     map[string]int{"line": line, "character": char},
+		}
+	}
+
+	in := frame(t,
+		call(1, "initialize", map[string]interface{}{}),
+		notice("textDocument/didOpen", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "version": 1, "text": broken},
+		}),
+		notice("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+			"contentChanges": []map[string]string{{"text": testQuery}},
+		}),
+		call(2, "textDocument/hover", pos(2, 3)),
+		call(3, "textDocument/completion", pos(2, 30)),
+		call(4, "textDocument/definition", pos(2, 30)),
+		call(5, "shutdown", nil),
+		notice("exit", nil),
+	)
+	var out bytes.Buffer
+	if err := NewServer(dir, conf, nil).Serve(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+	msgs := readAll(t, &out)
+	if len(msgs) != 7 {
+		t.Fatalf("expected 7 messages, got %d", len(msgs))
+	}
+
+	var diags PublishDiagnosticsParams
+	if err := json.Unmarshal(msgs[1]["params"], &diags); err != nil {
+		t.Fatal(err)
+	}
+	if len(diags.Diagnostics) != 1 || !strings.Contains(diags.Diagnostics[0].Message, "isbn") {
+		t.Fatalf("unexpected diagnostics for broken query: %+v", diags)
+	}
+	if diags.Diagnostics[0].Range.Start.Line != 2 {
+		t.Errorf("diagnostic on line %d, expected 2", diags.Diagnostics[0].Range.Start.Line)
+	}
+	if err := json.Unmarshal(msgs[2]["params"], &diags); err != nil {
+		t.Fatal(err)
+	}
+	if len(diags.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics for fixed query: %+v", diags)
+	}
+
+	var hover Hover
+	if err := json.Unmarshal(msgs[3]["result"], &hover); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(hover.Contents.Value, "GetBook") || !strings.Contains(hover.Contents.Value, "title pg_catalog.varchar not null") {
+		t.Errorf("unexpected hover: %s", hover.Contents.Value)
+	}
+
+	var completion CompletionList
+	if err := json.Unmarshal(msgs[4]["result"], &completion); err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, item := range completion.Items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, ",") != "id,title" {
+		t.Errorf("unexpected completion after alias: %v", labels)
+	}
+
+	var locs []Location
+	if err := json.Unmarshal(msgs[5]["result"], &locs); err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 1 || !strings.HasSuffix(locs[0].URI, "books/schema.sql") || locs[0].Range.Start != (Position{Line: 1, Character: 3}) {
+		t.Errorf("unexpected definition: %+v", locs)
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	text := "ab\n€x😀y\n"
+	for _, tc := range []struct {
+		pos    Position
+		offset int
+	}{
+		{Position{0, 0}, 0},
+		{Position{0, 2}, 2},
+		{Position{1, 1}, 6},
+		{Position{1, 2}, 7},
+		{Position{1, 4}, 11},
+		{Position{2, 0}, 13},
+	} {
+		if got := offsetOf(text, tc.pos); got != tc.offset {
+			t.Errorf("offsetOf(%v) = %d, expected %d", tc.pos, got, tc.offset)
+		}
+		if got := positionOf(text, tc.offset); got != tc.pos {
+			t.Errorf("positionOf(%d) = %v, expected %v", tc.offset, got, tc.pos)
+		}
+	}
+}
+
+func TestSchemaInvalidation(t *testing.T) {
+	dir, conf := setup(t)
+	uri := pathToURI(filepath.Join(dir, "books", "query.sql"))
+	schemaPath := filepath.Join(dir, "books", "schema.sql")
+	pos := map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+		"position":     map[string]int{"line": 2, "character": 30},
+	}
+	srv := NewServer(dir, conf, nil)
+	complete := func(msgs ...interface{}) []string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := srv.Serve(context.Background(), frame(t, append(msgs, call(1, "textDocument/completion", pos))...), &out); err != nil {
+			t.Fatal(err)
+		}
+		replies := readAll(t, &out)
+		var completion CompletionList
+		if err := json.Unmarshal(replies[len(replies)-1]["result"], &completion); err != nil {
+			t.Fatal(err)
+		}
+		var labels []string
+		for _, item := range completion.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+
+	if got := strings.Join(complete(), ","); got != "id,title" {
+		t.Fatalf("unexpected completion: %s", got)
+	}
+	updated := strings.Replace(testSchema, "title  VARCHAR(255)  NOT NULL,", "title  VARCHAR(255)  NOT NULL,\n   year   INT,", 1)
+	if err := os.WriteFile(schemaPath, []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(complete(), ","); got != "id,title" {
+		t.Errorf("schema was compiled again before it was saved: %s", got)
+	}
+	saved := notice("textDocument/didSave", map[string]interface{}{
+		"textDocument": map[string]string{"uri": pathToURI(schemaPath)},
+	})
+	if got := strings.Join(complete(saved), ","); got != "id,title,year" {
+		t.Errorf("unexpected completion after the schema was saved: %s", got)
+	}
+}
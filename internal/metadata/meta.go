@@ -50,6 +50,49 @@ type QueryConfig struct {
 	Options map[string]string
 }
 
+// OptionColumn is the query option that hints the nullability or the Go type
+// of a result column, e.g. `-- -- column: total notnull` or
+// `-- -- column: meta type:github.com/acme/types.Meta`.
+const OptionColumn = "column"
+
+type ColumnHint struct {
+	Column   string
+	NotNull  bool
+	Nullable bool
+	GoType   string
+}
+
+// ColumnHintKey is the key of the query option holding the hint of a column.
+func ColumnHintKey(column string) string {
+	return OptionColumn + "." + column
+}
+
+// ParseColumnHint parses the value of a column option: a column name
+// followed by notnull, nullable and/or type:<go type>.
+func ParseColumnHint(s string) (*ColumnHint, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid column hint %q: expected a column name followed by notnull, nullable or type:<go type>", s)
+	}
+	hint := &ColumnHint{Column: fields[0]}
+	for _, field := range fields[1:] {
+		switch {
+		case field == "notnull":
+			hint.NotNull = true
+		case field == "nullable":
+			hint.Nullable = true
+		case strings.HasPrefix(field, "type:") && len(field) > len("type:"):
+			hint.GoType = field[len("type:"):]
+		default:
+			return nil, fmt.Errorf("invalid column hint %q: unknown attribute %q", s, field)
+		}
+	}
+	if hint.NotNull && hint.Nullable {
+		return nil, fmt.Errorf("invalid column hint %q: a column can't be both notnull and nullable", s)
+	}
+	return hint, nil
+}
+
 // Parse returns query name and the specified return type.
 func ParseQueryNameAndType(t string, commentStyle CommentSyntax) (*QueryConfig, error) {
 	config := &QueryConfig{
@@ -116,6 +159,16 @@ func ParseQueryNameAndType(t string, commentStyle CommentSyntax) (*QueryConfig,
 			}
 			key := strings.TrimSpace(body[:sepIndex])
 			val := strings.TrimSpace(body[sepIndex+1:])
+			if key == OptionColumn {
+				hint, err := ParseColumnHint(val)
+				if err != nil {
+					return nil, err
+				}
+				key = ColumnHintKey(hint.Column)
+				if _, ok := config.Options[key]; ok {
+					return nil, fmt.Errorf("duplicate hint for column %q: %s", hint.Column, line)
+				}
+			}
 			config.Options[key] = val
 		} else {
 			// to be consistent with previous logic: if comments start with name
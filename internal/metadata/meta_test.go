@@ -48,6 +48,35 @@ func TestParseQueryNameAndType(t *testing.T) {
 
 }
 
+func TestParseColumnHints(t *testing.T) {
+	query := "-- name: GetTotals :one\n-- -- column: total notnull\n-- -- column: meta nullable type:github.com/acme/types.Meta"
+	config, err := ParseQueryNameAndType(query, CommentSyntax{Dash: true})
+	if err != nil {
+		t.Fatalf("expected valid metadata: %s", err)
+	}
+	hint, err := ParseColumnHint(config.Options[ColumnHintKey("meta")])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hint.Nullable || hint.NotNull || hint.GoType != "github.com/acme/types.Meta" {
+		t.Errorf("incorrect column hint parsed: %+v", hint)
+	}
+	if _, ok := config.Options[ColumnHintKey("total")]; !ok {
+		t.Errorf("missing column hint for total: %v", config.Options)
+	}
+
+	for _, query := range []string{
+		"-- -- column: total",
+		"-- -- column: total notnul",
+		"-- -- column: total notnull nullable",
+		"-- -- column: total notnull\n-- -- column: total nullable",
+	} {
+		if _, err := ParseQueryNameAndType(query, CommentSyntax{Dash: true}); err == nil {
+			t.Errorf("expected invalid column hint: %q", query)
+		}
+	}
+}
+
 func TestParseQueryFlags(t *testing.T) {
 	for _, comments := range [][]string{
 		{
@@ -0,0 +1,372 @@
+package migrations
+
+import (
+	"strings"
+)
+
+// UnsupportedChange starts the lines of a migration that stand for changes
+// Diff can't write as statements.
+const UnsupportedChange = "-- unsupported change: "
+
+// maxIdentifierLength is the length PostgreSQL truncates identifiers to.
+const maxIdentifierLength = 63
+
+// constraintKeywords start a table constraint, or a constraint of a column
+// definition.
+var constraintKeywords = map[string]bool{
+	"CONSTRAINT": true,
+	"PRIMARY":    true,
+	"UNIQUE":     true,
+	"CHECK":      true,
+	"REFERENCES": true,
+	"FOREIGN":    true,
+	"EXCLUDE":    true,
+}
+
+// constraint is a PRIMARY KEY, UNIQUE, FOREIGN KEY, CHECK or EXCLUDE
+// constraint of a table, with its definition written as a table constraint.
+type constraint struct {
+	// name is the name PostgreSQL gives the constraint, empty when it can't
+	// be told from the definition.
+	name string
+	// named is true when the name is written in the schema.
+	named bool
+	def   string
+	// column is the column whose definition has the constraint, if any.
+	column string
+}
+
+// key identifies the constraint between two schemas.
+func (c constraint) key() string {
+	if c.name != "" {
+		return c.name
+	}
+	return "(" + c.compact() + ")"
+}
+
+// compact returns the definition without white space and in lower case, so
+// that definitions are compared regardless of their formatting.
+func (c constraint) compact() string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ", ",", " , ").Replace(c.def)), ""))
+}
+
+// add returns the clause of ALTER TABLE that adds the constraint.
+func (c constraint) add() string {
+	if c.named {
+		return "ADD CONSTRAINT " + c.name + " " + c.def
+	}
+	return "ADD " + c.def
+}
+
+// tableElements splits the text of a CREATE TABLE statement into the column
+// definitions and table constraints between its parentheses.
+func tableElements(stmt string) []string {
+	start := strings.IndexByte(stmt, '(')
+	if start < 0 {
+		return nil
+	}
+	return splitElements(stmt[start+1:])
+}
+
+// splitElements splits text at the commas outside of parentheses and quotes,
+// up to the parenthesis that closes the list, with comments removed.
+func splitElements(text string) []string {
+	var elems []string
+	var b strings.Builder
+	depth := 0
+	flush := func() {
+		if elem := normalize(b.String()); elem != "" {
+			elems = append(elems, elem)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := strings.IndexByte(text[i+1:], c)
+			if j < 0 {
+				b.WriteString(text[i:])
+				flush()
+				return elems
+			}
+			b.WriteString(text[i : i+j+2])
+			i += j + 1
+			continue
+		case strings.HasPrefix(text[i:], "--"):
+			j := strings.IndexByte(text[i:], '\n')
+			if j < 0 {
+				flush()
+				return elems
+			}
+			i += j
+			c = '\n'
+		case c == '(':
+			depth++
+		case c == ')' && depth == 0:
+			flush()
+			return elems
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			flush()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	flush()
+	return elems
+}
+
+// constraintTokens splits elem like columnTokens, with the keywords written
+// right before a parenthesis, as in CHECK(price > 0), split from it.
+func constraintTokens(elem string) []string {
+	var tokens []string
+	for _, token := range columnTokens(elem) {
+		i := strings.IndexByte(token, '(')
+		switch strings.ToUpper(token[:max(i, 0)]) {
+		case "CHECK", "KEY", "UNIQUE", "EXCLUDE":
+			tokens = append(tokens, token[:i], token[i:])
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// tableConstraint parses a table constraint of table, or returns false if
+// elem is a column definition.
+func tableConstraint(table, elem string) (constraint, bool) {
+	tokens := constraintTokens(elem)
+	if len(tokens) == 0 || !constraintKeywords[strings.ToUpper(tokens[0])] || strings.ToUpper(tokens[0]) == "REFERENCES" {
+		return constraint{}, false
+	}
+	var c constraint
+	if strings.ToUpper(tokens[0]) == "CONSTRAINT" {
+		if len(tokens) < 3 {
+			return constraint{}, false
+		}
+		c.name, c.named = tokens[1], true
+		tokens = tokens[2:]
+	}
+	c.def = strings.Join(tokens, " ")
+	if c.named {
+		return c, true
+	}
+	switch strings.ToUpper(tokens[0]) {
+	case "PRIMARY":
+		c.name = defaultName(table, nil, "pkey")
+	case "UNIQUE":
+		c.name = defaultName(table, keyColumns(tokens), "key")
+	case "FOREIGN":
+		c.name = defaultName(table, keyColumns(tokens), "fkey")
+	}
+	return c, true
+}
+
+// columnConstraints returns the constraints of a column definition of table,
+// written as table constraints.
+func columnConstraints(table, elem string) []constraint {
+	tokens := constraintTokens(elem)
+	if len(tokens) < 2 {
+		return nil
+	}
+	col := tokens[0]
+	var out []constraint
+	var name string
+	i := 1
+	for i < len(tokens) && !columnKeywords[strings.ToUpper(tokens[i])] {
+		i++
+	}
+	for i < len(tokens) {
+		word := strings.ToUpper(tokens[i])
+		i++
+		var c constraint
+		switch word {
+		case "CONSTRAINT":
+			if i < len(tokens) {
+				name = tokens[i]
+				i++
+			}
+			continue
+		case "PRIMARY":
+			i++ // KEY
+			c = constraint{name: defaultName(table, nil, "pkey"), def: "PRIMARY KEY (" + col + ")"}
+		case "UNIQUE":
+			c = constraint{name: defaultName(table, []string{col}, "key"), def: "UNIQUE (" + col + ")"}
+		case "CHECK":
+			expr := ""
+			if i < len(tokens) {
+				expr = tokens[i]
+				i++
+			}
+			c = constraint{name: defaultName(table, []string{col}, "check"), def: "CHECK " + expr}
+		case "REFERENCES":
+			ref := []string{}
+			for ; i < len(tokens) && !endsReference(tokens, i); i++ {
+				ref = append(ref, tokens[i])
+			}
+			c = constraint{name: defaultName(table, []string{col}, "fkey"), def: "FOREIGN KEY (" + col + ") REFERENCES " + strings.Join(ref, " ")}
+		default:
+			name = ""
+			continue
+		}
+		if name != "" {
+			c.name, c.named = name, true
+			name = ""
+		}
+		c.column = col
+		out = append(out, c)
+	}
+	return out
+}
+
+// endsReference reports whether tokens[i] starts the constraint that follows
+// the REFERENCES clause of a column, whose actions may contain NULL, NOT and
+// DEFAULT themselves.
+func endsReference(tokens []string, i int) bool {
+	word := strings.ToUpper(tokens[i])
+	prev := strings.ToUpper(tokens[i-1])
+	switch word {
+	case "NULL", "DEFAULT":
+		return prev != "SET"
+	case "NOT":
+		return i+1 >= len(tokens) || strings.ToUpper(tokens[i+1]) != "DEFERRABLE"
+	case "REFERENCES":
+		return true
+	}
+	return constraintKeywords[word] || word == "GENERATED" || word == "COLLATE"
+}
+
+// keyColumns returns the columns of the first parenthesized list of tokens.
+func keyColumns(tokens []string) []string {
+	for _, token := range tokens {
+		if !strings.HasPrefix(token, "(") {
+			continue
+		}
+		var cols []string
+		for _, col := range strings.Split(strings.Trim(token, "()"), ",") {
+			cols = append(cols, strings.Trim(strings.TrimSpace(col), `"`))
+		}
+		return cols
+	}
+	return nil
+}
+
+// defaultName returns the name PostgreSQL gives an unnamed constraint: the
+// table, the columns and the label joined with underscores, where the table
+// and columns are shortened so that the name fits in an identifier.
+func defaultName(table string, columns []string, label string) string {
+	name1, name2 := table, strings.Join(columns, "_")
+	overhead := len(label) + 1
+	if name2 != "" {
+		overhead++
+	}
+	avail := maxIdentifierLength - overhead
+	for len(name1)+len(name2) > avail {
+		if len(name1) > len(name2) {
+			name1 = name1[:len(name1)-1]
+		} else {
+			name2 = name2[:len(name2)-1]
+		}
+	}
+	if name2 == "" {
+		return name1 + "_" + label
+	}
+	return name1 + "_" + name2 + "_" + label
+}
+
+// createConstraints returns the constraints of a CREATE TABLE statement of
+// table.
+func createConstraints(table, stmt string) []constraint {
+	var out []constraint
+	for _, elem := range tableElements(stmt) {
+		if c, ok := tableConstraint(table, elem); ok {
+			out = append(out, c)
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(elem), "LIKE ") {
+			continue
+		}
+		out = append(out, columnConstraints(table, elem)...)
+	}
+	return out
+}
+
+// alterConstraints applies the ADD and DROP CONSTRAINT actions of an ALTER
+// TABLE statement of table to constraints.
+func alterConstraints(table, stmt string, constraints map[string]constraint) {
+	for i, action := range splitElements(stmt) {
+		tokens := columnTokens(action)
+		if i == 0 {
+			// Skip ALTER TABLE [IF EXISTS] [ONLY] name.
+			j := 2
+			for j < len(tokens) && (strings.EqualFold(tokens[j], "IF") || strings.EqualFold(tokens[j], "EXISTS") || strings.EqualFold(tokens[j], "ONLY")) {
+				j++
+			}
+			if j+1 > len(tokens) {
+				return
+			}
+			tokens = tokens[j+1:]
+		}
+		if len(tokens) < 2 {
+			continue
+		}
+		switch strings.ToUpper(tokens[0]) {
+		case "ADD":
+			rest := tokens[1:]
+			if strings.EqualFold(rest[0], "COLUMN") {
+				rest = rest[1:]
+			}
+			elem := strings.Join(rest, " ")
+			if c, ok := tableConstraint(table, elem); ok {
+				constraints[c.key()] = c
+				continue
+			}
+			if len(rest) > 2 && strings.EqualFold(rest[0], "IF") && strings.EqualFold(rest[1], "NOT") {
+				elem = strings.Join(rest[3:], " ")
+			}
+			for _, c := range columnConstraints(table, elem) {
+				constraints[c.key()] = c
+			}
+		case "DROP":
+			if !strings.EqualFold(tokens[1], "CONSTRAINT") {
+				continue
+			}
+			name := tokens[len(tokens)-1]
+			if u := strings.ToUpper(name); (u == "CASCADE" || u == "RESTRICT") && len(tokens) > 3 {
+				name = tokens[len(tokens)-2]
+			}
+			delete(constraints, name)
+		}
+	}
+}
+
+// diffConstraints returns the ALTER TABLE statements that drop the
+// constraints of from that to doesn't have or defines differently, and the
+// ones that add them back. The constraints of the columns that from doesn't
+// have are added with the columns.
+func diffConstraints(name string, from, to map[string]constraint, columns map[string]string) (drop, add []string) {
+	for _, key := range sortedKeys(from) {
+		c := from[key]
+		if n, ok := to[key]; ok && n.compact() == c.compact() {
+			continue
+		}
+		if c.name == "" {
+			drop = append(drop, UnsupportedChange+"drop the unnamed constraint of "+name+": "+c.def)
+			continue
+		}
+		drop = append(drop, "ALTER TABLE "+name+" DROP CONSTRAINT IF EXISTS "+c.name+";")
+	}
+	for _, key := range sortedKeys(to) {
+		c := to[key]
+		if o, ok := from[key]; ok && o.compact() == c.compact() {
+			continue
+		}
+		if _, ok := columns[c.column]; c.column != "" && !ok {
+			continue
+		}
+		add = append(add, "ALTER TABLE "+name+" "+c.add()+";")
+	}
+	return drop, add
+}
@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+const systemSchemas = `n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND n.nspname NOT LIKE 'pg_temp%'`
+
+const columnsQuery = `SELECT n.nspname, c.relname, a.attname, tn.nspname, COALESCE(e.typname, t.typname),
+  e.oid IS NOT NULL, a.attnotnull, format_type(a.atttypid, a.atttypmod)
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_type e ON e.oid = t.typelem AND t.typcategory = 'A'
+JOIN pg_namespace tn ON tn.oid = COALESCE(e.typnamespace, t.typnamespace)
+WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+  AND ` + systemSchemas + `
+ORDER BY n.nspname, c.relname, a.attnum`
+
+const enumsQuery = `SELECT n.nspname, t.typname, e.enumlabel
+FROM pg_enum e
+JOIN pg_type t ON t.oid = e.enumtypid
+JOIN pg_namespace n ON n.oid = t.typnamespace
+ORDER BY n.nspname, t.typname, e.enumsortorder`
+
+// Indexes that back constraints are created with the constraint, so they
+// are left out.
+const indexesQuery = `SELECT n.nspname, i.relname, c.relname, x.indisunique,
+  ARRAY(SELECT pg_get_indexdef(x.indexrelid, k, true) FROM generate_series(1, x.indnkeyatts) AS k ORDER BY k)
+FROM pg_index x
+JOIN pg_class i ON i.oid = x.indexrelid
+JOIN pg_class c ON c.oid = x.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conindid = x.indexrelid)
+  AND ` + systemSchemas + `
+ORDER BY n.nspname, i.relname`
+
+// A Querier runs queries on a database, such as a *pgx.Conn.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// LoadDatabase reads the tables, enums and indexes of the database conn is
+// connected to from pg_catalog.
+func LoadDatabase(ctx context.Context, conn Querier) (*Schema, error) {
+	s := NewSchema()
+	if err := conn.QueryRow(ctx, "SELECT current_schema()").Scan(&s.defaults); err != nil {
+		return nil, err
+	}
+	if err := s.loadColumns(ctx, conn); err != nil {
+		return nil, err
+	}
+	if err := s.loadEnums(ctx, conn); err != nil {
+		return nil, err
+	}
+	if err := s.loadIndexes(ctx, conn); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) loadColumns(ctx context.Context, conn Querier) error {
+	rows, err := conn.Query(ctx, columnsQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schema, table, typeSchema string
+		var col catalog.Column
+		var format string
+		if err := rows.Scan(&schema, &table, &col.Name, &typeSchema, &col.Type.Name, &col.IsArray, &col.IsNotNull, &format); err != nil {
+			return err
+		}
+		col.Type.Schema = typeSchema
+		name := s.qualify(schema, table)
+		rel, ok := s.tables[name]
+		if !ok {
+			rel = &relation{
+				name:    name,
+				table:   &catalog.Table{Rel: &ast.TableName{Schema: schema, Name: table}},
+				columns: map[string]string{},
+			}
+			s.tables[name] = rel
+			s.order = append(s.order, name)
+		}
+		rel.table.Columns = append(rel.table.Columns, &col)
+		rel.columns[col.Name] = col.Name + " " + format
+	}
+	return rows.Err()
+}
+
+func (s *Schema) loadEnums(ctx context.Context, conn Querier) error {
+	rows, err := conn.Query(ctx, enumsQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schema, typ, label string
+		if err := rows.Scan(&schema, &typ, &label); err != nil {
+			return err
+		}
+		name := s.qualify(schema, typ)
+		enum, ok := s.enums[name]
+		if !ok {
+			enum = &catalog.Enum{Name: typ}
+			s.enums[name] = enum
+		}
+		enum.Vals = append(enum.Vals, label)
+	}
+	return rows.Err()
+}
+
+func (s *Schema) loadIndexes(ctx context.Context, conn Querier) error {
+	rows, err := conn.Query(ctx, indexesQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schema, name, table string
+		idx := &index{}
+		if err := rows.Scan(&schema, &name, &table, &idx.unique, &idx.columns); err != nil {
+			return err
+		}
+		idx.name = s.qualify(schema, name)
+		idx.table = s.qualify(schema, table)
+		s.indexes[idx.name] = idx
+	}
+	return rows.Err()
+}
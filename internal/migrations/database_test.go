@@ -0,0 +1,122 @@
+package migrations_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+)
+
+// catalogConn answers the queries of LoadDatabase with the rows PostgreSQL
+// returns from pg_catalog.
+type catalogConn struct {
+	columns, enums, indexes [][]any
+	queried                 []*catalogRows
+}
+
+func (c *catalogConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows := &catalogRows{}
+	switch {
+	case strings.Contains(sql, "pg_attribute"):
+		rows.rows = c.columns
+	case strings.Contains(sql, "pg_enum"):
+		rows.rows = c.enums
+	case strings.Contains(sql, "pg_index"):
+		rows.rows = c.indexes
+	default:
+		return nil, pgx.ErrNoRows
+	}
+	c.queried = append(c.queried, rows)
+	return rows, nil
+}
+
+func (c *catalogConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &catalogRows{rows: [][]any{{"public"}}, pos: 1}
+}
+
+type catalogRows struct {
+	pgx.Rows
+	rows   [][]any
+	pos    int
+	closed bool
+}
+
+func (r *catalogRows) Next() bool {
+	if r.pos >= len(r.rows) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *catalogRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.rows[r.pos-1][i]))
+	}
+	return nil
+}
+
+func (r *catalogRows) Err() error { return nil }
+func (r *catalogRows) Close()     { r.closed = true }
+
+func TestLoadDatabase(t *testing.T) {
+	files := buildSchema(t, `
+CREATE TYPE status AS ENUM ('open', 'closed');
+CREATE TABLE tickets (
+  id     BIGSERIAL PRIMARY KEY,
+  title  VARCHAR(100) NOT NULL,
+  price  NUMERIC(10) NOT NULL,
+  code   CHAR NOT NULL,
+  status status NOT NULL,
+  tags   TEXT[],
+  opened TIMESTAMPTZ(3)
+);
+CREATE INDEX tickets_title_idx ON tickets (title);
+CREATE INDEX ON tickets (status, id);
+CREATE INDEX ON tickets (opened);
+`)
+	conn := &catalogConn{
+		columns: [][]any{
+			{"public", "tickets", "id", "pg_catalog", "int8", false, true, "bigint"},
+			{"public", "tickets", "title", "pg_catalog", "varchar", false, true, "character varying(255)"},
+			{"public", "tickets", "price", "pg_catalog", "numeric", false, true, "numeric(10,0)"},
+			{"public", "tickets", "code", "pg_catalog", "bpchar", false, true, "character(1)"},
+			{"public", "tickets", "status", "public", "status", false, true, "status"},
+			{"public", "tickets", "tags", "pg_catalog", "text", true, false, "text[]"},
+			{"public", "tickets", "opened", "pg_catalog", "timestamptz", false, false, "timestamp(3) with time zone"},
+		},
+		enums: [][]any{
+			{"public", "status", "open"},
+			{"public", "status", "closed"},
+		},
+		indexes: [][]any{
+			{"public", "tickets_status_id_idx", "tickets", false, []string{"status", "id"}},
+			{"public", "tickets_title_idx", "tickets", false, []string{"title"}},
+		},
+	}
+	db, err := migrations.LoadDatabase(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []migrations.Drift{
+		{Kind: "column", Name: "tickets.title", Problem: "type", Schema: "varchar(100)", Database: "varchar(255)"},
+		{Kind: "index", Name: "tickets (opened)", Problem: "missing"},
+	}
+	if diff := cmp.Diff(want, migrations.Verify(files, db)); diff != "" {
+		t.Errorf("drifts differed (-want +got):\n%s", diff)
+	}
+	if len(conn.queried) != 3 {
+		t.Errorf("ran %d queries, want 3", len(conn.queried))
+	}
+	for i, rows := range conn.queried {
+		if !rows.closed {
+			t.Errorf("rows of query %d were not closed", i)
+		}
+	}
+}
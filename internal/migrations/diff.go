@@ -0,0 +1,508 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+// Schema is the state of a database that Diff computes migrations between.
+// Tables, columns and types come from the catalog built from the schema
+// files, while the statements that created relations and indexes are kept
+// verbatim, so that defaults, constraints and type modifiers the catalog
+// does not track survive in the migration.
+type Schema struct {
+	schemas  map[string]bool
+	enums    map[string]*catalog.Enum
+	types    map[string]*catalog.CompositeType
+	tables   map[string]*relation
+	views    map[string]*relation
+	indexes  map[string]*index
+	exts     map[string]string
+	order    []string
+	defaults string
+}
+
+type relation struct {
+	name    string
+	table   *catalog.Table
+	stmt    string
+	columns map[string]string
+	// constraints are keyed by name, or by definition for the unnamed
+	// constraints whose name PostgreSQL picks.
+	constraints map[string]constraint
+}
+
+type index struct {
+	name   string
+	table  string
+	stmt   string
+	unique bool
+	// columns are the key columns of the index, with "" for expressions.
+	columns []string
+}
+
+func NewSchema() *Schema {
+	return &Schema{
+		schemas: map[string]bool{},
+		enums:   map[string]*catalog.Enum{},
+		types:   map[string]*catalog.CompositeType{},
+		tables:  map[string]*relation{},
+		views:   map[string]*relation{},
+		indexes: map[string]*index{},
+		exts:    map[string]string{},
+	}
+}
+
+// AddCatalog adds the schemas, tables and types of c. Objects already added
+// from another catalog are kept, so that the catalogs of several packages
+// sharing schema files can be merged.
+func (s *Schema) AddCatalog(c *catalog.Catalog) {
+	s.defaults = c.DefaultSchema
+	for _, schema := range c.Schemas {
+		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
+			continue
+		}
+		if schema.Name != c.DefaultSchema {
+			s.schemas[schema.Name] = true
+		}
+		for _, typ := range schema.Types {
+			name := s.qualify(schema.Name, typeName(typ))
+			switch t := typ.(type) {
+			case *catalog.Enum:
+				if _, ok := s.enums[name]; !ok {
+					s.enums[name] = t
+				}
+			case *catalog.CompositeType:
+				if _, ok := s.types[name]; !ok {
+					s.types[name] = t
+				}
+			}
+		}
+		for _, table := range schema.Tables {
+			name := s.qualify(schema.Name, table.Rel.Name)
+			if rel, ok := s.tables[name]; ok {
+				if rel.table == nil {
+					rel.table = table
+				}
+				continue
+			}
+			s.tables[name] = &relation{name: name, table: table, columns: map[string]string{}}
+			s.order = append(s.order, name)
+		}
+	}
+}
+
+// AddStatements records the CREATE statements of a schema file, parsed from
+// contents.
+func (s *Schema) AddStatements(contents string, stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if stmt.Raw == nil {
+			continue
+		}
+		text := statementText(contents, stmt.Raw)
+		switch n := stmt.Raw.Stmt.(type) {
+		case *ast.CreateTableStmt:
+			name := s.qualify(n.Name.Schema, n.Name.Name)
+			rel, ok := s.tables[name]
+			if !ok {
+				rel = &relation{name: name, columns: map[string]string{}}
+				s.tables[name] = rel
+				s.order = append(s.order, name)
+			}
+			if rel.stmt != "" {
+				continue
+			}
+			rel.stmt = text
+			for _, col := range n.Cols {
+				rel.columns[col.Colname] = columnText(contents, col.Location)
+			}
+			rel.constraints = map[string]constraint{}
+			for _, c := range createConstraints(n.Name.Name, text) {
+				rel.constraints[c.key()] = c
+			}
+		case *ast.AlterTableStmt:
+			if n.Table == nil {
+				continue
+			}
+			if rel, ok := s.tables[s.qualify(n.Table.Schema, n.Table.Name)]; ok && rel.constraints != nil {
+				alterConstraints(n.Table.Name, text, rel.constraints)
+			}
+		case *ast.ViewStmt:
+			name := s.relName(n.View)
+			s.views[name] = &relation{name: name, stmt: text}
+		case *ast.CreateTableAsStmt:
+			if n.Into == nil || n.Into.Rel == nil {
+				continue
+			}
+			name := s.relName(n.Into.Rel)
+			s.views[name] = &relation{name: name, stmt: text}
+		case *ast.IndexStmt:
+			if n.Relation == nil {
+				continue
+			}
+			table := s.relName(n.Relation)
+			// PostgreSQL names unnamed indexes itself, so they are told
+			// apart by their definition.
+			key := normalize(text)
+			var name string
+			if n.Idxname != nil {
+				name = s.qualify(deref(n.Relation.Schemaname), *n.Idxname)
+				key = name
+			}
+			idx := &index{name: name, table: table, stmt: text, unique: n.Unique}
+			if n.IndexParams != nil {
+				for _, item := range n.IndexParams.Items {
+					elem, ok := item.(*ast.IndexElem)
+					if !ok {
+						continue
+					}
+					idx.columns = append(idx.columns, deref(elem.Name))
+				}
+			}
+			s.indexes[key] = idx
+		case *ast.CreateExtensionStmt:
+			if n.Extname != nil {
+				s.exts[*n.Extname] = text
+			}
+		}
+	}
+}
+
+func (s *Schema) qualify(schema, name string) string {
+	if schema == "" || schema == s.defaults {
+		return name
+	}
+	return schema + "." + name
+}
+
+func (s *Schema) relName(rv *ast.RangeVar) string {
+	return s.qualify(deref(rv.Schemaname), deref(rv.Relname))
+}
+
+func (s *Schema) isView(name string) bool {
+	_, ok := s.views[name]
+	return ok
+}
+
+// Diff returns the statements that migrate a database from the from schema
+// to the to schema. The statements of the rollback are Diff(to, from).
+func Diff(from, to *Schema) []string {
+	var out []string
+	add := func(format string, args ...any) {
+		out = append(out, fmt.Sprintf(format, args...))
+	}
+
+	for _, name := range sortedKeys(to.schemas) {
+		if !from.schemas[name] {
+			add("CREATE SCHEMA %s;", name)
+		}
+	}
+	for _, name := range sortedKeys(to.exts) {
+		if _, ok := from.exts[name]; !ok {
+			add("%s;", to.exts[name])
+		}
+	}
+
+	// Types come first, as the columns added below may use them.
+	for _, name := range sortedKeys(to.enums) {
+		old, ok := from.enums[name]
+		if !ok {
+			add("CREATE TYPE %s AS ENUM (%s);", name, quoteValues(to.enums[name].Vals))
+			continue
+		}
+		out = append(out, diffEnum(name, old.Vals, to.enums[name].Vals)...)
+	}
+	for _, name := range sortedKeys(to.types) {
+		old, ok := from.types[name]
+		if !ok {
+			var attrs []string
+			for _, col := range to.types[name].Columns {
+				attrs = append(attrs, col.Name+" "+columnType(col))
+			}
+			add("CREATE TYPE %s AS (%s);", name, strings.Join(attrs, ", "))
+			continue
+		}
+		out = append(out, diffComposite(name, old, to.types[name])...)
+	}
+
+	// Views and indexes that change are dropped before the tables they
+	// depend on, and created again once the tables are migrated.
+	for _, name := range sortedKeys(from.views) {
+		if v, ok := to.views[name]; !ok || normalize(v.stmt) != normalize(from.views[name].stmt) {
+			add("DROP %s IF EXISTS %s;", viewKind(from.views[name].stmt), name)
+		}
+	}
+	for _, key := range sortedKeys(from.indexes) {
+		idx := from.indexes[key]
+		if i, ok := to.indexes[key]; ok && normalize(i.stmt) == normalize(idx.stmt) {
+			continue
+		}
+		if idx.name == "" {
+			add(UnsupportedChange+"drop the unnamed index of %s: %s", idx.table, normalize(idx.stmt))
+			continue
+		}
+		add("DROP INDEX IF EXISTS %s;", idx.name)
+	}
+
+	// Constraints that change are dropped before the columns they use, and
+	// added once every table is migrated, as they may reference new tables.
+	var addConstraints []string
+	for _, name := range to.order {
+		old, ok := from.tables[name]
+		rel := to.tables[name]
+		if !ok || to.isView(name) || from.isView(name) || old.constraints == nil || rel.constraints == nil {
+			continue
+		}
+		drop, add := diffConstraints(name, old.constraints, rel.constraints, old.columns)
+		out = append(out, drop...)
+		addConstraints = append(addConstraints, add...)
+	}
+	for _, name := range to.order {
+		if to.isView(name) {
+			continue
+		}
+		rel := to.tables[name]
+		old, ok := from.tables[name]
+		if !ok || from.isView(name) {
+			out = append(out, createTable(rel))
+			continue
+		}
+		out = append(out, diffTable(name, old, rel)...)
+	}
+	out = append(out, addConstraints...)
+	for i := len(from.order) - 1; i >= 0; i-- {
+		name := from.order[i]
+		if from.isView(name) {
+			continue
+		}
+		if _, ok := to.tables[name]; !ok || to.isView(name) {
+			add("DROP TABLE IF EXISTS %s;", name)
+		}
+	}
+
+	for _, name := range sortedKeys(to.views) {
+		if v, ok := from.views[name]; !ok || normalize(v.stmt) != normalize(to.views[name].stmt) {
+			add("%s;", to.views[name].stmt)
+		}
+	}
+	for _, key := range sortedKeys(to.indexes) {
+		if i, ok := from.indexes[key]; !ok || normalize(i.stmt) != normalize(to.indexes[key].stmt) {
+			add("%s;", to.indexes[key].stmt)
+		}
+	}
+
+	for _, name := range sortedKeys(from.types) {
+		if _, ok := to.types[name]; !ok {
+			add("DROP TYPE IF EXISTS %s;", name)
+		}
+	}
+	for _, name := range sortedKeys(from.enums) {
+		if _, ok := to.enums[name]; !ok {
+			add("DROP TYPE IF EXISTS %s;", name)
+		}
+	}
+	for _, name := range sortedKeys(from.exts) {
+		if _, ok := to.exts[name]; !ok {
+			add("DROP EXTENSION IF EXISTS %s;", name)
+		}
+	}
+	for _, name := range sortedKeys(from.schemas) {
+		if !to.schemas[name] {
+			add("DROP SCHEMA IF EXISTS %s;", name)
+		}
+	}
+	return out
+}
+
+// diffEnum adds the new values of an enum in place. PostgreSQL can't remove
+// the values of an enum, so removed values are only reported.
+func diffEnum(name string, from, to []string) []string {
+	var out []string
+	have := map[string]bool{}
+	for _, v := range from {
+		have[v] = true
+	}
+	for i, v := range to {
+		if have[v] {
+			continue
+		}
+		switch {
+		case i == len(to)-1:
+			out = append(out, fmt.Sprintf("ALTER TYPE %s ADD VALUE %s;", name, quote(v)))
+		case i == 0:
+			out = append(out, fmt.Sprintf("ALTER TYPE %s ADD VALUE %s BEFORE %s;", name, quote(v), quote(to[1])))
+		default:
+			out = append(out, fmt.Sprintf("ALTER TYPE %s ADD VALUE %s AFTER %s;", name, quote(v), quote(to[i-1])))
+		}
+	}
+	keep := map[string]bool{}
+	for _, v := range to {
+		keep[v] = true
+	}
+	for _, v := range from {
+		if !keep[v] {
+			out = append(out, fmt.Sprintf(UnsupportedChange+"PostgreSQL can't remove the value %s of enum %s", quote(v), name))
+		}
+	}
+	return out
+}
+
+func diffComposite(name string, from, to *catalog.CompositeType) []string {
+	var out []string
+	old := map[string]*catalog.Column{}
+	for _, col := range from.Columns {
+		old[col.Name] = col
+	}
+	keep := map[string]bool{}
+	for _, col := range to.Columns {
+		keep[col.Name] = true
+		prev, ok := old[col.Name]
+		switch {
+		case !ok:
+			out = append(out, fmt.Sprintf("ALTER TYPE %s ADD ATTRIBUTE %s %s;", name, col.Name, columnType(col)))
+		case columnType(prev) != columnType(col):
+			out = append(out, fmt.Sprintf("ALTER TYPE %s ALTER ATTRIBUTE %s TYPE %s;", name, col.Name, columnType(col)))
+		}
+	}
+	for _, col := range from.Columns {
+		if !keep[col.Name] {
+			out = append(out, fmt.Sprintf("ALTER TYPE %s DROP ATTRIBUTE %s;", name, col.Name))
+		}
+	}
+	return out
+}
+
+func createTable(rel *relation) string {
+	if rel.stmt != "" {
+		return rel.stmt + ";"
+	}
+	var cols []string
+	for _, col := range rel.table.Columns {
+		cols = append(cols, "  "+rel.column(col))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);", rel.name, strings.Join(cols, ",\n"))
+}
+
+func diffTable(name string, from, to *relation) []string {
+	if from.table == nil || to.table == nil {
+		return nil
+	}
+	var out []string
+	add := func(format string, args ...any) {
+		out = append(out, fmt.Sprintf("ALTER TABLE %s "+format+";", append([]any{name}, args...)...))
+	}
+	old := map[string]*catalog.Column{}
+	for _, col := range from.table.Columns {
+		old[col.Name] = col
+	}
+	keep := map[string]bool{}
+	for _, col := range to.table.Columns {
+		keep[col.Name] = true
+		prev, ok := old[col.Name]
+		if !ok {
+			add("ADD COLUMN %s", to.column(col))
+			continue
+		}
+		oldDef, newDef := parseColumn(from.columns[col.Name]), parseColumn(to.columns[col.Name])
+		oldType, newType := columnType(prev), columnType(col)
+		if oldDef.typ != "" && newDef.typ != "" {
+			oldType, newType = oldDef.typ, newDef.typ
+		}
+		if !strings.EqualFold(oldType, newType) {
+			add("ALTER COLUMN %s TYPE %s", col.Name, newType)
+		}
+		if prev.IsNotNull != col.IsNotNull {
+			if col.IsNotNull {
+				add("ALTER COLUMN %s SET NOT NULL", col.Name)
+			} else {
+				add("ALTER COLUMN %s DROP NOT NULL", col.Name)
+			}
+		}
+		if !strings.EqualFold(oldDef.def, newDef.def) {
+			if newDef.def == "" {
+				add("ALTER COLUMN %s DROP DEFAULT", col.Name)
+			} else {
+				add("ALTER COLUMN %s SET DEFAULT %s", col.Name, newDef.def)
+			}
+		}
+	}
+	for _, col := range from.table.Columns {
+		if !keep[col.Name] {
+			add("DROP COLUMN %s", col.Name)
+		}
+	}
+	return out
+}
+
+// column returns the definition of a column, as written in the CREATE TABLE
+// statement if the column comes from it.
+func (r *relation) column(col *catalog.Column) string {
+	if def, ok := r.columns[col.Name]; ok && def != "" {
+		return def
+	}
+	def := col.Name + " " + columnType(col)
+	if col.IsNotNull {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+func columnType(col *catalog.Column) string {
+	name := col.Type.Name
+	if col.Type.Schema != "" && col.Type.Schema != "pg_catalog" {
+		name = col.Type.Schema + "." + name
+	}
+	if col.IsArray {
+		dims := col.ArrayDims
+		if dims == 0 {
+			dims = 1
+		}
+		name += strings.Repeat("[]", dims)
+	}
+	return name
+}
+
+func typeName(t catalog.Type) string {
+	switch t := t.(type) {
+	case *catalog.Enum:
+		return t.Name
+	case *catalog.CompositeType:
+		return t.Name
+	}
+	return ""
+}
+
+func viewKind(stmt string) string {
+	if strings.Contains(strings.ToUpper(normalize(stmt)), "MATERIALIZED VIEW") {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func quoteValues(vals []string) string {
+	quoted := make([]string, len(vals))
+	for i, v := range vals {
+		quoted[i] = quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// normalize collapses the white space of a statement, so that statements
+// are compared regardless of their formatting.
+func normalize(stmt string) string {
+	return strings.Join(strings.Fields(stmt), " ")
+}
@@ -0,0 +1,166 @@
+package migrations_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/compiler"
+	"github.com/sqlc-dev/sqlc/internal/config"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+)
+
+// buildSchema builds the schema of the files with the given contents, each
+// creating a single table.
+func buildSchema(t *testing.T, files ...string) *migrations.Schema {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, sql := range files {
+		path := filepath.Join(dir, fmt.Sprintf("%d.sql", i))
+		if err := os.WriteFile(path, []byte(sql), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	c := compiler.NewCompiler(config.SQL{Engine: config.EnginePostgreSQL}, config.CombinedSettings{})
+	if err := c.ParseCatalog(paths); err != nil {
+		t.Fatal(err)
+	}
+	s := migrations.NewSchema()
+	s.AddCatalog(c.Catalog())
+	for _, sql := range files {
+		stmts, err := c.Parser().Parse(strings.NewReader(sql))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.AddStatements(sql, stmts)
+	}
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	from := buildSchema(t, `
+CREATE TYPE status AS ENUM ('open', 'closed');
+CREATE TABLE tickets (
+  id     BIGINT PRIMARY KEY,
+  title  VARCHAR(100) NOT NULL,
+  status status NOT NULL DEFAULT 'open',
+  note   TEXT
+);
+CREATE INDEX tickets_title_idx ON tickets (title);
+`)
+	to := buildSchema(t, `
+CREATE TYPE status AS ENUM ('open', 'pending', 'closed');
+CREATE TABLE tickets (
+  id     BIGINT PRIMARY KEY,
+  title  VARCHAR(255) NOT NULL, -- shown in lists
+  status status NOT NULL DEFAULT 'open',
+  opened TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX tickets_opened_idx ON tickets (opened DESC);
+`)
+
+	up := []string{
+		"ALTER TYPE status ADD VALUE 'pending' AFTER 'open';",
+		"DROP INDEX IF EXISTS tickets_title_idx;",
+		"ALTER TABLE tickets ALTER COLUMN title TYPE VARCHAR(255);",
+		"ALTER TABLE tickets ADD COLUMN opened TIMESTAMPTZ NOT NULL DEFAULT now();",
+		"ALTER TABLE tickets DROP COLUMN note;",
+		"CREATE INDEX tickets_opened_idx ON tickets (opened DESC);",
+	}
+	if diff := cmp.Diff(up, migrations.Diff(from, to)); diff != "" {
+		t.Errorf("up differed (-want +got):\n%s", diff)
+	}
+	down := []string{
+		"-- unsupported change: PostgreSQL can't remove the value 'pending' of enum status",
+		"DROP INDEX IF EXISTS tickets_opened_idx;",
+		"ALTER TABLE tickets ALTER COLUMN title TYPE VARCHAR(100);",
+		"ALTER TABLE tickets ADD COLUMN note TEXT;",
+		"ALTER TABLE tickets DROP COLUMN opened;",
+		"CREATE INDEX tickets_title_idx ON tickets (title);",
+	}
+	if diff := cmp.Diff(down, migrations.Diff(to, from)); diff != "" {
+		t.Errorf("down differed (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffConstraints(t *testing.T) {
+	from := buildSchema(t, `
+CREATE TABLE authors (
+  id    BIGINT PRIMARY KEY,
+  email TEXT NOT NULL UNIQUE,
+  name  TEXT NOT NULL CHECK (name <> '')
+);
+`, `
+CREATE TABLE books (
+  id        BIGINT NOT NULL,
+  author_id BIGINT NOT NULL,
+  isbn      TEXT NOT NULL,
+  price     INT NOT NULL,
+  CONSTRAINT books_price_positive CHECK (price > 0),
+  CHECK (price < 100000),
+  PRIMARY KEY (id)
+);
+ALTER TABLE books ADD CONSTRAINT books_isbn_unique UNIQUE (isbn);
+`)
+	to := buildSchema(t, `
+CREATE TABLE authors (
+  id    BIGINT PRIMARY KEY,
+  email TEXT NOT NULL,
+  name  TEXT NOT NULL CHECK(name <> '')
+);
+`, `
+CREATE TABLE publishers (
+  id BIGINT PRIMARY KEY
+);
+`, `
+CREATE TABLE books (
+  id           BIGINT NOT NULL,
+  author_id    BIGINT NOT NULL REFERENCES authors (id) ON DELETE SET NULL,
+  publisher_id BIGINT NOT NULL,
+  isbn         TEXT NOT NULL,
+  price        INT NOT NULL,
+  CONSTRAINT books_price_positive CHECK (price >= 0),
+  CONSTRAINT books_publisher_fk FOREIGN KEY (publisher_id) REFERENCES publishers (id),
+  PRIMARY KEY (id, author_id)
+);
+`)
+
+	up := []string{
+		"-- unsupported change: drop the unnamed constraint of books: CHECK (price < 100000)",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_unique;",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_pkey;",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_price_positive;",
+		"ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_email_key;",
+		"ALTER TABLE books ADD COLUMN publisher_id BIGINT NOT NULL;",
+		"CREATE TABLE publishers (\n  id BIGINT PRIMARY KEY\n);",
+		"ALTER TABLE books ADD FOREIGN KEY (author_id) REFERENCES authors (id) ON DELETE SET NULL;",
+		"ALTER TABLE books ADD PRIMARY KEY (id, author_id);",
+		"ALTER TABLE books ADD CONSTRAINT books_price_positive CHECK (price >= 0);",
+		"ALTER TABLE books ADD CONSTRAINT books_publisher_fk FOREIGN KEY (publisher_id) REFERENCES publishers (id);",
+	}
+	if diff := cmp.Diff(up, migrations.Diff(from, to)); diff != "" {
+		t.Errorf("up differed (-want +got):\n%s", diff)
+	}
+	down := []string{
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_author_id_fkey;",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_pkey;",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_price_positive;",
+		"ALTER TABLE books DROP CONSTRAINT IF EXISTS books_publisher_fk;",
+		"ALTER TABLE books DROP COLUMN publisher_id;",
+		"ALTER TABLE books ADD CHECK (price < 100000);",
+		"ALTER TABLE books ADD CONSTRAINT books_isbn_unique UNIQUE (isbn);",
+		"ALTER TABLE books ADD PRIMARY KEY (id);",
+		"ALTER TABLE books ADD CONSTRAINT books_price_positive CHECK (price > 0);",
+		"ALTER TABLE authors ADD UNIQUE (email);",
+		"DROP TABLE IF EXISTS publishers;",
+	}
+	if diff := cmp.Diff(down, migrations.Diff(to, from)); diff != "" {
+		t.Errorf("down differed (-want +got):\n%s", diff)
+	}
+}
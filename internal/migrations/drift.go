@@ -0,0 +1,211 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/catalog"
+)
+
+// A Drift is a difference between the schema files and a database.
+type Drift struct {
+	// Kind is one of "table", "column", "enum" and "index".
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Problem is "missing" for objects of the schema files the database
+	// lacks, "extra" for objects only the database has, or the property that
+	// differs: "type", "nullability", "values" or "definition".
+	Problem  string `json:"problem"`
+	Schema   string `json:"schema,omitempty"`
+	Database string `json:"database,omitempty"`
+}
+
+func (d Drift) String() string {
+	switch d.Problem {
+	case "missing":
+		return fmt.Sprintf("%s %s: missing in the database", d.Kind, d.Name)
+	case "extra":
+		return fmt.Sprintf("%s %s: not in the schema files", d.Kind, d.Name)
+	}
+	return fmt.Sprintf("%s %s: %s is %s in the database, %s in the schema files", d.Kind, d.Name, d.Problem, d.Database, d.Schema)
+}
+
+// modTypes are the types whose modifiers, such as the length of a varchar,
+// are compared.
+var modTypes = map[string]bool{
+	"bit":         true,
+	"bpchar":      true,
+	"interval":    true,
+	"numeric":     true,
+	"time":        true,
+	"timestamp":   true,
+	"timestamptz": true,
+	"timetz":      true,
+	"varbit":      true,
+	"varchar":     true,
+}
+
+// serialAliases maps the serial pseudo-types to the type of their column.
+var serialAliases = map[string]string{
+	"smallserial": "int2",
+	"serial2":     "int2",
+	"serial":      "int4",
+	"serial4":     "int4",
+	"bigserial":   "int8",
+	"serial8":     "int8",
+}
+
+// Verify compares the tables, columns, enums and indexes of the schema files
+// with the ones of a database. Tables and enums that only exist in the
+// database are not reported, since a database is often shared by several
+// services; its extra columns and indexes on the tables of the schema files
+// are.
+func Verify(files, db *Schema) []Drift {
+	var drifts []Drift
+	add := func(kind, name, problem, schema, database string) {
+		drifts = append(drifts, Drift{Kind: kind, Name: name, Problem: problem, Schema: schema, Database: database})
+	}
+
+	for _, name := range files.order {
+		rel := files.tables[name]
+		if rel.table == nil {
+			continue
+		}
+		dbRel, ok := db.tables[name]
+		if !ok || dbRel.table == nil {
+			add("table", name, "missing", "", "")
+			continue
+		}
+		dbCols := map[string]*catalog.Column{}
+		for _, col := range dbRel.table.Columns {
+			dbCols[col.Name] = col
+		}
+		keep := map[string]bool{}
+		for _, col := range rel.table.Columns {
+			keep[col.Name] = true
+			colName := name + "." + col.Name
+			dbCol, ok := dbCols[col.Name]
+			if !ok {
+				add("column", colName, "missing", "", "")
+				continue
+			}
+			want := files.describeType(col, rel.columns[col.Name])
+			got := db.describeType(dbCol, dbRel.columns[col.Name])
+			if want != got {
+				add("column", colName, "type", want, got)
+			}
+			if col.IsNotNull != dbCol.IsNotNull {
+				add("column", colName, "nullability", nullability(col), nullability(dbCol))
+			}
+		}
+		for _, col := range dbRel.table.Columns {
+			if !keep[col.Name] {
+				add("column", name+"."+col.Name, "extra", "", "")
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(files.enums) {
+		enum := files.enums[name]
+		dbEnum, ok := db.enums[name]
+		if !ok {
+			add("enum", name, "missing", "", "")
+			continue
+		}
+		want, got := quoteValues(enum.Vals), quoteValues(dbEnum.Vals)
+		if want != got {
+			add("enum", name, "values", want, got)
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, key := range sortedKeys(files.indexes) {
+		idx := files.indexes[key]
+		if idx.name == "" {
+			// PostgreSQL named the index, so it is found by its definition.
+			found := false
+			for _, dbKey := range sortedKeys(db.indexes) {
+				if !matched[dbKey] && idx.table == db.indexes[dbKey].table && indexDef(idx) == indexDef(db.indexes[dbKey]) {
+					matched[dbKey] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				add("index", idx.table+" "+indexDef(idx), "missing", "", "")
+			}
+			continue
+		}
+		dbIdx, ok := db.indexes[key]
+		if !ok {
+			add("index", idx.name, "missing", "", "")
+			continue
+		}
+		matched[key] = true
+		if want, got := indexDef(idx), indexDef(dbIdx); want != got && !hasExpression(idx) {
+			add("index", idx.name, "definition", want, got)
+		}
+	}
+	for _, key := range sortedKeys(db.indexes) {
+		dbIdx := db.indexes[key]
+		if _, ok := files.tables[dbIdx.table]; !ok || matched[key] {
+			continue
+		}
+		if _, ok := files.indexes[key]; !ok {
+			add("index", dbIdx.name, "extra", "", "")
+		}
+	}
+	return drifts
+}
+
+// describeType returns the type of a column, with its modifiers, as it is
+// compared by Verify. raw is the definition of the column.
+func (s *Schema) describeType(col *catalog.Column, raw string) string {
+	name := col.Type.Name
+	if alias, ok := serialAliases[name]; ok {
+		name = alias
+	}
+	if schema := col.Type.Schema; schema != "" && schema != "pg_catalog" && schema != s.defaults {
+		name = schema + "." + name
+	}
+	if modTypes[name] {
+		_, mods := splitType(parseColumn(raw).typ)
+		switch {
+		case mods == "" && (name == "bpchar" || name == "bit"):
+			mods = "1"
+		case name == "numeric" && mods != "" && !strings.Contains(mods, ","):
+			mods += ",0"
+		}
+		if mods != "" {
+			name += "(" + mods + ")"
+		}
+	}
+	if col.IsArray {
+		name += "[]"
+	}
+	return name
+}
+
+func nullability(col *catalog.Column) string {
+	if col.IsNotNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+func indexDef(idx *index) string {
+	def := "(" + strings.Join(idx.columns, ", ") + ")"
+	if idx.unique {
+		def = "UNIQUE " + def
+	}
+	return def
+}
+
+func hasExpression(idx *index) bool {
+	for _, col := range idx.columns {
+		if col == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+)
+
+func TestVerify(t *testing.T) {
+	files := buildSchema(t, `
+CREATE TYPE status AS ENUM ('open', 'closed');
+CREATE TABLE tickets (
+  id     BIGSERIAL PRIMARY KEY,
+  title  VARCHAR(100) NOT NULL,
+  price  NUMERIC(10) NOT NULL,
+  status status NOT NULL,
+  tags   TEXT[]
+);
+CREATE INDEX tickets_title_idx ON tickets (title);
+CREATE INDEX ON tickets (status, id);
+CREATE INDEX ON tickets (price);
+`)
+	db := buildSchema(t, `
+CREATE TYPE status AS ENUM ('open', 'pending', 'closed');
+CREATE TABLE tickets (
+  id     BIGINT PRIMARY KEY,
+  title  CHARACTER VARYING(255) NOT NULL,
+  price  DECIMAL(10, 0) NOT NULL,
+  status status,
+  hotfix INT
+);
+CREATE UNIQUE INDEX tickets_title_idx ON tickets (title);
+CREATE INDEX tickets_status_id_idx ON tickets (status, id);
+CREATE INDEX tickets_hotfix_idx ON tickets (hotfix);
+`)
+
+	want := []migrations.Drift{
+		{Kind: "column", Name: "tickets.title", Problem: "type", Schema: "varchar(100)", Database: "varchar(255)"},
+		{Kind: "column", Name: "tickets.status", Problem: "nullability", Schema: "NOT NULL", Database: "NULL"},
+		{Kind: "column", Name: "tickets.tags", Problem: "missing"},
+		{Kind: "column", Name: "tickets.hotfix", Problem: "extra"},
+		{Kind: "enum", Name: "status", Problem: "values", Schema: "'open', 'closed'", Database: "'open', 'pending', 'closed'"},
+		{Kind: "index", Name: "tickets (price)", Problem: "missing"},
+		{Kind: "index", Name: "tickets_title_idx", Problem: "definition", Schema: "(title)", Database: "UNIQUE (title)"},
+		{Kind: "index", Name: "tickets_hotfix_idx", Problem: "extra"},
+	}
+	if diff := cmp.Diff(want, migrations.Verify(files, db)); diff != "" {
+		t.Errorf("drifts differed (-want +got):\n%s", diff)
+	}
+	if drifts := migrations.Verify(files, files); len(drifts) != 0 {
+		t.Errorf("schema drifted from itself: %v", drifts)
+	}
+}
@@ -0,0 +1,313 @@
+package migrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/astutils"
+)
+
+// A Finding is a statement that locks or rewrites a table which may
+// already hold rows.
+type Finding struct {
+	// Location is the offset of the statement, or of the part of it that
+	// is reported, in the contents of the file.
+	Location int
+	Message  string
+}
+
+// vetDisable skips the checks of the statement it annotates, as it does for
+// queries.
+const vetDisable = "@sqlc-vet-disable"
+
+// volatileFuncs are the functions whose values, used as the default of a new
+// column, make PostgreSQL 11 and later rewrite the table.
+var volatileFuncs = map[string]bool{
+	"clock_timestamp":    true,
+	"gen_random_uuid":    true,
+	"nextval":            true,
+	"random":             true,
+	"timeofday":          true,
+	"uuid_generate_v1":   true,
+	"uuid_generate_v1mc": true,
+	"uuid_generate_v4":   true,
+}
+
+var serialTypes = map[string]bool{
+	"smallserial": true,
+	"serial":      true,
+	"bigserial":   true,
+	"serial2":     true,
+	"serial4":     true,
+	"serial8":     true,
+}
+
+// A Linter checks the statements of schema and migration files for
+// operations that take long locks on, or rewrite, existing tables. Files
+// must be linted in the order they are applied, so that the linter knows the
+// column types that ALTER COLUMN TYPE starts from.
+type Linter struct {
+	types map[string]string
+}
+
+func NewLinter() *Linter {
+	return &Linter{types: map[string]string{}}
+}
+
+// Lint returns the findings for stmts, the statements parsed from contents
+// by the PostgreSQL engine. Tables created in the same file are empty, so
+// the statements on them are not reported.
+func (l *Linter) Lint(contents string, stmts []ast.Statement) []Finding {
+	created := map[string]bool{}
+	var findings []Finding
+	for _, stmt := range stmts {
+		raw := stmt.Raw
+		if raw == nil {
+			continue
+		}
+		start := statementStart(contents, raw)
+		skip := strings.Contains(contents[raw.StmtLocation:start], vetDisable)
+		report := func(loc int, format string, args ...any) {
+			if skip {
+				return
+			}
+			if loc <= 0 {
+				loc = start
+			}
+			findings = append(findings, Finding{Location: loc, Message: fmt.Sprintf(format, args...)})
+		}
+
+		switch n := raw.Stmt.(type) {
+		case *ast.CreateTableStmt:
+			table := tableKey(n.Name.Schema, n.Name.Name)
+			created[table] = true
+			for _, col := range n.Cols {
+				l.types[table+"."+col.Colname] = parseColumn(columnText(contents, col.Location)).typ
+			}
+
+		case *ast.IndexStmt:
+			if n.Relation == nil || n.Concurrent {
+				continue
+			}
+			table := tableKey(deref(n.Relation.Schemaname), deref(n.Relation.Relname))
+			if created[table] {
+				continue
+			}
+			report(start, "CREATE INDEX without CONCURRENTLY blocks writes to %q while the index is built", table)
+
+		case *ast.AlterTableStmt:
+			if n.Table == nil {
+				continue
+			}
+			table := tableKey(n.Table.Schema, n.Table.Name)
+			for _, item := range n.Cmds.Items {
+				cmd, ok := item.(*ast.AlterTableCmd)
+				if !ok {
+					continue
+				}
+				if cmd.Def != nil && cmd.Def.Location > 0 {
+					switch cmd.Subtype {
+					case ast.AT_AddColumn:
+						l.types[table+"."+cmd.Def.Colname] = parseColumn(columnText(contents, cmd.Def.Location)).typ
+					case ast.AT_AlterColumnType:
+						to := alterColumnType(columnText(contents, cmd.Def.Location))
+						from, known := l.types[table+"."+cmd.Def.Colname]
+						l.types[table+"."+cmd.Def.Colname] = to
+						if created[table] {
+							continue
+						}
+						if !known {
+							report(cmd.Def.Location, "changing the type of column %q to %s may rewrite %q", cmd.Def.Colname, to, table)
+						} else if typeRewrites(from, to) {
+							report(cmd.Def.Location, "changing the type of column %q from %s to %s rewrites %q", cmd.Def.Colname, from, to, table)
+						}
+						continue
+					}
+				}
+				if created[table] {
+					continue
+				}
+				switch cmd.Subtype {
+				case ast.AT_AddColumn:
+					l.lintAddColumn(table, cmd.Def, report)
+				case ast.AT_SetNotNull:
+					report(start, "SET NOT NULL on column %q blocks %q while its rows are checked; add a CHECK (%s IS NOT NULL) NOT VALID constraint and validate it first", deref(cmd.Name), table, deref(cmd.Name))
+				case ast.AT_AddConstraint:
+					lintAddConstraint(table, cmd.Constraint, report)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// lintAddColumn reports a new column that makes PostgreSQL rewrite the table
+// or fails on its rows. It assumes PostgreSQL 11 or later, which stores a
+// non-volatile default in the catalog instead of writing it to every row;
+// earlier versions rewrite the table for any default.
+func (l *Linter) lintAddColumn(table string, col *ast.ColumnDef, report func(int, string, ...any)) {
+	if col == nil {
+		return
+	}
+	if col.TypeName != nil && serialTypes[col.TypeName.Name] {
+		report(col.Location, "adding %s column %q rewrites %q", col.TypeName.Name, col.Colname, table)
+		return
+	}
+	var def ast.Node
+	if col.Constraints != nil {
+		for _, item := range col.Constraints.Items {
+			if c, ok := item.(*ast.Constraint); ok && c.Contype == ast.CONSTR_DEFAULT {
+				def = c.RawExpr
+			}
+		}
+	}
+	if def == nil {
+		if col.IsNotNull {
+			report(col.Location, "adding NOT NULL column %q without a default fails when %q has rows", col.Colname, table)
+		}
+		return
+	}
+	var volatile string
+	astutils.Walk(astutils.VisitorFunc(func(node ast.Node) {
+		if fn, ok := node.(*ast.FuncCall); ok && fn.Func != nil && volatileFuncs[fn.Func.Name] && volatile == "" {
+			volatile = fn.Func.Name
+		}
+	}), def)
+	if volatile != "" {
+		report(col.Location, "adding column %q with the volatile default %s() rewrites %q", col.Colname, volatile, table)
+	}
+}
+
+func lintAddConstraint(table string, c *ast.Constraint, report func(int, string, ...any)) {
+	if c == nil {
+		return
+	}
+	switch c.Contype {
+	case ast.CONSTR_FOREIGN, ast.CONSTR_CHECK:
+		if c.SkipValidation {
+			return
+		}
+		kind := "FOREIGN KEY"
+		if c.Contype == ast.CONSTR_CHECK {
+			kind = "CHECK"
+		}
+		report(c.Location, "adding a %s constraint without NOT VALID blocks writes to %q while its rows are checked; add it NOT VALID and VALIDATE CONSTRAINT later", kind, table)
+	case ast.CONSTR_PRIMARY, ast.CONSTR_UNIQUE:
+		if c.Indexname != nil {
+			return
+		}
+		kind := "PRIMARY KEY"
+		if c.Contype == ast.CONSTR_UNIQUE {
+			kind = "UNIQUE"
+		}
+		report(c.Location, "adding a %s constraint blocks writes to %q while its index is built; build the index CONCURRENTLY and add the constraint USING INDEX", kind, table)
+	}
+}
+
+// alterColumnType returns the new type in the text of an ALTER COLUMN TYPE
+// command, which starts at the column name.
+func alterColumnType(text string) string {
+	tokens := columnTokens(text)
+	if len(tokens) > 0 {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 {
+		switch strings.ToUpper(tokens[0]) {
+		case "SET", "DATA", "TYPE":
+			tokens = tokens[1:]
+			continue
+		}
+		break
+	}
+	var typ []string
+	for _, t := range tokens {
+		if columnKeywords[strings.ToUpper(t)] {
+			break
+		}
+		typ = append(typ, t)
+	}
+	return strings.Join(typ, " ")
+}
+
+// typeRewrites reports whether PostgreSQL rewrites the table to change the
+// type of a column from one type to another. Only the changes that are known
+// to keep the stored values are allowed.
+func typeRewrites(from, to string) bool {
+	fromName, fromMods := splitType(from)
+	toName, toMods := splitType(to)
+	if fromName == toName && fromMods == toMods {
+		return false
+	}
+	switch {
+	case fromName == "varchar" && toName == "text":
+		return false
+	case fromName == "text" && toName == "varchar" && toMods == "":
+		return false
+	case fromName == "varchar" && toName == "varchar":
+		return !widens(fromMods, toMods, false)
+	case fromName == "numeric" && toName == "numeric":
+		return !widens(fromMods, toMods, true)
+	}
+	return true
+}
+
+// splitType returns the canonical name of a type and its modifiers.
+func splitType(typ string) (string, string) {
+	typ = strings.ToLower(normalize(typ))
+	name, mods := typ, ""
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		if j := strings.IndexByte(typ[i:], ')'); j >= 0 {
+			mods = strings.ReplaceAll(typ[i+1:i+j], " ", "")
+			name = normalize(typ[:i] + typ[i+j+1:])
+		}
+	}
+	switch name {
+	case "character varying":
+		name = "varchar"
+	case "decimal":
+		name = "numeric"
+	}
+	return name, mods
+}
+
+// widens reports whether the modifiers to allow all the values of from. For
+// numeric, the scale must be kept.
+func widens(from, to string, numeric bool) bool {
+	if to == "" {
+		return true
+	}
+	if from == "" {
+		return false
+	}
+	fromMods, toMods := strings.Split(from, ","), strings.Split(to, ",")
+	if numeric {
+		fromScale, toScale := "0", "0"
+		if len(fromMods) > 1 {
+			fromScale = fromMods[1]
+		}
+		if len(toMods) > 1 {
+			toScale = toMods[1]
+		}
+		if fromScale != toScale {
+			return false
+		}
+	}
+	f, err := strconv.Atoi(fromMods[0])
+	if err != nil {
+		return false
+	}
+	t, err := strconv.Atoi(toMods[0])
+	if err != nil {
+		return false
+	}
+	return t >= f
+}
+
+func tableKey(schema, name string) string {
+	if schema == "" || schema == "public" {
+		return name
+	}
+	return schema + "." + name
+}
@@ -0,0 +1,69 @@
+package migrations_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/engine/postgresql"
+	"github.com/sqlc-dev/sqlc/internal/migrations"
+)
+
+func TestLint(t *testing.T) {
+	linter := migrations.NewLinter()
+	lint := func(sql string) []string {
+		t.Helper()
+		stmts, err := postgresql.NewParser().Parse(strings.NewReader(sql))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, f := range linter.Lint(sql, stmts) {
+			messages = append(messages, f.Message)
+		}
+		return messages
+	}
+
+	if got := lint(`
+CREATE TABLE tickets (
+  id    BIGINT PRIMARY KEY,
+  title VARCHAR(100) NOT NULL,
+  price NUMERIC(10, 2) NOT NULL
+);
+CREATE INDEX tickets_title_idx ON tickets (title);
+ALTER TABLE tickets ADD COLUMN note TEXT NOT NULL;
+`); len(got) != 0 {
+		t.Errorf("statements on a new table were reported: %v", got)
+	}
+
+	got := lint(`
+CREATE INDEX tickets_price_idx ON tickets (price);
+CREATE INDEX CONCURRENTLY tickets_id_price_idx ON tickets (id, price);
+ALTER TABLE tickets ALTER COLUMN title TYPE varchar(255), ALTER COLUMN price TYPE numeric(12,2);
+ALTER TABLE tickets ALTER COLUMN title TYPE INT USING title::int;
+ALTER TABLE tickets ADD COLUMN opened TIMESTAMPTZ NOT NULL DEFAULT now(), ADD COLUMN closed DATE NOT NULL;
+ALTER TABLE tickets ADD COLUMN token UUID DEFAULT gen_random_uuid();
+ALTER TABLE tickets ALTER COLUMN note SET NOT NULL;
+ALTER TABLE tickets ADD CONSTRAINT tickets_owner_fk FOREIGN KEY (owner_id) REFERENCES users (id);
+ALTER TABLE tickets ADD CONSTRAINT tickets_owner_fk FOREIGN KEY (owner_id) REFERENCES users (id) NOT VALID;
+ALTER TABLE tickets ADD CONSTRAINT tickets_pkey PRIMARY KEY (id);
+ALTER TABLE tickets ADD CONSTRAINT tickets_title_key UNIQUE (title);
+ALTER TABLE tickets ADD CONSTRAINT tickets_token_key UNIQUE USING INDEX tickets_token_idx;
+-- @sqlc-vet-disable
+CREATE INDEX tickets_note_idx ON tickets (note);
+`)
+	want := []string{
+		`CREATE INDEX without CONCURRENTLY blocks writes to "tickets" while the index is built`,
+		`changing the type of column "title" from varchar(255) to INT rewrites "tickets"`,
+		`adding NOT NULL column "closed" without a default fails when "tickets" has rows`,
+		`adding column "token" with the volatile default gen_random_uuid() rewrites "tickets"`,
+		`SET NOT NULL on column "note" blocks "tickets" while its rows are checked; add a CHECK (note IS NOT NULL) NOT VALID constraint and validate it first`,
+		`adding a FOREIGN KEY constraint without NOT VALID blocks writes to "tickets" while its rows are checked; add it NOT VALID and VALIDATE CONSTRAINT later`,
+		`adding a PRIMARY KEY constraint blocks writes to "tickets" while its index is built; build the index CONCURRENTLY and add the constraint USING INDEX`,
+		`adding a UNIQUE constraint blocks writes to "tickets" while its index is built; build the index CONCURRENTLY and add the constraint USING INDEX`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("findings differed (-want +got):\n%s", diff)
+	}
+}
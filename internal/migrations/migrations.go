@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"bufio"
+	"fmt"
 	"strings"
 )
 
@@ -36,3 +37,66 @@ func IsDown(filename string) bool {
 	// Remove golang-migrate rollback files.
 	return strings.HasSuffix(filename, ".down.sql")
 }
+
+// AddsEnumValue reports whether stmts add a value to an enum. A value added
+// in a transaction can't be used before the transaction commits, so such a
+// migration is run outside of one.
+func AddsEnumValue(stmts []string) bool {
+	for _, stmt := range stmts {
+		if strings.HasPrefix(stmt, "ALTER TYPE ") && strings.Contains(stmt, " ADD VALUE ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Format lays out the up and down statements of a migration the way a
+// migration tool expects them, so that RemoveRollbackStatements finds the
+// rollback marker. A section that adds enum values is marked to run outside
+// of a transaction, except for tern, which has no such marker.
+func Format(tool string, up, down []string) (string, error) {
+	var header, upMarker, downMarker string
+	switch tool {
+	case "goose":
+		// goose runs the whole file either in a transaction or not.
+		if AddsEnumValue(up) || AddsEnumValue(down) {
+			header = "-- +goose NO TRANSACTION"
+		}
+		upMarker, downMarker = "-- +goose Up", "-- +goose Down"
+	case "sql-migrate":
+		upMarker, downMarker = "-- +migrate Up", "-- +migrate Down"
+		if AddsEnumValue(up) {
+			upMarker += " notransaction"
+		}
+		if AddsEnumValue(down) {
+			downMarker += " notransaction"
+		}
+	case "tern":
+		downMarker = "---- create above / drop below ----"
+	case "dbmate":
+		upMarker, downMarker = "-- migrate:up", "-- migrate:down"
+		if AddsEnumValue(up) {
+			upMarker += " transaction:false"
+		}
+		if AddsEnumValue(down) {
+			downMarker += " transaction:false"
+		}
+	default:
+		return "", fmt.Errorf("unknown migration format %q, expected goose, sql-migrate, tern or dbmate", tool)
+	}
+	var b strings.Builder
+	if header != "" {
+		b.WriteString(header + "\n")
+	}
+	if upMarker != "" {
+		b.WriteString(upMarker + "\n")
+	}
+	for _, stmt := range up {
+		b.WriteString(stmt + "\n")
+	}
+	b.WriteString("\n" + downMarker + "\n")
+	for _, stmt := range down {
+		b.WriteString(stmt + "\n")
+	}
+	return b.String(), nil
+}
@@ -88,3 +88,25 @@ func TestRemoveGolangMigrateRollback(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatEnumValues(t *testing.T) {
+	up := []string{"ALTER TYPE status ADD VALUE 'pending' AFTER 'open';"}
+	down := []string{"-- unsupported change: PostgreSQL can't remove the value 'pending' of enum status"}
+	for tool, want := range map[string]string{
+		"goose":       "-- +goose NO TRANSACTION\n-- +goose Up\n" + up[0] + "\n\n-- +goose Down\n" + down[0] + "\n",
+		"sql-migrate": "-- +migrate Up notransaction\n" + up[0] + "\n\n-- +migrate Down\n" + down[0] + "\n",
+		"dbmate":      "-- migrate:up transaction:false\n" + up[0] + "\n\n-- migrate:down\n" + down[0] + "\n",
+		"tern":        up[0] + "\n\n---- create above / drop below ----\n" + down[0] + "\n",
+	} {
+		got, err := Format(tool, up, down)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s migration mismatch:\n%s", tool, diff)
+		}
+		if RemoveRollbackStatements(got) != RemoveRollbackStatements(got+"SELECT 1;\n") {
+			t.Errorf("%s migration: rollback marker not found", tool)
+		}
+	}
+}
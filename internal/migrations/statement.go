@@ -0,0 +1,177 @@
+package migrations
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+)
+
+// columnKeywords start the constraints that follow the type of a column
+// definition.
+var columnKeywords = map[string]bool{
+	"NOT":        true,
+	"NULL":       true,
+	"DEFAULT":    true,
+	"PRIMARY":    true,
+	"UNIQUE":     true,
+	"CHECK":      true,
+	"REFERENCES": true,
+	"CONSTRAINT": true,
+	"GENERATED":  true,
+	"COLLATE":    true,
+	"USING":      true,
+}
+
+type columnDef struct {
+	typ string
+	def string
+}
+
+// statementStart returns the offset of a statement in contents, after the
+// comments that precede it.
+func statementStart(contents string, raw *ast.RawStmt) int {
+	i := raw.StmtLocation
+	for i < len(contents) {
+		rest := contents[i:]
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		i += len(rest) - len(trimmed)
+		switch {
+		case strings.HasPrefix(trimmed, "--"):
+			j := strings.IndexByte(trimmed, '\n')
+			if j < 0 {
+				return len(contents)
+			}
+			i += j + 1
+		case strings.HasPrefix(trimmed, "/*"):
+			j := strings.Index(trimmed, "*/")
+			if j < 0 {
+				return len(contents)
+			}
+			i += j + 2
+		default:
+			return i
+		}
+	}
+	return len(contents)
+}
+
+// statementText returns the text of a statement in contents, without the
+// comments that precede it and the terminating semicolon.
+func statementText(contents string, raw *ast.RawStmt) string {
+	end := len(contents)
+	if raw.StmtLen > 0 && raw.StmtLocation+raw.StmtLen < end {
+		end = raw.StmtLocation + raw.StmtLen
+	}
+	start := statementStart(contents, raw)
+	if start >= end {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(contents[start:end]), ";"))
+}
+
+// columnText returns the definition of the column that starts at loc in a
+// CREATE or ALTER TABLE statement, up to the comma, parenthesis or semicolon
+// that ends it, with comments removed.
+func columnText(contents string, loc int) string {
+	if loc < 0 || loc >= len(contents) {
+		return ""
+	}
+	var b strings.Builder
+	depth := 0
+	for i := loc; i < len(contents); i++ {
+		c := contents[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := strings.IndexByte(contents[i+1:], c)
+			if j < 0 {
+				b.WriteString(contents[i:])
+				return normalize(b.String())
+			}
+			b.WriteString(contents[i : i+j+2])
+			i += j + 1
+			continue
+		case strings.HasPrefix(contents[i:], "--"):
+			j := strings.IndexByte(contents[i:], '\n')
+			if j < 0 {
+				return normalize(b.String())
+			}
+			i += j
+			c = '\n'
+		case c == '(':
+			depth++
+		case c == ')' && depth == 0, c == ',' && depth == 0, c == ';':
+			return normalize(b.String())
+		case c == ')':
+			depth--
+		}
+		b.WriteByte(c)
+	}
+	return normalize(b.String())
+}
+
+// parseColumn splits a column definition into its type and its default.
+func parseColumn(text string) columnDef {
+	tokens := columnTokens(text)
+	if len(tokens) < 2 {
+		return columnDef{}
+	}
+	var def columnDef
+	i := 1
+	var typ []string
+	for ; i < len(tokens) && !columnKeywords[strings.ToUpper(tokens[i])]; i++ {
+		typ = append(typ, tokens[i])
+	}
+	def.typ = strings.Join(typ, " ")
+	for ; i < len(tokens); i++ {
+		if strings.ToUpper(tokens[i]) != "DEFAULT" || i+1 == len(tokens) {
+			continue
+		}
+		expr := []string{tokens[i+1]}
+		for i += 2; i < len(tokens) && !columnKeywords[strings.ToUpper(tokens[i])]; i++ {
+			expr = append(expr, tokens[i])
+		}
+		def.def = strings.Join(expr, " ")
+		break
+	}
+	return def
+}
+
+// columnTokens splits text at white space outside of parentheses and
+// quotes.
+func columnTokens(text string) []string {
+	var tokens []string
+	start, depth := -1, 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if start < 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			start = i
+		}
+		switch {
+		case c == '\'' || c == '"':
+			if j := strings.IndexByte(text[i+1:], c); j >= 0 {
+				i += j + 1
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case (c == ' ' || c == '\t' || c == '\n' || c == '\r') && depth == 0 && start >= 0:
+			tokens = append(tokens, text[start:i])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
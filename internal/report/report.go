@@ -0,0 +1,249 @@
+// Package report renders the queries of a configuration as a document for
+// the review of their patterns, e.g. by DBAs designing indexes.
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sqlc-dev/sqlc/internal/codegen/golang"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+	"github.com/sqlc-dev/sqlc/internal/sql/astutils"
+	"github.com/sqlc-dev/sqlc/internal/vet"
+)
+
+//go:embed templates/*
+var templates embed.FS
+
+type Report struct {
+	Packages []Package `json:"packages"`
+}
+
+type Package struct {
+	Name    string  `json:"name"`
+	Queries []Query `json:"queries"`
+}
+
+type Query struct {
+	Name     string `json:"name"`
+	Cmd      string `json:"cmd"`
+	Filename string `json:"filename"`
+	SQL      string `json:"sql"`
+	// Reads and Writes are the tables of the catalog that the query reads
+	// and writes.
+	Reads        []string `json:"reads"`
+	Writes       []string `json:"writes"`
+	Params       []Param  `json:"params"`
+	Cache        string   `json:"cache,omitempty"`
+	Timeout      string   `json:"timeout,omitempty"`
+	Invalidates  []string `json:"invalidates,omitempty"`
+	AllowReplica bool     `json:"allow_replica"`
+	// Plan is the EXPLAIN output of the query, a line per plan node, when
+	// the report is made with a database.
+	Plan string `json:"plan,omitempty"`
+}
+
+type Param struct {
+	Number  int32  `json:"number"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	NotNull bool   `json:"not_null"`
+}
+
+// NewPackage returns the queries of req. stmts are the parsed statements of
+// the queries, in the same order.
+func NewPackage(name string, req *plugin.CodeGenRequest, stmts []ast.Node) Package {
+	tables := catalogTables(req.Catalog)
+	pkg := Package{Name: name}
+	for i, q := range req.Queries {
+		query := Query{
+			Name:         q.Name,
+			Cmd:          q.Cmd,
+			Filename:     q.Filename,
+			SQL:          q.Text,
+			Cache:        q.Options[golang.WPgxOptionKeyCache],
+			Timeout:      q.Options[golang.WpgxOptionKeyTimeout],
+			AllowReplica: q.Options[golang.WpgxOptionKeyAllowReplica] == "true",
+		}
+		if v, ok := q.Options[golang.WPgxOptionKeyInvalidate]; ok {
+			for _, name := range strings.Split(strings.Trim(v, " []"), ",") {
+				query.Invalidates = append(query.Invalidates, strings.TrimSpace(name))
+			}
+		}
+		for _, p := range q.Params {
+			param := Param{Number: p.Number}
+			if p.Column != nil {
+				param.Name = p.Column.Name
+				param.NotNull = p.Column.NotNull
+				if p.Column.Type != nil {
+					param.Type = p.Column.Type.Name
+				}
+				if p.Column.IsArray {
+					param.Type += "[]"
+				}
+			}
+			query.Params = append(query.Params, param)
+		}
+		if i < len(stmts) && stmts[i] != nil {
+			query.Reads, query.Writes = Tables(stmts[i], tables)
+		}
+		pkg.Queries = append(pkg.Queries, query)
+	}
+	return pkg
+}
+
+// catalogTables returns the names of the tables of the catalog, as they can
+// be written in queries.
+func catalogTables(c *plugin.Catalog) map[string]string {
+	tables := map[string]string{}
+	if c == nil {
+		return tables
+	}
+	for _, s := range c.Schemas {
+		for _, t := range s.Tables {
+			if t.Rel == nil {
+				continue
+			}
+			name := s.Name + "." + t.Rel.Name
+			tables[name] = name
+			if s.Name == c.DefaultSchema {
+				tables[name] = t.Rel.Name
+				tables[t.Rel.Name] = t.Rel.Name
+			}
+		}
+	}
+	return tables
+}
+
+// Tables returns the sorted tables that stmt reads and writes. tables maps
+// the names a table can be referenced by to the name to report, so that
+// common table expressions are left out.
+func Tables(stmt ast.Node, tables map[string]string) (reads, writes []string) {
+	written := map[*ast.RangeVar]bool{}
+	writeSet := map[string]bool{}
+	readSet := map[string]bool{}
+	add := func(set map[string]bool, rv *ast.RangeVar) {
+		if rv == nil || rv.Relname == nil {
+			return
+		}
+		name := *rv.Relname
+		if rv.Schemaname != nil {
+			name = *rv.Schemaname + "." + name
+		}
+		if t, ok := tables[name]; ok {
+			set[t] = true
+		}
+	}
+	targets := func(l *ast.List) {
+		if l == nil {
+			return
+		}
+		for _, item := range l.Items {
+			if rv, ok := item.(*ast.RangeVar); ok {
+				written[rv] = true
+				add(writeSet, rv)
+			}
+		}
+	}
+	astutils.Search(stmt, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.InsertStmt:
+			if n.Relation != nil {
+				written[n.Relation] = true
+				add(writeSet, n.Relation)
+			}
+		case *ast.UpdateStmt:
+			targets(n.Relations)
+		case *ast.DeleteStmt:
+			targets(n.Relations)
+		}
+		return false
+	})
+	astutils.Search(stmt, func(node ast.Node) bool {
+		if rv, ok := node.(*ast.RangeVar); ok && !written[rv] {
+			add(readSet, rv)
+		}
+		return false
+	})
+	return sorted(readSet), sorted(writeSet)
+}
+
+func sorted(set map[string]bool) []string {
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatPlan returns the nodes of a PostgreSQL EXPLAIN output, a line per
+// node indented by its depth, e.g.
+//
+//	Index Scan using books_pkey on books (cost=0.15..8.17 rows=1)
+//	  Index Cond: (id = 1)
+func FormatPlan(explain *vet.PostgreSQLExplain) string {
+	if explain == nil || explain.Plan == nil {
+		return ""
+	}
+	var b strings.Builder
+	formatPlan(&b, explain.Plan, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatPlan(b *strings.Builder, plan *vet.PostgreSQLExplain_Plan, depth int) {
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent + plan.NodeType)
+	if plan.IndexName != "" {
+		b.WriteString(" using " + plan.IndexName)
+	}
+	if plan.RelationName != "" {
+		b.WriteString(" on " + plan.RelationName)
+		if plan.Alias != "" && plan.Alias != plan.RelationName {
+			b.WriteString(" " + plan.Alias)
+		}
+	}
+	fmt.Fprintf(b, " (cost=%.2f..%.2f rows=%d)\n", plan.StartupCost, plan.TotalCost, plan.PlanRows)
+	for _, cond := range []struct{ name, value string }{
+		{"Index Cond", plan.IndexCond},
+		{"Hash Cond", plan.HashCond},
+	} {
+		if cond.value != "" {
+			fmt.Fprintf(b, "%s  %s: %s\n", indent, cond.name, cond.value)
+		}
+	}
+	if len(plan.SortKey) > 0 {
+		fmt.Fprintf(b, "%s  Sort Key: %s\n", indent, strings.Join(plan.SortKey, ", "))
+	}
+	for _, p := range plan.Plans {
+		formatPlan(b, p, depth+1)
+	}
+}
+
+var funcs = map[string]any{
+	"join": strings.Join,
+}
+
+// Markdown writes the report as a Markdown document.
+func (r Report) Markdown(w io.Writer) error {
+	tmpl, err := template.New("report.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.md.tmpl")
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+// HTML writes the report as a standalone HTML page.
+func (r Report) HTML(w io.Writer) error {
+	tmpl, err := htmltemplate.New("report.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.html.tmpl")
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
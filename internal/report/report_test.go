@@ -0,0 +1,74 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sqlc-dev/sqlc/internal/engine/postgresql"
+	"github.com/sqlc-dev/sqlc/internal/plugin"
+	"github.com/sqlc-dev/sqlc/internal/vet"
+)
+
+func TestTables(t *testing.T) {
+	tables := catalogTables(&plugin.Catalog{
+		DefaultSchema: "public",
+		Schemas: []*plugin.Schema{
+			{Name: "public", Tables: []*plugin.Table{{Rel: &plugin.Identifier{Name: "books"}}, {Rel: &plugin.Identifier{Name: "authors"}}}},
+			{Name: "audit", Tables: []*plugin.Table{{Rel: &plugin.Identifier{Name: "events"}}}},
+		},
+	})
+	for _, tc := range []struct {
+		sql           string
+		reads, writes []string
+	}{
+		{
+			sql:   "SELECT * FROM books b JOIN public.authors a ON a.id = b.author_id",
+			reads: []string{"authors", "books"},
+		},
+		{
+			sql:    "WITH old AS (SELECT id FROM books WHERE year < $1) DELETE FROM books USING old WHERE books.id = old.id",
+			reads:  []string{"books"},
+			writes: []string{"books"},
+		},
+		{
+			sql:    "UPDATE authors SET name = $1 WHERE id = $2",
+			writes: []string{"authors"},
+		},
+		{
+			sql:    "INSERT INTO audit.events (book_id) SELECT id FROM books RETURNING *",
+			reads:  []string{"books"},
+			writes: []string{"audit.events"},
+		},
+	} {
+		stmts, err := postgresql.NewParser().Parse(strings.NewReader(tc.sql))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reads, writes := Tables(stmts[0].Raw.Stmt, tables)
+		if diff := cmp.Diff(tc.reads, reads); diff != "" {
+			t.Errorf("%s: reads mismatch (-want +got):\n%s", tc.sql, diff)
+		}
+		if diff := cmp.Diff(tc.writes, writes); diff != "" {
+			t.Errorf("%s: writes mismatch (-want +got):\n%s", tc.sql, diff)
+		}
+	}
+}
+
+func TestFormatPlan(t *testing.T) {
+	plan := FormatPlan(&vet.PostgreSQLExplain{Plan: &vet.PostgreSQLExplain_Plan{
+		NodeType: "Sort", TotalCost: 12.5, PlanRows: 10, SortKey: []string{"b.year"},
+		Plans: []*vet.PostgreSQLExplain_Plan{{
+			NodeType: "Index Scan", IndexName: "books_title_idx", RelationName: "books", Alias: "b",
+			TotalCost: 8.25, PlanRows: 10, IndexCond: "(title = 'x'::text)",
+		}},
+	}})
+	want := `Sort (cost=0.00..12.50 rows=10)
+  Sort Key: b.year
+  Index Scan using books_title_idx on books b (cost=0.00..8.25 rows=10)
+    Index Cond: (title = 'x'::text)`
+	if diff := cmp.Diff(want, plan); diff != "" {
+		t.Errorf("plan mismatch (-want +got):\n%s", diff)
+	}
+}
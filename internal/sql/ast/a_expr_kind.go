@@ -1,5 +1,24 @@
 package ast
 
+// https://github.com/pganalyze/libpg_query/blob/15-latest/protobuf/pg_query.proto
+const (
+	_ A_Expr_Kind = iota
+	A_Expr_Kind_OP
+	A_Expr_Kind_OP_ANY
+	A_Expr_Kind_OP_ALL
+	A_Expr_Kind_DISTINCT
+	A_Expr_Kind_NOT_DISTINCT
+	A_Expr_Kind_NULLIF
+	A_Expr_Kind_IN
+	A_Expr_Kind_LIKE
+	A_Expr_Kind_ILIKE
+	A_Expr_Kind_SIMILAR
+	A_Expr_Kind_BETWEEN
+	A_Expr_Kind_NOT_BETWEEN
+	A_Expr_Kind_BETWEEN_SYM
+	A_Expr_Kind_NOT_BETWEEN_SYM
+)
+
 type A_Expr_Kind uint
 
 func (n *A_Expr_Kind) Pos() int {
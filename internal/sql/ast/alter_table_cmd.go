@@ -6,6 +6,7 @@ const (
 	AT_DropColumn
 	AT_DropNotNull
 	AT_SetNotNull
+	AT_AddConstraint
 )
 
 type AlterTableType int
@@ -22,18 +23,21 @@ func (t AlterTableType) String() string {
 		return "DropNotNull"
 	case AT_SetNotNull:
 		return "SetNotNull"
+	case AT_AddConstraint:
+		return "AddConstraint"
 	default:
 		return "Unknown"
 	}
 }
 
 type AlterTableCmd struct {
-	Subtype   AlterTableType
-	Name      *string
-	Def       *ColumnDef
-	Newowner  *RoleSpec
-	Behavior  DropBehavior
-	MissingOk bool
+	Subtype    AlterTableType
+	Name       *string
+	Def        *ColumnDef
+	Constraint *Constraint
+	Newowner   *RoleSpec
+	Behavior   DropBehavior
+	MissingOk  bool
 }
 
 func (n *AlterTableCmd) Pos() int {
@@ -2,6 +2,7 @@ package ast
 
 type CompositeTypeStmt struct {
 	TypeName *TypeName
+	Cols     []*ColumnDef
 }
 
 func (n *CompositeTypeStmt) Pos() int {
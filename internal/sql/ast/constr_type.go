@@ -1,5 +1,24 @@
 package ast
 
+// https://github.com/pganalyze/libpg_query/blob/15-latest/protobuf/pg_query.proto
+const (
+	_ ConstrType = iota
+	CONSTR_NULL
+	CONSTR_NOTNULL
+	CONSTR_DEFAULT
+	CONSTR_IDENTITY
+	CONSTR_GENERATED
+	CONSTR_CHECK
+	CONSTR_PRIMARY
+	CONSTR_UNIQUE
+	CONSTR_EXCLUSION
+	CONSTR_FOREIGN
+	CONSTR_ATTR_DEFERRABLE
+	CONSTR_ATTR_NOT_DEFERRABLE
+	CONSTR_ATTR_DEFERRED
+	CONSTR_ATTR_IMMEDIATE
+)
+
 type ConstrType uint
 
 func (n *ConstrType) Pos() int {
@@ -7,6 +7,8 @@ type CreateTableStmt struct {
 	ReferTable  *TableName
 	Comment     string
 	Inherits    []*TableName
+	// PrimaryKey lists the columns of the primary key, in key order.
+	PrimaryKey []string
 }
 
 func (n *CreateTableStmt) Pos() int {
@@ -1,5 +1,12 @@
 package ast
 
+// https://github.com/pganalyze/libpg_query/blob/15-latest/protobuf/pg_query.proto
+const (
+	_ NullTestType = iota
+	NullTestTypeIsNull
+	NullTestTypeIsNotNull
+)
+
 type NullTestType uint
 
 func (n *NullTestType) Pos() int {
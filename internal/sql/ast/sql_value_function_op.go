@@ -1,5 +1,25 @@
 package ast
 
+// https://github.com/pganalyze/libpg_query/blob/15-latest/protobuf/pg_query.proto
+const (
+	_ SQLValueFunctionOp = iota
+	SQLValueFunctionOpCurrentDate
+	SQLValueFunctionOpCurrentTime
+	SQLValueFunctionOpCurrentTimeN
+	SQLValueFunctionOpCurrentTimestamp
+	SQLValueFunctionOpCurrentTimestampN
+	SQLValueFunctionOpLocaltime
+	SQLValueFunctionOpLocaltimeN
+	SQLValueFunctionOpLocaltimestamp
+	SQLValueFunctionOpLocaltimestampN
+	SQLValueFunctionOpCurrentRole
+	SQLValueFunctionOpCurrentUser
+	SQLValueFunctionOpUser
+	SQLValueFunctionOpSessionUser
+	SQLValueFunctionOpCurrentCatalog
+	SQLValueFunctionOpCurrentSchema
+)
+
 type SQLValueFunctionOp uint
 
 func (n *SQLValueFunctionOp) Pos() int {
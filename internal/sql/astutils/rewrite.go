@@ -365,6 +365,7 @@ func (a *application) apply(parent ast.Node, name string, iter *iterator, n ast.
 	case *ast.AlterTableCmd:
 		a.apply(n, "Newowner", nil, n.Newowner)
 		a.apply(n, "Def", nil, n.Def)
+		a.apply(n, "Constraint", nil, n.Constraint)
 
 	case *ast.AlterTableMoveAllStmt:
 		a.apply(n, "Roles", nil, n.Roles)
@@ -428,6 +428,9 @@ func Walk(f Visitor, node ast.Node) {
 		if n.Def != nil {
 			Walk(f, n.Def)
 		}
+		if n.Constraint != nil {
+			Walk(f, n.Constraint)
+		}
 
 	case *ast.AlterTableMoveAllStmt:
 		if n.Roles != nil {
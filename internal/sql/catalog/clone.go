@@ -0,0 +1,64 @@
+package catalog
+
+// Clone returns a copy of the catalog that can be updated without affecting
+// the original. Functions and operators are never modified in place, so they
+// are shared.
+func (c *Catalog) Clone() *Catalog {
+	out := *c
+	out.SearchPath = append([]string(nil), c.SearchPath...)
+	out.RawSQLs = append([]string(nil), c.RawSQLs...)
+	out.Extensions = make(map[string]struct{}, len(c.Extensions))
+	for k, v := range c.Extensions {
+		out.Extensions[k] = v
+	}
+	out.Schemas = make([]*Schema, 0, len(c.Schemas))
+	for _, s := range c.Schemas {
+		out.Schemas = append(out.Schemas, s.clone())
+	}
+	return &out
+}
+
+func (s *Schema) clone() *Schema {
+	out := *s
+	out.Funcs = append([]*Function(nil), s.Funcs...)
+	out.Operators = append([]*Operator(nil), s.Operators...)
+	out.Tables = make([]*Table, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		out.Tables = append(out.Tables, t.clone())
+	}
+	out.Types = make([]Type, 0, len(s.Types))
+	for _, t := range s.Types {
+		switch t := t.(type) {
+		case *Enum:
+			e := *t
+			e.Vals = append([]string(nil), t.Vals...)
+			out.Types = append(out.Types, &e)
+		case *CompositeType:
+			ct := *t
+			ct.Columns = make([]*Column, 0, len(t.Columns))
+			for _, c := range t.Columns {
+				col := *c
+				ct.Columns = append(ct.Columns, &col)
+			}
+			out.Types = append(out.Types, &ct)
+		default:
+			out.Types = append(out.Types, t)
+		}
+	}
+	return &out
+}
+
+func (t *Table) clone() *Table {
+	out := *t
+	if t.Rel != nil {
+		rel := *t.Rel
+		out.Rel = &rel
+	}
+	out.PrimaryKey = append([]string(nil), t.PrimaryKey...)
+	out.Columns = make([]*Column, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		col := *c
+		out.Columns = append(out.Columns, &col)
+	}
+	return &out
+}
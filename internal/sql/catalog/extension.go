@@ -25,5 +25,6 @@ func (c *Catalog) createExtension(stmt *ast.CreateExtensionStmt) error {
 	}
 	// TODO: Error on duplicate functions
 	s.Funcs = append(s.Funcs, ext.Funcs...)
+	s.Operators = append(s.Operators, ext.Operators...)
 	return nil
 }
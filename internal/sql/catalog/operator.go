@@ -0,0 +1,15 @@
+package catalog
+
+import (
+	"github.com/sqlc-dev/sqlc/internal/sql/ast"
+)
+
+// Operator describes a database operator
+//
+// Left is nil for prefix operators.
+type Operator struct {
+	Name       string
+	Left       *ast.TypeName
+	Right      *ast.TypeName
+	ReturnType *ast.TypeName
+}
@@ -32,6 +32,22 @@ func (c *Catalog) ListFuncsByName(rel *ast.FuncName) ([]Function, error) {
 	return funcs, nil
 }
 
+func (c *Catalog) ListOperatorsByName(name string) ([]Operator, error) {
+	var ops []Operator
+	for _, ns := range c.schemasToSearch("") {
+		s, err := c.getSchema(ns)
+		if err != nil {
+			return nil, err
+		}
+		for i := range s.Operators {
+			if s.Operators[i].Name == name {
+				ops = append(ops, *s.Operators[i])
+			}
+		}
+	}
+	return ops, nil
+}
+
 func (c *Catalog) ResolveFuncCall(call *ast.FuncCall) (*Function, error) {
 	// Do not validate unknown functions
 	funs, err := c.ListFuncsByName(call.Func)
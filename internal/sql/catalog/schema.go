@@ -14,6 +14,9 @@ type Schema struct {
 	Tables []*Table
 	Types  []Type
 	Funcs  []*Function
+	// Operators are not updated by DDL statements; they come from the
+	// built-in schemas and extensions.
+	Operators []*Operator
 
 	Comment string
 }
@@ -17,6 +17,8 @@ type Table struct {
 	Columns       []*Column
 	Comment       string
 	GenerateModel bool
+	// PrimaryKey lists the columns of the primary key, in key order.
+	PrimaryKey []string
 }
 
 func checkMissing(err error, missingOK bool) error {
@@ -52,18 +54,33 @@ func (table *Table) addColumn(cmd *ast.AlterTableCmd) error {
 		}
 	}
 
+	hasDefault, generated := columnDefaults(cmd.Def)
 	table.Columns = append(table.Columns, &Column{
-		Name:       cmd.Def.Colname,
-		Type:       *cmd.Def.TypeName,
-		IsNotNull:  cmd.Def.IsNotNull,
-		IsUnsigned: cmd.Def.IsUnsigned,
-		IsArray:    cmd.Def.IsArray,
-		ArrayDims:  cmd.Def.ArrayDims,
-		Length:     cmd.Def.Length,
+		Name:        cmd.Def.Colname,
+		Type:        *cmd.Def.TypeName,
+		IsNotNull:   cmd.Def.IsNotNull,
+		IsUnsigned:  cmd.Def.IsUnsigned,
+		IsArray:     cmd.Def.IsArray,
+		ArrayDims:   cmd.Def.ArrayDims,
+		Length:      cmd.Def.Length,
+		HasDefault:  hasDefault,
+		IsGenerated: generated,
 	})
 	return nil
 }
 
+func (table *Table) addConstraint(cmd *ast.AlterTableCmd) {
+	if cmd.Constraint == nil || cmd.Constraint.Contype != ast.CONSTR_PRIMARY || cmd.Constraint.Keys == nil {
+		return
+	}
+	table.PrimaryKey = nil
+	for _, key := range cmd.Constraint.Keys.Items {
+		if name, ok := key.(*ast.String); ok {
+			table.PrimaryKey = append(table.PrimaryKey, name.Str)
+		}
+	}
+}
+
 func (table *Table) alterColumnType(cmd *ast.AlterTableCmd) error {
 	index, err := table.isExistColumn(cmd)
 	if err != nil {
@@ -83,6 +100,13 @@ func (table *Table) dropColumn(cmd *ast.AlterTableCmd) error {
 		return err
 	}
 	if index >= 0 {
+		// Dropping a column of the primary key drops the primary key.
+		for _, key := range table.PrimaryKey {
+			if key == table.Columns[index].Name {
+				table.PrimaryKey = nil
+				break
+			}
+		}
 		table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
 	}
 	return nil
@@ -122,6 +146,48 @@ type Column struct {
 	ArrayDims  int
 	Comment    string
 	Length     *int
+	// HasDefault is set for columns with a default value, including serial
+	// and identity columns.
+	HasDefault bool
+	// IsGenerated is set for generated columns and GENERATED ALWAYS identity
+	// columns, whose values can't be written.
+	IsGenerated bool
+}
+
+// serialTypes are the PostgreSQL pseudo-types whose columns default to the
+// next value of a sequence.
+var serialTypes = map[string]bool{
+	"smallserial": true,
+	"serial2":     true,
+	"serial":      true,
+	"serial4":     true,
+	"bigserial":   true,
+	"serial8":     true,
+}
+
+// columnDefaults reports whether the column defined by def has a default, and
+// whether it is generated.
+func columnDefaults(def *ast.ColumnDef) (hasDefault, generated bool) {
+	hasDefault = def.TypeName != nil && serialTypes[def.TypeName.Name]
+	if def.Constraints == nil {
+		return hasDefault, false
+	}
+	for _, item := range def.Constraints.Items {
+		c, ok := item.(*ast.Constraint)
+		if !ok {
+			continue
+		}
+		switch c.Contype {
+		case ast.CONSTR_DEFAULT:
+			hasDefault = true
+		case ast.CONSTR_IDENTITY:
+			hasDefault = true
+			generated = generated || c.GeneratedWhen == 'a'
+		case ast.CONSTR_GENERATED:
+			hasDefault, generated = true, true
+		}
+	}
+	return hasDefault, generated
 }
 
 // An interface is used to resolve a circular import between the catalog and compiler packages.
@@ -169,6 +235,8 @@ func isStmtImplemented(stmt *ast.AlterTableStmt) bool {
 				implemented = true
 			case ast.AT_SetNotNull:
 				implemented = true
+			case ast.AT_AddConstraint:
+				implemented = true
 			}
 		}
 	}
@@ -207,6 +275,8 @@ func (c *Catalog) alterTable(stmt *ast.AlterTableStmt) error {
 				if err := table.setNotNull(cmd); err != nil {
 					return err
 				}
+			case ast.AT_AddConstraint:
+				table.addConstraint(cmd)
 			}
 		}
 	}
@@ -257,7 +327,10 @@ func (c *Catalog) createTable(stmt *ast.CreateTableStmt, genModel bool) error {
 
 	coltype := make(map[string]ast.TypeName) // used to check for duplicate column names
 	seen := make(map[string]bool)            // used to check for duplicate column names
-	tbl := Table{Rel: stmt.Name, Comment: stmt.Comment, GenerateModel: genModel}
+	// Copy the name, as later renames must not modify the statement.
+	rel := *stmt.Name
+	tbl := Table{Rel: &rel, Comment: stmt.Comment, GenerateModel: genModel}
+	tbl.PrimaryKey = append(tbl.PrimaryKey, stmt.PrimaryKey...)
 	for _, inheritTable := range stmt.Inherits {
 		t, _, err := schema.getTable(inheritTable)
 		if err != nil {
@@ -306,15 +379,18 @@ func (c *Catalog) createTable(stmt *ast.CreateTableStmt, genModel bool) error {
 				continue
 			}
 
+			hasDefault, generated := columnDefaults(col)
 			tc := &Column{
-				Name:       col.Colname,
-				Type:       *col.TypeName,
-				IsNotNull:  col.IsNotNull,
-				IsUnsigned: col.IsUnsigned,
-				IsArray:    col.IsArray,
-				ArrayDims:  col.ArrayDims,
-				Comment:    col.Comment,
-				Length:     col.Length,
+				Name:        col.Colname,
+				Type:        *col.TypeName,
+				IsNotNull:   col.IsNotNull,
+				IsUnsigned:  col.IsUnsigned,
+				IsArray:     col.IsArray,
+				ArrayDims:   col.ArrayDims,
+				Comment:     col.Comment,
+				Length:      col.Length,
+				HasDefault:  hasDefault,
+				IsGenerated: generated,
 			}
 			if col.Vals != nil {
 				typeName := ast.TypeName{
@@ -384,6 +460,11 @@ func (c *Catalog) renameColumn(stmt *ast.RenameColumnStmt) error {
 		return sqlerr.ColumnNotFound(tbl.Rel.Name, stmt.Col.Name)
 	}
 	tbl.Columns[idx].Name = *stmt.NewName
+	for i, key := range tbl.PrimaryKey {
+		if key == stmt.Col.Name {
+			tbl.PrimaryKey[i] = *stmt.NewName
+		}
+	}
 	return nil
 }
 
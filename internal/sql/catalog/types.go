@@ -28,6 +28,7 @@ func (e *Enum) isType() {
 
 type CompositeType struct {
 	Name    string
+	Columns []*Column
 	Comment string
 }
 
@@ -134,9 +135,18 @@ func (c *Catalog) createCompositeType(stmt *ast.CompositeTypeStmt) error {
 	if _, _, err := schema.getType(stmt.TypeName); err == nil {
 		return sqlerr.TypeExists(tbl.Name)
 	}
-	schema.Types = append(schema.Types, &CompositeType{
+	ct := &CompositeType{
 		Name: stmt.TypeName.Name,
-	})
+	}
+	for _, col := range stmt.Cols {
+		ct.Columns = append(ct.Columns, &Column{
+			Name:      col.Colname,
+			Type:      *col.TypeName,
+			IsArray:   col.IsArray,
+			ArrayDims: col.ArrayDims,
+		})
+	}
+	schema.Types = append(schema.Types, ct)
 	return nil
 }
 
@@ -255,7 +265,8 @@ func (c *Catalog) alterTypeSetSchema(stmt *ast.AlterTypeSetSchemaStmt) error {
 		return err
 	}
 	oldType := *stmt.Type
-	stmt.Type.Schema = *stmt.NewSchema
+	newType := oldType
+	newType.Schema = *stmt.NewSchema
 	newSchema, err := c.getSchema(*stmt.NewSchema)
 	if err != nil {
 		return err
@@ -265,18 +276,18 @@ func (c *Catalog) alterTypeSetSchema(stmt *ast.AlterTypeSetSchemaStmt) error {
 	// schema.
 	// https://www.postgresql.org/docs/current/sql-createtype.html
 	tbl := &ast.TableName{
-		Name: stmt.Type.Name,
+		Name: newType.Name,
 	}
 	if _, _, err := newSchema.getTable(tbl); err == nil {
 		return sqlerr.RelationExists(tbl.Name)
 	}
-	if _, _, err := newSchema.getType(stmt.Type); err == nil {
-		return sqlerr.TypeExists(stmt.Type.Name)
+	if _, _, err := newSchema.getType(&newType); err == nil {
+		return sqlerr.TypeExists(newType.Name)
 	}
 	oldSchema.Types = append(oldSchema.Types[:idx], oldSchema.Types[idx+1:]...)
 	newSchema.Types = append(newSchema.Types, typ)
 
-	// Update all the table columns with the new type
+	// Update all the table and composite type columns with the new type
 	for _, schema := range c.Schemas {
 		for _, table := range schema.Tables {
 			for _, column := range table.Columns {
@@ -285,6 +296,17 @@ func (c *Catalog) alterTypeSetSchema(stmt *ast.AlterTypeSetSchemaStmt) error {
 				}
 			}
 		}
+		for _, typ := range schema.Types {
+			ct, ok := typ.(*CompositeType)
+			if !ok {
+				continue
+			}
+			for _, column := range ct.Columns {
+				if column.Type == oldType {
+					column.Type.Schema = *stmt.NewSchema
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -343,6 +365,7 @@ func (c *Catalog) renameType(stmt *ast.RenameTypeStmt) error {
 	case *CompositeType:
 		schema.Types[idx] = &CompositeType{
 			Name:    newName,
+			Columns: typ.Columns,
 			Comment: typ.Comment,
 		}
 
@@ -358,7 +381,7 @@ func (c *Catalog) renameType(stmt *ast.RenameTypeStmt) error {
 
 	}
 
-	// Update all the table columns with the new type
+	// Update all the table and composite type columns with the new type
 	for _, schema := range c.Schemas {
 		for _, table := range schema.Tables {
 			for _, column := range table.Columns {
@@ -367,6 +390,17 @@ func (c *Catalog) renameType(stmt *ast.RenameTypeStmt) error {
 				}
 			}
 		}
+		for _, typ := range schema.Types {
+			ct, ok := typ.(*CompositeType)
+			if !ok {
+				continue
+			}
+			for _, column := range ct.Columns {
+				if column.Type == *stmt.Type {
+					column.Type.Name = newName
+				}
+			}
+		}
 	}
 
 	return nil
@@ -19,6 +19,12 @@ func (e Embed) Orig() string {
 	return fmt.Sprintf("sqlc.embed(%s)", e.param)
 }
 
+// Len returns the length of the `sqlc.embed(param)` call at the start of s,
+// however it is formatted.
+func (e Embed) Len(s string) int {
+	return callLen(s)
+}
+
 // EmbedSet is a set of Embed instances
 type EmbedSet []*Embed
 
@@ -42,59 +42,115 @@ func isNamedParamSignCast(node ast.Node) bool {
 	return astutils.Join(expr.Name, ".") == "@" && cast
 }
 
-// paramFromFuncCall creates a param from sqlc.n?arg() calls return the
-// parameter and whether the parameter name was specified a best guess as its
-// "source" string representation (used for replacing this function call in the
-// original SQL query)
-func paramFromFuncCall(call *ast.FuncCall) (named.Param, string) {
-	paramName, isConst := flatten(call.Args)
-
-	// origName keeps track of how the parameter was specified in the source SQL
-	origName := paramName
-	if isConst {
-		origName = fmt.Sprintf("'%s'", paramName)
+// fusedOperators lists the operators that PostgreSQL lexes together with a
+// following @ when there is no space in between, as in `a=@a`.
+var fusedOperators = map[string]bool{
+	"=":  true,
+	"<>": true,
+	"!=": true,
+	">":  true,
+	"<=": true,
+	">=": true,
+	"+":  true,
+	"-":  true,
+	"*":  true,
+	"/":  true,
+	"%":  true,
+	"^":  true,
+	"||": true,
+	"<@": true,
+	"@>": true,
+	"&&": true,
+}
+
+// fusedParamSign returns the column reference of a named parameter that the
+// lexer fused into the preceding operator, e.g. `a=@a` is read as the
+// operator `=@` applied to `a` and `a`.
+func fusedParamSign(node ast.Node) (*ast.A_Expr, *ast.ColumnRef, bool) {
+	expr, ok := node.(*ast.A_Expr)
+	if !ok || expr.Lexpr == nil || expr.Name == nil || len(expr.Name.Items) != 1 {
+		return nil, nil, false
+	}
+	if _, ok := expr.Lexpr.(*ast.TODO); ok {
+		return nil, nil, false
+	}
+	op := astutils.Join(expr.Name, ".")
+	if !strings.HasSuffix(op, "@") || !fusedOperators[strings.TrimSuffix(op, "@")] {
+		return nil, nil, false
 	}
+	switch n := expr.Rexpr.(type) {
+	case *ast.ColumnRef:
+		return expr, n, true
+	case *ast.TypeCast:
+		if ref, ok := n.Arg.(*ast.ColumnRef); ok {
+			return expr, ref, true
+		}
+	}
+	return nil, nil, false
+}
 
-	var param named.Param
+// paramFromFuncCall creates a param from sqlc.n?arg() calls
+func paramFromFuncCall(call *ast.FuncCall) named.Param {
+	paramName, _ := flatten(call.Args)
 	switch call.Func.Name {
 	case "narg":
-		param = named.NewUserNullableParam(paramName)
+		return named.NewUserNullableParam(paramName)
 	case "slice":
-		param = named.NewSqlcSlice(paramName)
+		return named.NewSqlcSlice(paramName)
 	default:
-		param = named.NewParam(paramName)
+		return named.NewParam(paramName)
 	}
+}
 
-	// TODO: This code assumes that sqlc.arg(name) / sqlc.narg(name) is on a single line
-	// with no extraneous spaces (or any non-significant tokens for that matter)
-	// except between the function name and argument
-	funcName := call.Func.Schema + "." + call.Func.Name
-	spaces := ""
-	if call.Args != nil && len(call.Args.Items) > 0 {
-		leftParen := call.Args.Items[0].Pos() - 1
-		spaces = strings.Repeat(" ", leftParen-call.Location-len(funcName))
+// sliceIns returns the `IN (sqlc.slice(name))` expressions of a statement,
+// by the sqlc.slice call they consist of.
+func sliceIns(raw *ast.RawStmt) map[*ast.FuncCall]*ast.A_Expr {
+	ins := map[*ast.FuncCall]*ast.A_Expr{}
+	found := astutils.Search(raw, func(node ast.Node) bool {
+		expr, ok := node.(*ast.A_Expr)
+		return ok && expr.Kind == ast.A_Expr_Kind_IN
+	})
+	for _, node := range found.Items {
+		expr := node.(*ast.A_Expr)
+		list, ok := expr.Rexpr.(*ast.List)
+		if !ok || len(list.Items) != 1 || !named.IsParamFunc(list.Items[0]) {
+			continue
+		}
+		if fun := list.Items[0].(*ast.FuncCall); fun.Func.Name == "slice" {
+			ins[fun] = expr
+		}
 	}
-	origText := fmt.Sprintf("%s%s(%s)", funcName, spaces, origName)
-	return param, origText
+	return ins
 }
 
 func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool, dollar bool) (*ast.RawStmt, *named.ParamSet, []source.Edit) {
 	foundFunc := astutils.Search(raw, named.IsParamFunc)
 	foundSign := astutils.Search(raw, named.IsParamSign)
+	foundFused := astutils.Search(raw, func(node ast.Node) bool {
+		_, _, ok := fusedParamSign(node)
+		return ok
+	})
 	hasNamedParameterSupport := engine != config.EngineMySQL
 	allParams := named.NewParamSet(numbs, hasNamedParameterSupport)
 
-	if len(foundFunc.Items)+len(foundSign.Items) == 0 {
+	if len(foundFunc.Items)+len(foundSign.Items)+len(foundFused.Items) == 0 {
 		return raw, allParams, nil
 	}
 
+	// PostgreSQL binds a sqlc.slice() as a single array parameter, so the IN
+	// list it stands in is compared with = ANY, or <> ALL for NOT IN, instead.
+	var ins map[*ast.FuncCall]*ast.A_Expr
+	if engine == config.EnginePostgreSQL && dollar {
+		ins = sliceIns(raw)
+	}
+
 	var edits []source.Edit
 	node := astutils.Apply(raw, func(cr *astutils.Cursor) bool {
 		node := cr.Node()
 		switch {
 		case named.IsParamFunc(node):
 			fun := node.(*ast.FuncCall)
-			param, origText := paramFromFuncCall(fun)
+			param := paramFromFuncCall(fun)
 			argn := allParams.Add(param)
 			cr.Replace(&ast.ParamRef{
 				Number:   argn,
@@ -118,9 +174,22 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 				replace = fmt.Sprintf("$%d", argn)
 			}
 
+			if in, ok := ins[fun]; ok {
+				op := "= ANY"
+				if astutils.Join(in.Name, ".") == "<>" {
+					op = "<> ALL"
+				}
+				edits = append(edits, source.Edit{
+					Location: in.Location - raw.StmtLocation,
+					OldFunc:  callLen,
+					New:      op + "(" + replace + ")",
+				})
+				return false
+			}
+
 			edits = append(edits, source.Edit{
 				Location: fun.Location - raw.StmtLocation,
-				Old:      origText,
+				OldFunc:  callLen,
 				New:      replace,
 			})
 			return false
@@ -129,6 +198,10 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 			expr := node.(*ast.A_Expr)
 			cast := expr.Rexpr.(*ast.TypeCast)
 			paramName, _ := flatten(cast.Arg)
+			ident := -1
+			if ref, ok := cast.Arg.(*ast.ColumnRef); ok {
+				ident = ref.Location - expr.Location
+			}
 			param := named.NewParam(paramName)
 
 			argn := allParams.Add(param)
@@ -138,7 +211,6 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 			}
 			cr.Replace(cast)
 
-			// TODO: This code assumes that @foo::bool is on a single line
 			var replace string
 			if engine == config.EngineMySQL || !dollar {
 				replace = "?"
@@ -150,7 +222,7 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 
 			edits = append(edits, source.Edit{
 				Location: expr.Location - raw.StmtLocation,
-				Old:      fmt.Sprintf("@%s", paramName),
+				OldFunc:  signLen(ident),
 				New:      replace,
 			})
 			return false
@@ -158,6 +230,10 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 		case named.IsParamSign(node):
 			expr := node.(*ast.A_Expr)
 			paramName, _ := flatten(expr.Rexpr)
+			ident := -1
+			if ref, ok := expr.Rexpr.(*ast.ColumnRef); ok {
+				ident = ref.Location - expr.Location
+			}
 			param := named.NewParam(paramName)
 
 			argn := allParams.Add(param)
@@ -166,7 +242,6 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 				Location: expr.Location,
 			})
 
-			// TODO: This code assumes that @foo is on a single line
 			var replace string
 			if engine == config.EngineMySQL || !dollar {
 				replace = "?"
@@ -178,12 +253,47 @@ func NamedParameters(engine config.Engine, raw *ast.RawStmt, numbs map[int]bool,
 
 			edits = append(edits, source.Edit{
 				Location: expr.Location - raw.StmtLocation,
-				Old:      fmt.Sprintf("@%s", paramName),
+				OldFunc:  signLen(ident),
 				New:      replace,
 			})
 			return false
 
 		default:
+			expr, ref, ok := fusedParamSign(node)
+			if !ok {
+				return true
+			}
+			op := astutils.Join(expr.Name, ".")
+			sign := expr.Location + len(op) - 1
+			paramName, _ := flatten(ref)
+			param := named.NewParam(paramName)
+
+			argn := allParams.Add(param)
+			paramRef := &ast.ParamRef{
+				Number:   argn,
+				Location: sign,
+			}
+			expr.Name = &ast.List{Items: []ast.Node{&ast.String{Str: strings.TrimSuffix(op, "@")}}}
+			if cast, ok := expr.Rexpr.(*ast.TypeCast); ok {
+				cast.Arg = paramRef
+			} else {
+				expr.Rexpr = paramRef
+			}
+
+			var replace string
+			if engine == config.EngineMySQL || !dollar {
+				replace = "?"
+			} else if engine == config.EngineSQLite {
+				replace = fmt.Sprintf("?%d", argn)
+			} else {
+				replace = fmt.Sprintf("$%d", argn)
+			}
+
+			edits = append(edits, source.Edit{
+				Location: sign - raw.StmtLocation,
+				OldFunc:  signLen(ref.Location - sign),
+				New:      replace,
+			})
 			return true
 		}
 	}, nil)
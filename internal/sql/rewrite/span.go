@@ -0,0 +1,254 @@
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/sqlc/internal/source"
+	"github.com/sqlc-dev/sqlc/internal/sql/sqlerr"
+)
+
+// The helpers below measure tokens directly in the query text, starting at a
+// location reported by the parser, so that edits replace exactly the source
+// span of a node no matter how it is formatted.
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c == '$' || ('0' <= c && c <= '9')
+}
+
+func isOperatorChar(c byte) bool {
+	return strings.IndexByte("+-*/<>=~!@#%^&|`?", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// skipLiteral returns the end of the string literal, quoted identifier,
+// dollar-quoted string or comment starting at s[i], or i if there is none.
+func skipLiteral(s string, i int) int {
+	switch {
+	case strings.HasPrefix(s[i:], "--"):
+		if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(s)
+
+	case strings.HasPrefix(s[i:], "/*"):
+		depth := 0
+		for j := i; j < len(s)-1; j++ {
+			switch s[j : j+2] {
+			case "/*":
+				depth++
+				j++
+			case "*/":
+				depth--
+				j++
+				if depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return len(s)
+
+	case s[i] == '\'' || s[i] == '"':
+		quote := s[i]
+		escapes := quote == '\'' && i > 0 && (s[i-1] == 'e' || s[i-1] == 'E') &&
+			(i == 1 || !isIdentChar(s[i-2]))
+		for j := i + 1; j < len(s); j++ {
+			switch {
+			case escapes && s[j] == '\\':
+				j++
+			case s[j] == quote && j+1 < len(s) && s[j+1] == quote:
+				j++
+			case s[j] == quote:
+				return j + 1
+			}
+		}
+		return len(s)
+
+	case s[i] == '$' && (i == 0 || !isIdentChar(s[i-1])):
+		j := i + 1
+		for j < len(s) && isIdentChar(s[j]) && s[j] != '$' {
+			if j == i+1 && !isIdentStart(s[j]) {
+				return i
+			}
+			j++
+		}
+		if j >= len(s) || s[j] != '$' {
+			return i
+		}
+		tag := s[i : j+1]
+		if end := strings.Index(s[j+1:], tag); end >= 0 {
+			return j + 1 + end + len(tag)
+		}
+		return len(s)
+	}
+	return i
+}
+
+// skipSpace returns the position of the first token at or after s[i],
+// skipping whitespace and comments.
+func skipSpace(s string, i int) int {
+	for i < len(s) {
+		switch {
+		case isSpace(s[i]):
+			i++
+		case strings.HasPrefix(s[i:], "--"), strings.HasPrefix(s[i:], "/*"):
+			i = skipLiteral(s, i)
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// identLen returns the length of the (possibly quoted) identifier at the
+// start of s.
+func identLen(s string) int {
+	if s == "" {
+		return 0
+	}
+	if s[0] == '"' {
+		return skipLiteral(s, 0)
+	}
+	i := 0
+	for i < len(s) && isIdentChar(s[i]) {
+		i++
+	}
+	return i
+}
+
+// signLen returns the length of the `@name` token at the start of s, where
+// the name begins at offset ident. Whitespace and comments may separate the
+// sign from the name.
+func signLen(ident int) func(string) int {
+	return func(s string) int {
+		i := ident
+		if i <= 0 || i > len(s) {
+			i = skipSpace(s, 1)
+		}
+		return i + identLen(s[i:])
+	}
+}
+
+// callLen returns the length of the function call at the start of s, up to
+// and including the parenthesis that closes its argument list.
+func callLen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		if j := skipLiteral(s, i); j > i {
+			i = j - 1
+			continue
+		}
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// SplitParamSigns returns sql with a space inserted between `=` and a named
+// parameter that directly follows it. PostgreSQL lexes `=@` as a single
+// operator, which the parser accepts in an expression but not as the `=` of
+// a SET clause, as in `SET title=@title`. The returned offsets are the
+// positions of the inserted spaces in the returned text, in increasing order.
+func SplitParamSigns(sql string) (string, []int) {
+	var b strings.Builder
+	var splits []int
+	last := 0
+	for i := 0; i+2 < len(sql); i++ {
+		if j := skipLiteral(sql, i); j > i {
+			i = j - 1
+			continue
+		}
+		if sql[i] != '=' || sql[i+1] != '@' || !(isIdentStart(sql[i+2]) || sql[i+2] == '"') {
+			continue
+		}
+		// The = of an operator such as <= or => is left alone.
+		if i > 0 && isOperatorChar(sql[i-1]) {
+			continue
+		}
+		b.WriteString(sql[last : i+1])
+		splits = append(splits, b.Len())
+		b.WriteByte(' ')
+		last = i + 1
+	}
+	if len(splits) == 0 {
+		return sql, nil
+	}
+	b.WriteString(sql[last:])
+	return b.String(), splits
+}
+
+// NotReplaced reports the first `@name` or `sqlc.*` token in sql that is not
+// covered by one of the edits. Such a token would otherwise end up verbatim
+// in the generated query. The returned error is positioned at offset+loc,
+// where offset is the location of sql in its file.
+func NotReplaced(sql string, offset int, edits []source.Edit) error {
+	covered := func(loc int) bool {
+		for _, edit := range edits {
+			if edit.Location < 0 || edit.Location > len(sql) {
+				continue
+			}
+			n := len(edit.Old)
+			if edit.OldFunc != nil {
+				n = edit.OldFunc(sql[edit.Location:])
+			}
+			if edit.Location <= loc && loc < edit.Location+n {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < len(sql); i++ {
+		if j := skipLiteral(sql, i); j > i {
+			i = j - 1
+			continue
+		}
+		var token string
+		switch {
+		case sql[i] == '@':
+			if i+1 == len(sql) || !(isIdentStart(sql[i+1]) || sql[i+1] == '"') {
+				continue
+			}
+			// @ preceded by other operator characters is either an operator
+			// such as <@ or @@, or a named parameter fused to an operator.
+			start := i
+			for start > 0 && isOperatorChar(sql[start-1]) {
+				start--
+			}
+			if start < i && !fusedOperators[sql[start:i]] {
+				continue
+			}
+			token = sql[i : i+1+identLen(sql[i+1:])]
+		case (sql[i] == 's' || sql[i] == 'S') && (i == 0 || !isIdentChar(sql[i-1])):
+			if !strings.EqualFold(sql[i:min(i+4, len(sql))], "sqlc") {
+				continue
+			}
+			dot := skipSpace(sql, i+4)
+			if dot < len(sql) && sql[dot] == '.' {
+				name := skipSpace(sql, dot+1)
+				token = "sqlc." + sql[name:name+identLen(sql[name:])]
+			}
+		}
+		if token == "" || covered(i) {
+			continue
+		}
+		return &sqlerr.Error{
+			Message:  fmt.Sprintf("named parameter %s could not be rewritten; check the syntax around it", token),
+			Location: offset + i,
+		}
+	}
+	return nil
+}
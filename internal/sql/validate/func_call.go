@@ -22,6 +22,23 @@ func (v *funcCallVisitor) Visit(node ast.Node) astutils.Visitor {
 		return nil
 	}
 
+	// unnest with several arrays is only allowed in FROM, where it is
+	// expanded to one unnest call per array, so only its arguments are
+	// validated.
+	if rf, ok := node.(*ast.RangeFunction); ok && rf.Functions != nil {
+		for _, item := range rf.Functions.Items {
+			if list, ok := item.(*ast.List); ok && len(list.Items) > 0 {
+				item = list.Items[0]
+			}
+			if call, ok := item.(*ast.FuncCall); ok && IsMultiUnnest(call) {
+				astutils.Walk(v, call.Args)
+				continue
+			}
+			astutils.Walk(v, item)
+		}
+		return nil
+	}
+
 	call, ok := node.(*ast.FuncCall)
 	if !ok {
 		return v
@@ -73,6 +90,14 @@ func (v *funcCallVisitor) Visit(node ast.Node) astutils.Visitor {
 	return nil
 }
 
+// IsMultiUnnest reports whether call is unnest with more than one array.
+func IsMultiUnnest(call *ast.FuncCall) bool {
+	if call.Func == nil || call.Func.Name != "unnest" || call.Args == nil {
+		return false
+	}
+	return (call.Func.Schema == "" || call.Func.Schema == "pg_catalog") && len(call.Args.Items) > 1
+}
+
 func FuncCall(c *catalog.Catalog, cs config.CombinedSettings, n ast.Node) error {
 	visitor := funcCallVisitor{catalog: c, settings: cs}
 	astutils.Walk(&visitor, n)
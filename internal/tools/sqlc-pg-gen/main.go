@@ -75,10 +75,28 @@ var funcs{{.GenFnName}} = []*catalog.Function {
 	},
 	{{- end}}
 }
+{{- if .Operators }}
+
+var ops{{.GenFnName}} = []*catalog.Operator {
+    {{- range .Operators}}
+	{
+		Name: "{{.Name}}",
+		{{- if .Left}}
+		Left: &ast.TypeName{Name: "{{.LeftTypeName}}"},
+		{{- end}}
+		Right: &ast.TypeName{Name: "{{.RightTypeName}}"},
+		ReturnType: &ast.TypeName{Name: "{{.ReturnTypeName}}"},
+	},
+	{{- end}}
+}
+{{- end }}
 
 func {{.GenFnName}}() *catalog.Schema {
 	s := &catalog.Schema{Name: "{{ .SchemaName }}"}
 	s.Funcs = funcs{{.GenFnName}}
+	{{- if .Operators }}
+	s.Operators = ops{{.GenFnName}}
+	{{- end }}
 	{{- if .Relations }}
 	s.Tables = []*catalog.Table {
 	    {{- range .Relations }}
@@ -139,6 +157,7 @@ type tmplCtx struct {
 	GenFnName  string
 	SchemaName string
 	Procs      []Proc
+	Operators  []Operator
 	Relations  []Relation
 }
 
@@ -281,6 +300,11 @@ func run(ctx context.Context) error {
 			procs = preserveLegacyCatalogBehavior(procs)
 		}
 
+		operators, err := readOperators(ctx, conn, schema.Name)
+		if err != nil {
+			return err
+		}
+
 		relations, err := readRelations(ctx, conn, schema.Name)
 		if err != nil {
 			return err
@@ -291,6 +315,7 @@ func run(ctx context.Context) error {
 			SchemaName: schema.Name,
 			GenFnName:  schema.GenFnName,
 			Procs:      procs,
+			Operators:  operators,
 			Relations:  relations,
 		}, schema.DestPath)
 
@@ -339,12 +364,22 @@ func run(ctx context.Context) error {
 			return false
 		})
 
+		rows, err = conn.Query(ctx, extensionOperators, extension)
+		if err != nil {
+			return err
+		}
+		operators, err := scanOperators(rows)
+		if err != nil {
+			return err
+		}
+
 		extensionPath := filepath.Join(dir, "contrib", name+".go")
 		err = writeFormattedGo(tmpl, tmplCtx{
 			Pkg:        "contrib",
 			SchemaName: "pg_catalog",
 			GenFnName:  funcName,
 			Procs:      procs,
+			Operators:  operators,
 		}, extensionPath)
 		if err != nil {
 			return fmt.Errorf("error generating extension %s: %w", extension, err)
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// The comparison, arithmetic, concatenation and JSON access operators are
+// resolved by the type checker itself, so only the remaining operators are
+// listed. Postfix operators no longer exist since PostgreSQL 14.
+const operatorFilter = `
+  AND o.oprright <> 0
+  AND o.oprname NOT IN ('=', '<>', '<', '>', '<=', '>=', '+', '-', '*', '/',
+    '%', '^', '||', '->', '->>', '#>', '#>>')
+`
+
+const catalogOperators = `
+SELECT o.oprname,
+  CASE WHEN o.oprleft = 0 THEN '' ELSE format_type(o.oprleft, NULL) END,
+  format_type(o.oprright, NULL),
+  format_type(o.oprresult, NULL)
+FROM pg_catalog.pg_operator o
+LEFT JOIN pg_catalog.pg_namespace n ON n.oid = o.oprnamespace
+WHERE n.nspname::text = $1
+  AND pg_operator_is_visible(o.oid)` + operatorFilter + `
+-- simply order all columns to keep subsequent runs stable
+ORDER BY 1, 2, 3, 4;
+`
+
+const extensionOperators = `
+WITH extension_operators AS (
+  SELECT o.oid
+  FROM pg_catalog.pg_extension AS e
+      INNER JOIN pg_catalog.pg_depend AS d ON (d.refobjid = e.oid)
+      INNER JOIN pg_catalog.pg_operator AS o ON (o.oid = d.objid)
+  WHERE d.deptype = 'e' AND e.extname = $1
+)
+SELECT o.oprname,
+  CASE WHEN o.oprleft = 0 THEN '' ELSE format_type(o.oprleft, NULL) END,
+  format_type(o.oprright, NULL),
+  format_type(o.oprresult, NULL)
+FROM pg_catalog.pg_operator o
+JOIN extension_operators eo ON eo.oid = o.oid
+WHERE pg_operator_is_visible(o.oid)` + operatorFilter + `
+-- simply order all columns to keep subsequent runs stable
+ORDER BY 1, 2, 3, 4;
+`
+
+type Operator struct {
+	Name       string
+	Left       string
+	Right      string
+	ReturnType string
+}
+
+func (o *Operator) LeftTypeName() string {
+	return clean(o.Left)
+}
+
+func (o *Operator) RightTypeName() string {
+	return clean(o.Right)
+}
+
+func (o *Operator) ReturnTypeName() string {
+	return clean(o.ReturnType)
+}
+
+func scanOperators(rows pgx.Rows) ([]Operator, error) {
+	defer rows.Close()
+	var ops []Operator
+	for rows.Next() {
+		var o Operator
+		if err := rows.Scan(&o.Name, &o.Left, &o.Right, &o.ReturnType); err != nil {
+			return nil, err
+		}
+		if o.Left == "internal" || o.Right == "internal" || o.ReturnType == "internal" {
+			continue
+		}
+		ops = append(ops, o)
+	}
+	return ops, rows.Err()
+}
+
+func readOperators(ctx context.Context, conn *pgx.Conn, schemaName string) ([]Operator, error) {
+	rows, err := conn.Query(ctx, catalogOperators, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOperators(rows)
+}